@@ -0,0 +1,14 @@
+// Package assets contains the files embedded in the binary.
+//
+// The files in `fankit` come from the FINAL FANTASY XIV fan kit, and are
+// covered by its own license instead of the license of this repository.
+package assets
+
+import (
+	_ "embed"
+)
+
+// FankitPortrait is a square portrait from the fan kit, as a PNG.
+//
+//go:embed fankit/leWfBmZHbHkObrh1ZH3L6Jhmuc.png
+var FankitPortrait []byte
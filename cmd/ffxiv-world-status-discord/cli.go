@@ -0,0 +1,130 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	iapi "github.com/c032/ffxiv-world-status-discord/interactions-api"
+)
+
+// subcommand is a command of the program, e.g. `serve`.
+type subcommand struct {
+	name        string
+	description string
+	run         func(args []string) int
+}
+
+func subcommands() []subcommand {
+	return []subcommand{
+		{
+			name:        "serve",
+			description: "Serve the interactions API. This is the default.",
+			run:         serveMain,
+		},
+		{
+			name:        "commands",
+			description: "Synchronize, list or delete the registered Discord commands.",
+			run:         commandsMain,
+		},
+		{
+			name:        "status",
+			description: "Print the status of every world.",
+			run:         statusMain,
+		},
+		{
+			name:        "simulate",
+			description: "Send signed interactions to a running server.",
+			run:         simulateMain,
+		},
+		{
+			name:        "exporter",
+			description: "Export the status of every world as Prometheus metrics.",
+			run:         exporterMain,
+		},
+		{
+			name:        "version",
+			description: "Print information about the build.",
+			run:         versionMain,
+		},
+	}
+}
+
+func printUsage(w io.Writer) {
+	fmt.Fprintf(w, "Usage: %s <command> [flags]\n\nCommands:\n", os.Args[0])
+	for _, cmd := range subcommands() {
+		fmt.Fprintf(w, "  %-10s %s\n", cmd.name, cmd.description)
+	}
+	fmt.Fprintf(w, "\nRun `%s <command> -h` for the flags of each command.\n", os.Args[0])
+}
+
+// run runs the subcommand in `args`, and returns its exit code. Without a
+// subcommand, it runs `serve`.
+func run(args []string) int {
+	name := "serve"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		name = args[0]
+		args = args[1:]
+	}
+
+	for _, cmd := range subcommands() {
+		if cmd.name == name {
+			return cmd.run(args)
+		}
+	}
+
+	if name == "help" {
+		printUsage(os.Stdout)
+
+		return 0
+	}
+
+	fmt.Fprintf(os.Stderr, "Unknown command: %#v\n\n", name)
+	printUsage(os.Stderr)
+
+	return 2
+}
+
+// newFlagSet returns the flags of the subcommand `name`, including `-config`.
+func newFlagSet(name string, configPath *string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.StringVar(configPath, "config", os.Getenv("CONFIG_FILE"), "`path` of the configuration file (defaults to $CONFIG_FILE)")
+
+	return fs
+}
+
+// flagsExitCode returns the exit code after failing to parse flags with
+// `err`.
+func flagsExitCode(err error) int {
+	if errors.Is(err, flag.ErrHelp) {
+		return 0
+	}
+
+	return 2
+}
+
+// versionMain prints information about the build.
+func versionMain(args []string) int {
+	fs := flag.NewFlagSet("version", flag.ContinueOnError)
+
+	err := fs.Parse(args)
+	if err != nil {
+		return flagsExitCode(err)
+	}
+
+	info := iapi.BuildVersion()
+
+	fmt.Printf("path: %s\n", info.Path)
+	fmt.Printf("version: %s\n", info.Version)
+	fmt.Printf("go_version: %s\n", info.GoVersion)
+	if info.VCSRevision != "" {
+		fmt.Printf("vcs_revision: %s\n", info.VCSRevision)
+		fmt.Printf("vcs_time: %s\n", info.VCSTime)
+		fmt.Printf("vcs_modified: %t\n", info.VCSModified)
+	}
+
+	return 0
+}
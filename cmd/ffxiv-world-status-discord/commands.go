@@ -0,0 +1,173 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/c032/ffxiv-world-status-discord/config"
+	iapi "github.com/c032/ffxiv-world-status-discord/interactions-api"
+)
+
+// commandsMain manages the commands registered in Discord, without serving
+// interactions. It only needs the ID and token of each application.
+func commandsMain(args []string) int {
+	const usage = "Usage: commands <sync|list|delete> [flags]"
+
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, usage)
+
+		return 2
+	}
+
+	action := args[0]
+	switch action {
+	case "sync", "list", "delete":
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown action: %#v\n%s\n", action, usage)
+
+		return 2
+	}
+
+	var (
+		configPath   string
+		dryRun       bool
+		applications string
+	)
+
+	fs := newFlagSet("commands "+action, &configPath)
+	fs.StringVar(&applications, "application", "", "comma-separated names of the applications to manage (defaults to all of them)")
+	if action != "list" {
+		fs.BoolVar(&dryRun, "dry-run", false, "only print the changes, without applying them")
+	}
+
+	err := fs.Parse(args[1:])
+	if err != nil {
+		return flagsExitCode(err)
+	}
+
+	cfg, err := loadConfig(configPath)
+	if err == nil {
+		err = filterApplications(cfg, applications)
+	}
+	if err == nil {
+		err = cfg.ValidateDiscord()
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+
+		return 1
+	}
+
+	// Logs go to stderr, so they don't mix with the output.
+	log, err := newLogger(os.Stderr, cfg.Log.Format)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+
+		return 1
+	}
+
+	s := &iapi.Server{
+		Logger:        log,
+		DiscordAPIURL: cfg.Discord.APIURL,
+	}
+	for _, appConfig := range cfg.Discord.Applications {
+		s.Applications = append(s.Applications, newApplication(appConfig))
+	}
+
+	err = s.InitializeCommands()
+	if err != nil {
+		log.Error(err)
+
+		return 1
+	}
+	defer s.Cleanup()
+
+	switch action {
+	case "sync":
+		var results []iapi.CommandSyncResult
+		results, err = s.SyncCommands(dryRun)
+		printCommandSyncResults(os.Stdout, results)
+	case "delete":
+		var results []iapi.CommandSyncResult
+		results, err = s.DeleteCommands(dryRun)
+		printCommandSyncResults(os.Stdout, results)
+	case "list":
+		var results []iapi.RegisteredCommands
+		results, err = s.ListCommands()
+		printRegisteredCommands(os.Stdout, results)
+	}
+	if err != nil {
+		log.Error(err)
+
+		return 1
+	}
+
+	return 0
+}
+
+// filterApplications removes the applications of `cfg` not named in the
+// comma-separated list `names`. An empty list keeps every application.
+func filterApplications(cfg *config.Config, names string) error {
+	if names == "" {
+		return nil
+	}
+
+	var wanted []string
+	for _, name := range strings.Split(names, ",") {
+		name = strings.TrimSpace(name)
+		if name != "" {
+			wanted = append(wanted, name)
+		}
+	}
+	for _, name := range wanted {
+		found := slices.ContainsFunc(cfg.Discord.Applications, func(app config.ApplicationConfig) bool {
+			return app.Name == name
+		})
+		if !found {
+			return fmt.Errorf("unknown application: %#v", name)
+		}
+	}
+
+	cfg.Discord.Applications = slices.DeleteFunc(cfg.Discord.Applications, func(app config.ApplicationConfig) bool {
+		return !slices.Contains(wanted, app.Name)
+	})
+
+	return nil
+}
+
+func scopeName(guildID string) string {
+	if guildID == "" {
+		return "global"
+	}
+
+	return "guild " + guildID
+}
+
+func printCommandSyncResults(w io.Writer, results []iapi.CommandSyncResult) {
+	for _, result := range results {
+		fmt.Fprintf(w, "%s (%s):\n", result.Application, scopeName(result.GuildID))
+		if result.Diff.Empty() {
+			fmt.Fprintln(w, "  (no changes)")
+
+			continue
+		}
+
+		fmt.Fprintln(w, "  "+strings.ReplaceAll(result.Diff.String(), "\n", "\n  "))
+	}
+}
+
+func printRegisteredCommands(w io.Writer, results []iapi.RegisteredCommands) {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	defer tw.Flush()
+
+	fmt.Fprintln(tw, "APPLICATION\tSCOPE\tCOMMAND\tID")
+	for _, result := range results {
+		for _, cmd := range result.Commands {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", result.Application, scopeName(result.GuildID), cmd.Name, cmd.ID)
+		}
+	}
+}
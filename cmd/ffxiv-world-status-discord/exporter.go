@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/c032/ffxiv-world-status-discord/metrics"
+)
+
+// exporterMain runs only the poller of the FFXIV API, and exposes the status
+// of every world as Prometheus metrics. It doesn't need Discord credentials.
+func exporterMain(args []string) int {
+	var configPath string
+
+	fs := newFlagSet("exporter", &configPath)
+
+	err := fs.Parse(args)
+	if err != nil {
+		return flagsExitCode(err)
+	}
+
+	cfg, err := loadConfig(configPath)
+	if err == nil {
+		err = cfg.ValidateExporter()
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+
+		return 1
+	}
+
+	log, err := newLogger(os.Stdout, cfg.Log.Format)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+
+		return 1
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	addr := cfg.ExporterListenAddress
+
+	metricsRegistry := metrics.NewRegistry()
+	m := newAPIMetrics(metricsRegistry)
+
+	_, poller, err := createAPIClient(log, cfg, m, nil)
+	if err != nil {
+		log.Error(err)
+
+		return 1
+	}
+	go poller.Run(ctx)
+
+	mux := http.NewServeMux()
+	mux.Handle("GET /metrics", metricsRegistry)
+
+	hs := &http.Server{
+		Addr:    addr,
+		Handler: mux,
+
+		ReadTimeout:       httpServerTimeout,
+		ReadHeaderTimeout: httpServerTimeout,
+		WriteTimeout:      httpServerTimeout,
+		IdleTimeout:       httpServerTimeout,
+	}
+
+	chErr := make(chan error, 1)
+	go func() {
+		log.Printf("Exporter listening on %s", addr)
+
+		chErr <- hs.ListenAndServe()
+	}()
+
+	select {
+	case <-ctx.Done():
+		log.Print("Gracefully shutting down exporter.")
+	case err := <-chErr:
+		if !errors.Is(err, http.ErrServerClosed) {
+			log.Errorf("%s", err.Error())
+
+			return 1
+		}
+	}
+
+	err = hs.Shutdown(context.Background())
+	if err != nil {
+		log.Errorf("Error during exporter shutdown: %s", err)
+
+		return 1
+	}
+
+	return 0
+}
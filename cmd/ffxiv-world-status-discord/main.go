@@ -2,105 +2,356 @@ package main
 
 import (
 	"context"
-	"crypto/ed25519"
-	"encoding/hex"
 	"errors"
 	"fmt"
-	"io/ioutil"
+	"io"
+	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
-	"strings"
 	"syscall"
 	"time"
 
 	logger "github.com/c032/go-logger"
 
+	"github.com/c032/ffxiv-world-status-discord/config"
 	ffxivapi "github.com/c032/ffxiv-world-status-discord/ffxivapi"
 	iapi "github.com/c032/ffxiv-world-status-discord/interactions-api"
+	"github.com/c032/ffxiv-world-status-discord/metrics"
+	"github.com/c032/ffxiv-world-status-discord/tracing"
 )
 
 var (
 	ErrShutdown = errors.New("received signal to shutdown")
 )
 
-func mustReadRequiredEnvironmentVariable(key string) string {
-	value := strings.TrimSpace(os.Getenv(key))
-	if value == "" {
-		err := fmt.Errorf("environment variable %s is missing or empty", key)
+const httpServerTimeout = 60 * time.Second
 
-		panic(err)
+// loadConfig loads the configuration from the file at `path`, if it's not
+// empty, and the environment.
+func loadConfig(path string) (*config.Config, error) {
+	return config.Load(path, os.LookupEnv)
+}
+
+// newApplication creates the Discord application in `appConfig`, without its
+// public keys.
+func newApplication(appConfig config.ApplicationConfig) *iapi.Application {
+	return &iapi.Application{
+		Name:  appConfig.Name,
+		ID:    appConfig.ApplicationID,
+		Token: appConfig.Token,
+
+		Commands:    appConfig.Commands,
+		DevGuildIDs: appConfig.DevGuildIDs,
+
+		SkipRequestValidation: appConfig.SkipRequestValidation,
 	}
+}
+
+// newApplications creates the Discord applications in `cfg`, and the watchers
+// of their public key files.
+func newApplications(log logger.Logger, cfg *config.Config) ([]*iapi.Application, []*iapi.PublicKeyFileWatcher, error) {
+	var (
+		apps              []*iapi.Application
+		publicKeyWatchers []*iapi.PublicKeyFileWatcher
+	)
+
+	for _, appConfig := range cfg.Discord.Applications {
+		app := newApplication(appConfig)
 
-	return value
+		if appConfig.PublicKeyFile != "" {
+			keys, err := iapi.ReadPublicKeysFile(appConfig.PublicKeyFile)
+			if err != nil {
+				return nil, nil, fmt.Errorf("could not read public keys of application %#v: %w", appConfig.Name, err)
+			}
+
+			app.PublicKeys = iapi.NewPublicKeyRing(keys...)
+
+			publicKeyWatchers = append(publicKeyWatchers, &iapi.PublicKeyFileWatcher{
+				Logger: log,
+				Path:   appConfig.PublicKeyFile,
+				Ring:   app.PublicKeys,
+			})
+		}
+
+		apps = append(apps, app)
+	}
+
+	return apps, publicKeyWatchers, nil
 }
 
-func must[T any](v T, err error) T {
+// newAPIClient creates the FFXIV API client, with retries.
+func newAPIClient(cfg *config.Config, observer ffxivapi.Observer, tracer *tracing.Tracer) (ffxivapi.Client, error) {
+	return ffxivapi.NewClient(ffxivapi.ClientOptions{
+		BaseURL:    cfg.FFXIVAPI.URL,
+		Token:      cfg.FFXIVAPI.Token,
+		MaxRetries: ffxivapi.DefaultMaxRetries,
+		Observer:   observer,
+		Tracer:     tracer,
+	})
+}
+
+// createAPIClient creates the FFXIV API client, with retries, a circuit
+// breaker and a cache, and the poller that keeps the cache warm.
+func createAPIClient(log logger.Logger, cfg *config.Config, observer ffxivapi.Observer, tracer *tracing.Tracer) (*ffxivapi.CachedClient, *ffxivapi.Poller, error) {
+	uncachedAPIClient, err := newAPIClient(cfg, observer, tracer)
 	if err != nil {
-		panic(err)
+		return nil, nil, err
+	}
+
+	ac := ffxivapi.NewCachedClient(ffxivapi.NewCircuitBreaker(uncachedAPIClient, ffxivapi.CircuitBreakerOptions{}), time.Duration(cfg.FFXIVAPI.CacheTTL))
+	ac.Observer = observer
+
+	poller := &ffxivapi.Poller{
+		Logger:   log,
+		Cache:    ac,
+		Interval: time.Duration(cfg.FFXIVAPI.PollInterval),
 	}
 
-	return v
+	return ac, poller, nil
 }
 
-func actualMain() int {
-	log := logger.Default()
+// newLogger returns a logger that writes to `w` in the given format, which can
+// be `text` (the default) or `json`.
+func newLogger(w io.Writer, format string) (logger.Logger, error) {
+	var h slog.Handler
+	switch format {
+	case "", "text":
+		h = slog.NewTextHandler(w, nil)
+	case "json":
+		h = slog.NewJSONHandler(w, nil)
+	default:
+		return nil, fmt.Errorf("unsupported log format: %#v", format)
+	}
 
-	rootCtx := context.Background()
-	ctx, cancel := context.WithCancelCause(rootCtx)
+	return logger.FromSlog(slog.New(h)), nil
+}
 
-	var (
-		err error
+// newTracer returns a tracer that exports spans to the configured OTLP
+// collector, or `nil` if there is none.
+func newTracer(log logger.Logger, cfg *config.Config) *tracing.Tracer {
+	endpoint := cfg.Tracing.OTLPEndpoint
+	if endpoint == "" {
+		return nil
+	}
+
+	serviceName := cfg.Tracing.ServiceName
+	if serviceName == "" {
+		serviceName = "ffxiv-world-status-discord"
+	}
+
+	return tracing.NewTracer(&tracing.OTLPExporter{
+		Endpoint:    endpoint,
+		ServiceName: serviceName,
+	}, tracing.TracerOptions{
+		ServiceName: serviceName,
+		Logger:      log,
+	})
+}
 
-		ac ffxivapi.Client
+// reloadableSettings returns the settings of the server in `cfg` that can be
+// changed while it's running.
+func reloadableSettings(cfg *config.Config) iapi.ReloadableSettings {
+	icons := cfg.Discord.StatusIcons
+
+	return iapi.ReloadableSettings{
+		DiscordThumbnailURL: cfg.Discord.ThumbnailURL,
+		StatusIcons: iapi.StatusIcons{
+			Online:                  icons.Online,
+			Maintenance:             icons.Maintenance,
+			Congested:               icons.Congested,
+			Preferred:               icons.Preferred,
+			New:                     icons.New,
+			CharacterCreationLocked: icons.CharacterCreationLocked,
+		},
+		CommandCooldown: time.Duration(cfg.Discord.CommandCooldown),
+		LogPersonalData: cfg.Log.PersonalData,
+		Features: iapi.Features{
+			DisableImages: cfg.Features.DisableImages,
+			DisableANSI:   cfg.Features.DisableANSI,
+		},
+	}
+}
+
+// serveMain runs the interactions API.
+func serveMain(args []string) int {
+	var (
+		configPath   string
+		syncCommands bool
 	)
 
-	apiBaseURL := mustReadRequiredEnvironmentVariable("FFXIV_API_URL")
-	apiToken := mustReadRequiredEnvironmentVariable("FFXIV_API_TOKEN")
+	fs := newFlagSet("serve", &configPath)
+	fs.BoolVar(&syncCommands, "sync-commands", true, "synchronize the registered commands on start")
 
-	ac, err = ffxivapi.NewClient(ffxivapi.ClientOptions{
-		BaseURL: apiBaseURL,
-		Token:   apiToken,
+	err := fs.Parse(args)
+	if err != nil {
+		return flagsExitCode(err)
+	}
+
+	cfg, err := loadConfig(configPath)
+	if err == nil {
+		err = cfg.Validate()
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+
+		return 1
+	}
+
+	templates, err := iapi.NewResponseTemplates(iapi.TemplateOverrides{
+		Default: cfg.Discord.Templates.Default,
+		Guilds:  cfg.Discord.Templates.Guilds,
 	})
 	if err != nil {
-		panic(err)
+		fmt.Fprintf(os.Stderr, "invalid templates: %s\n", err.Error())
+
+		return 1
+	}
+
+	log, err := newLogger(os.Stdout, cfg.Log.Format)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+
+		return 1
+	}
+
+	rootCtx := context.Background()
+	ctx, cancel := context.WithCancelCause(rootCtx)
+	defer cancel(nil)
+
+	metricsRegistry := metrics.NewRegistry()
+	am := newAPIMetrics(metricsRegistry)
+	im := newInteractionMetrics(metricsRegistry)
+
+	tracer := newTracer(log, cfg)
+	go tracer.Run(ctx)
+
+	ac, poller, err := createAPIClient(log, cfg, am, tracer)
+	if err != nil {
+		log.Error(err)
+
+		return 1
+	}
+
+	// The poller keeps running until it's stopped during the shutdown, so
+	// interactions that are being drained still get fresh data.
+	pollerCtx, stopPoller := context.WithCancel(rootCtx)
+	defer stopPoller()
+
+	pollerDone := make(chan struct{})
+	go func() {
+		defer close(pollerDone)
+
+		poller.Run(pollerCtx)
+	}()
+
+	discordApplications, discordPublicKeyWatchers, err := newApplications(log, cfg)
+	if err != nil {
+		log.Error(err)
+
+		return 1
+	}
+	for _, w := range discordPublicKeyWatchers {
+		go w.Run(ctx)
 	}
 
-	rawDiscordPublicKey := strings.TrimSpace(string(must(ioutil.ReadFile(mustReadRequiredEnvironmentVariable("DISCORD_PUBLIC_KEY_FILE")))))
-	discordPublicKeyBytes := must(hex.DecodeString(rawDiscordPublicKey))
-	discordPublicKey := ed25519.PublicKey(discordPublicKeyBytes)
+	var guildSettings iapi.GuildSettingsStore
+	if cfg.GuildSettingsFile != "" {
+		guildSettings, err = iapi.NewFileGuildSettingsStore(cfg.GuildSettingsFile)
+		if err != nil {
+			log.Error(err)
+
+			return 1
+		}
+	}
 
-	discordToken := strings.TrimSpace(string(must(ioutil.ReadFile(mustReadRequiredEnvironmentVariable("DISCORD_TOKEN_FILE")))))
-	discordApplicationID := mustReadRequiredEnvironmentVariable("DISCORD_APPLICATION_ID")
-	addr := mustReadRequiredEnvironmentVariable("INTERACTIONS_API_LISTEN_ADDRESS")
-	skipDiscordRequestValidation := os.Getenv("SKIP_DISCORD_REQUEST_VALIDATION") == "1"
-	discordThumbnailURL := os.Getenv("DISCORD_THUMBNAIL_URL")
+	settings := reloadableSettings(cfg)
 
 	s := &iapi.Server{
 		Logger: log,
 		API:    ac,
 
-		DiscordApplicationID:         discordApplicationID,
-		DiscordPublicKey:             discordPublicKey,
-		DiscordThumbnailURL:          discordThumbnailURL,
-		DiscordToken:                 discordToken,
-		SkipDiscordRequestValidation: skipDiscordRequestValidation,
+		GuildSettings: guildSettings,
+
+		Applications:  discordApplications,
+		DiscordAPIURL: cfg.Discord.APIURL,
+		Metrics:       im,
+		Tracer:        tracer,
+
+		DiscordThumbnailURL: settings.DiscordThumbnailURL,
+		StatusIcons:         settings.StatusIcons,
+		Templates:           templates,
+		CommandSyncDryRun:   cfg.Discord.CommandsSyncDryRun,
+		SkipCommandSync:     !syncCommands,
+
+		CommandCooldown:  settings.CommandCooldown,
+		MaxTimestampSkew: time.Duration(cfg.Discord.MaxTimestampSkew),
+		ReplayWindow:     time.Duration(cfg.Discord.ReplayWindow),
+
+		LogPersonalData: settings.LogPersonalData,
+		Features:        settings.Features,
+		LogHashKey:      []byte(cfg.Log.HashKey),
+	}
+
+	// current is the configuration with the last reloaded settings. It's
+	// only used by `reloadConfig`, which runs in the goroutine that handles
+	// signals; `cfg` is never modified.
+	current := cfg
+
+	// reloadConfig applies the settings that can be changed while running.
+	reloadConfig := func() {
+		next, err := loadConfig(configPath)
+		if err == nil {
+			err = next.Validate()
+		}
+		if err != nil {
+			log.Errorf("Could not reload configuration. Keeping the current one. %s", err.Error())
+
+			return
+		}
+
+		reloaded, restartRequired := current.Reload(next)
+		if restartRequired {
+			log.Print("Some changes to the configuration require a restart, and were ignored.")
+		}
+
+		current = reloaded
+
+		s.Reload(reloadableSettings(current))
+		ac.SetTTL(time.Duration(current.FFXIVAPI.CacheTTL))
+		poller.SetInterval(time.Duration(current.FFXIVAPI.PollInterval))
+
+		log.Print("Configuration reloaded.")
 	}
 
 	err = s.Initialize()
 	if err != nil {
-		panic(err)
+		log.Error(err)
+
+		return 1
 	}
-	defer s.Cleanup()
 
 	chSignals := make(chan os.Signal, 1)
-	signal.Notify(chSignals, os.Interrupt, syscall.SIGTERM)
+	signal.Notify(chSignals, os.Interrupt, syscall.SIGTERM, syscall.SIGHUP)
 	go func() {
 		for s := range chSignals {
 			if s == os.Interrupt {
 				log.Print("Received SIGINT.")
 			} else if s == syscall.SIGTERM {
 				log.Print("Received SIGTERM.")
+			} else if s == syscall.SIGHUP {
+				log.Print("Received SIGHUP. Reloading configuration and public keys.")
+
+				reloadConfig()
+
+				for _, w := range discordPublicKeyWatchers {
+					err := w.Reload()
+					if err != nil {
+						log.Errorf("Could not reload public keys: %s", err.Error())
+					}
+				}
+
+				continue
 			} else {
 				log.Print("Received unexpected signal. Ignoring.")
 
@@ -111,10 +362,8 @@ func actualMain() int {
 		}
 	}()
 
-	const httpServerTimeout = 60 * time.Second
-
 	hs := &http.Server{
-		Addr:    addr,
+		Addr:    cfg.ListenAddress,
 		Handler: s,
 
 		ReadTimeout:       httpServerTimeout,
@@ -124,9 +373,9 @@ func actualMain() int {
 	}
 
 	go func(log logger.Logger, hs *http.Server, cancel context.CancelCauseFunc) {
-		log.Printf("Listening on %s", addr)
+		log.Printf("Listening on %s", hs.Addr)
 
-		err = hs.ListenAndServe()
+		err := hs.ListenAndServe()
 		if err != nil {
 			if errors.Is(err, http.ErrServerClosed) {
 				// Normal shutdown.
@@ -144,14 +393,151 @@ func actualMain() int {
 		}
 	}(log, hs, cancel)
 
+	// Commands are synchronized once the server is listening, so `/readyz`
+	// reports whether they are.
+	go func() {
+		err := s.RunCommandSync(ctx)
+		if err != nil {
+			log.Error(err)
+		}
+	}()
+
+	var adminServer *http.Server
+	if adminAddr := cfg.AdminListenAddress; adminAddr != "" {
+		adminMux := http.NewServeMux()
+		adminMux.Handle("GET /metrics", metricsRegistry)
+
+		adminServer = &http.Server{
+			Addr:    adminAddr,
+			Handler: adminMux,
+
+			ReadTimeout:       httpServerTimeout,
+			ReadHeaderTimeout: httpServerTimeout,
+			WriteTimeout:      httpServerTimeout,
+			IdleTimeout:       httpServerTimeout,
+		}
+
+		go func(log logger.Logger, hs *http.Server, cancel context.CancelCauseFunc) {
+			log.Printf("Admin server listening on %s", adminAddr)
+
+			err := hs.ListenAndServe()
+			if err != nil && !errors.Is(err, http.ErrServerClosed) {
+				log.Errorf("%s", err.Error())
+
+				cancel(ErrShutdown)
+			}
+		}(log, adminServer, cancel)
+	}
+
 	log.Print("Main function is ready. Waiting for interrupts.")
 	<-ctx.Done()
 
-	log.Print("Gracefully shutting down HTTP server.")
+	shutdownTimeout := time.Duration(cfg.ShutdownTimeout)
+	if shutdownTimeout == 0 {
+		shutdownTimeout = defaultShutdownTimeout
+	}
+
+	log.Printf("Gracefully shutting down. Waiting up to %s.", shutdownTimeout)
+
+	shutdownCtx, cancelShutdown := context.WithTimeout(rootCtx, shutdownTimeout)
+	defer cancelShutdown()
+
+	exitCode := 0
+
+	err = runShutdown(shutdownCtx, log, []shutdownStep{
+		{
+			// Makes `/readyz` fail and rejects new interactions with 503,
+			// while the HTTP server is still listening, and waits for the
+			// interactions being handled and their deferred responses.
+			name: "interactions",
+			run:  s.Shutdown,
+		},
+		gracePeriodStep("readiness_grace_period", time.Duration(cfg.ShutdownGracePeriod)),
+		{
+			// Stops accepting requests, and waits for the ones being
+			// handled, e.g. probes.
+			name: "http_server",
+			run: func(ctx context.Context) error {
+				err := hs.Shutdown(ctx)
+				if err != nil {
+					hs.Close()
+
+					return fmt.Errorf("could not shutdown HTTP server: %w", err)
+				}
+
+				return nil
+			},
+		},
+		{
+			name: "poller",
+			run: func(ctx context.Context) error {
+				stopPoller()
+
+				select {
+				case <-pollerDone:
+					return nil
+				case <-ctx.Done():
+					return fmt.Errorf("could not stop poller: %w", ctx.Err())
+				}
+			},
+		},
+		{
+			name: "guild_settings",
+			run: func(ctx context.Context) error {
+				closer, ok := guildSettings.(io.Closer)
+				if !ok {
+					return nil
+				}
+
+				err := closer.Close()
+				if err != nil {
+					return fmt.Errorf("could not close guild settings store: %w", err)
+				}
 
-	err = hs.Shutdown(rootCtx)
+				return nil
+			},
+		},
+		{
+			name: "spans",
+			run: func(ctx context.Context) error {
+				err := tracer.Flush(ctx)
+				if err != nil {
+					return fmt.Errorf("could not export spans: %w", err)
+				}
+
+				return nil
+			},
+		},
+		{
+			// Stopped late, so metrics can still be scraped while
+			// draining.
+			name: "admin_server",
+			run: func(ctx context.Context) error {
+				if adminServer == nil {
+					return nil
+				}
+
+				err := adminServer.Shutdown(ctx)
+				if err != nil {
+					adminServer.Close()
+
+					return fmt.Errorf("could not shutdown admin server: %w", err)
+				}
+
+				return nil
+			},
+		},
+		{
+			// Follow-ups use the Discord sessions, so they are released
+			// last.
+			name: "discord_sessions",
+			run: func(ctx context.Context) error {
+				return s.Cleanup()
+			},
+		},
+	})
 	if err != nil {
-		log.Errorf("Error during HTTP server shutdown: %s", err)
+		exitCode = 1
 	}
 
 	err = ctx.Err()
@@ -178,11 +564,11 @@ func actualMain() int {
 		}
 	}
 
-	return 0
+	return exitCode
 }
 
 func main() {
-	exitCode := actualMain()
+	exitCode := run(os.Args[1:])
 	if exitCode != 0 {
 		os.Exit(exitCode)
 	}
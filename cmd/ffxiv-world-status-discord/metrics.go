@@ -0,0 +1,156 @@
+package main
+
+import (
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+
+	ffxivapi "github.com/c032/ffxiv-world-status-discord/ffxivapi"
+	iapi "github.com/c032/ffxiv-world-status-discord/interactions-api"
+	"github.com/c032/ffxiv-world-status-discord/metrics"
+)
+
+var (
+	_ iapi.InteractionMetrics = (*interactionMetrics)(nil)
+	_ ffxivapi.Observer       = (*apiMetrics)(nil)
+)
+
+func resultLabel(err error) string {
+	if err != nil {
+		return "error"
+	}
+
+	return "success"
+}
+
+func boolGauge(v bool) float64 {
+	if v {
+		return 1
+	}
+
+	return 0
+}
+
+// interactionMetrics exports the measurements of the interactions API as
+// Prometheus metrics.
+type interactionMetrics struct {
+	interactions        *metrics.CounterVec
+	interactionDuration *metrics.HistogramVec
+}
+
+func newInteractionMetrics(r *metrics.Registry) *interactionMetrics {
+	return &interactionMetrics{
+		interactions:        r.NewCounter("ffxiv_world_status_interactions_total", "Interactions handled, by type, command and result.", "type", "command", "result"),
+		interactionDuration: r.NewHistogram("ffxiv_world_status_interaction_duration_seconds", "Time spent handling interactions.", nil, "type", "command"),
+	}
+}
+
+func (m *interactionMetrics) ObserveInteraction(interactionType discordgo.InteractionType, commandName string, duration time.Duration, err error) {
+	m.interactions.Inc(interactionType.String(), commandName, resultLabel(err))
+	m.interactionDuration.Observe(duration.Seconds(), interactionType.String(), commandName)
+}
+
+// apiMetrics exports the measurements of the FFXIV API client, and the status
+// of every world, as Prometheus metrics.
+type apiMetrics struct {
+	requests        *metrics.CounterVec
+	requestErrors   *metrics.CounterVec
+	requestDuration *metrics.HistogramVec
+	retries         *metrics.CounterVec
+	cacheLookups    *metrics.CounterVec
+
+	worlds *worldGauges
+}
+
+func newAPIMetrics(r *metrics.Registry) *apiMetrics {
+	return &apiMetrics{
+		requests:        r.NewCounter("ffxiv_world_status_api_requests_total", "Requests sent to the FFXIV API, including retries.", "endpoint"),
+		requestErrors:   r.NewCounter("ffxiv_world_status_api_request_errors_total", "Requests to the FFXIV API that failed.", "endpoint"),
+		requestDuration: r.NewHistogram("ffxiv_world_status_api_request_duration_seconds", "Latency of requests to the FFXIV API.", nil, "endpoint"),
+		retries:         r.NewCounter("ffxiv_world_status_api_retries_total", "Requests to the FFXIV API that were retried.", "endpoint"),
+		cacheLookups:    r.NewCounter("ffxiv_world_status_api_cache_lookups_total", "Lookups in the FFXIV API cache, by result.", "result"),
+
+		worlds: newWorldGauges(r),
+	}
+}
+
+func (m *apiMetrics) ObserveRequest(endpoint string, duration time.Duration, err error) {
+	m.requests.Inc(endpoint)
+	m.requestDuration.Observe(duration.Seconds(), endpoint)
+
+	if err != nil {
+		m.requestErrors.Inc(endpoint)
+	}
+}
+
+func (m *apiMetrics) ObserveRetry(endpoint string) {
+	m.retries.Inc(endpoint)
+}
+
+func (m *apiMetrics) ObserveCacheLookup(hit bool) {
+	if hit {
+		m.cacheLookups.Inc("hit")
+	} else {
+		m.cacheLookups.Inc("miss")
+	}
+}
+
+func (m *apiMetrics) ObserveWorlds(worlds *ffxivapi.WorldsResponse) {
+	m.worlds.set(worlds)
+}
+
+// worldGauges contains the `ffxiv_world_*` gauges, labeled by world, data
+// center and region.
+type worldGauges struct {
+	online            *metrics.GaugeVec
+	maintenance       *metrics.GaugeVec
+	characterCreation *metrics.GaugeVec
+	congested         *metrics.GaugeVec
+	preferred         *metrics.GaugeVec
+	isNew             *metrics.GaugeVec
+	lastUpdate        *metrics.GaugeVec
+}
+
+func newWorldGauges(r *metrics.Registry) *worldGauges {
+	labels := []string{"world", "datacenter", "region"}
+
+	return &worldGauges{
+		online:            r.NewGauge("ffxiv_world_online", "Whether the world is online.", labels...),
+		maintenance:       r.NewGauge("ffxiv_world_maintenance", "Whether the world is under maintenance.", labels...),
+		characterCreation: r.NewGauge("ffxiv_world_character_creation_available", "Whether new characters can be created in the world.", labels...),
+		congested:         r.NewGauge("ffxiv_world_congested", "Whether the world is congested.", labels...),
+		preferred:         r.NewGauge("ffxiv_world_preferred", "Whether the world is a preferred world.", labels...),
+		isNew:             r.NewGauge("ffxiv_world_new", "Whether the world is new.", labels...),
+		lastUpdate:        r.NewGauge("ffxiv_world_last_update_timestamp_seconds", "Time of the last successful update of the world status."),
+	}
+}
+
+// set replaces the series of every gauge with the worlds of `worlds`. The new
+// values are built first, so scrapes never see the gauges empty.
+func (g *worldGauges) set(worlds *ffxivapi.WorldsResponse) {
+	gauges := []struct {
+		gauge *metrics.GaugeVec
+		value func(w ffxivapi.World) bool
+	}{
+		{g.online, func(w ffxivapi.World) bool { return w.IsOnline }},
+		{g.maintenance, func(w ffxivapi.World) bool { return w.IsMaintenance }},
+		{g.characterCreation, func(w ffxivapi.World) bool { return w.CanCreateNewCharacters }},
+		{g.congested, func(w ffxivapi.World) bool { return w.IsCongested }},
+		{g.preferred, func(w ffxivapi.World) bool { return w.IsPreferred }},
+		{g.isNew, func(w ffxivapi.World) bool { return w.IsNew }},
+	}
+
+	for _, gg := range gauges {
+		values := make([]metrics.GaugeValue, 0, len(worlds.Worlds))
+		for _, w := range worlds.Worlds {
+			values = append(values, metrics.GaugeValue{
+				LabelValues: []string{w.Name, w.Group, w.Region()},
+				Value:       boolGauge(gg.value(w)),
+			})
+		}
+
+		gg.gauge.Replace(values)
+	}
+
+	g.lastUpdate.Set(float64(time.Now().Unix()))
+}
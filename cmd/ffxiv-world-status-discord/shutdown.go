@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	logger "github.com/c032/go-logger"
+)
+
+// defaultShutdownTimeout is used when `SHUTDOWN_TIMEOUT` is not set. It's
+// shorter than the 10 seconds that `docker stop` waits before killing the
+// container.
+const defaultShutdownTimeout = 8 * time.Second
+
+// shutdownStep is a single step of the shutdown sequence.
+type shutdownStep struct {
+	name string
+	run  func(ctx context.Context) error
+}
+
+// gracePeriodStep waits for `d`, or until `ctx` is done, e.g. to give load
+// balancers polling `/readyz` a chance to notice that it fails before the
+// HTTP server stops listening.
+func gracePeriodStep(name string, d time.Duration) shutdownStep {
+	return shutdownStep{
+		name: name,
+		run: func(ctx context.Context) error {
+			if d <= 0 {
+				return nil
+			}
+
+			timer := time.NewTimer(d)
+			defer timer.Stop()
+
+			select {
+			case <-timer.C:
+				return nil
+			case <-ctx.Done():
+				return fmt.Errorf("could not wait for the grace period: %w", ctx.Err())
+			}
+		},
+	}
+}
+
+// runShutdown runs `steps` in order, and logs how long each one took.
+//
+// Every step runs even if a previous one failed, or `ctx` is done, so
+// resources are always released. Steps that wait for something must stop
+// waiting once `ctx` is done.
+func runShutdown(ctx context.Context, log logger.Logger, steps []shutdownStep) error {
+	start := time.Now()
+
+	var errs []error
+	for _, step := range steps {
+		log.WithFields(logger.Fields{
+			"step": step.name,
+		}).Print("Running shutdown step.")
+
+		stepStart := time.Now()
+		err := step.run(ctx)
+
+		// Fields are not added to the previous logger, because some
+		// implementations of `WithFields` replace them instead.
+		stepLog := log.WithFields(logger.Fields{
+			"step":        step.name,
+			"duration_ms": time.Since(stepStart).Milliseconds(),
+		})
+		if err != nil {
+			stepLog.Errorf("Shutdown step failed: %s", err.Error())
+
+			errs = append(errs, err)
+
+			continue
+		}
+
+		stepLog.Print("Shutdown step finished.")
+	}
+
+	log.WithFields(logger.Fields{
+		"duration_ms": time.Since(start).Milliseconds(),
+	}).Print("Shutdown finished.")
+
+	return errors.Join(errs...)
+}
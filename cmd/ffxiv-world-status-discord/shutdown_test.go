@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	logger "github.com/c032/go-logger"
+)
+
+func TestRunShutdown(t *testing.T) {
+	errStep := errors.New("step failed")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var ran []string
+	step := func(name string, err error) shutdownStep {
+		return shutdownStep{
+			name: name,
+			run: func(ctx context.Context) error {
+				ran = append(ran, name)
+
+				return err
+			},
+		}
+	}
+
+	err := runShutdown(ctx, logger.Discard, []shutdownStep{
+		step("first", nil),
+		step("second", errStep),
+		step("third", nil),
+	})
+	if !errors.Is(err, errStep) {
+		t.Errorf("err = %v; want %v", err, errStep)
+	}
+
+	if want := []string{"first", "second", "third"}; !slices.Equal(ran, want) {
+		t.Errorf("ran = %v; want %v", ran, want)
+	}
+}
+
+func TestGracePeriodStep(t *testing.T) {
+	const gracePeriod = 50 * time.Millisecond
+
+	start := time.Now()
+	err := gracePeriodStep("grace", gracePeriod).run(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < gracePeriod {
+		t.Errorf("waited %s; want at least %s", elapsed, gracePeriod)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err = gracePeriodStep("grace", time.Hour).run(ctx)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("err = %v; want %v", err, context.Canceled)
+	}
+
+	err = gracePeriodStep("grace", 0).run(ctx)
+	if err != nil {
+		t.Errorf("err without grace period = %v; want nil", err)
+	}
+}
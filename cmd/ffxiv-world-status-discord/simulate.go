@@ -0,0 +1,191 @@
+package main
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/c032/ffxiv-world-status-discord/simulator"
+)
+
+// stringsFlag is a flag that can be repeated.
+type stringsFlag []string
+
+func (f *stringsFlag) String() string {
+	return strings.Join(*f, ",")
+}
+
+func (f *stringsFlag) Set(value string) error {
+	*f = append(*f, value)
+
+	return nil
+}
+
+// simulateMain sends interactions to a running server, signed with a
+// development key, and prints the responses.
+func simulateMain(args []string) int {
+	const usage = "Usage: simulate <keygen|ping|command|autocomplete|component> [flags]"
+
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, usage)
+
+		return 2
+	}
+
+	action := args[0]
+	switch action {
+	case "keygen", "ping", "command", "autocomplete", "component":
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown action: %#v\n%s\n", action, usage)
+
+		return 2
+	}
+
+	var (
+		keyPath string
+		url     string
+		dm      bool
+
+		name     string
+		options  stringsFlag
+		focused  string
+		customID string
+		values   stringsFlag
+	)
+
+	ic := simulator.DefaultContext
+
+	fs := flag.NewFlagSet("simulate "+action, flag.ContinueOnError)
+	fs.StringVar(&keyPath, "key", "discord-dev.key", "`path` of the private key; it's generated if it doesn't exist")
+	if action != "keygen" {
+		fs.StringVar(&url, "url", "http://localhost:8000/interactions", "interactions endpoint of the server")
+		fs.StringVar(&ic.ApplicationID, "application-id", ic.ApplicationID, "ID of the application")
+		fs.StringVar(&ic.GuildID, "guild-id", ic.GuildID, "ID of the guild")
+		fs.StringVar(&ic.UserID, "user-id", ic.UserID, "ID of the user")
+		fs.Int64Var(&ic.Permissions, "permissions", ic.Permissions, "permissions of the user in the guild")
+		fs.Func("locale", "locale of the user (default \"en-US\")", func(value string) error {
+			ic.Locale = discordgo.Locale(value)
+
+			return nil
+		})
+		fs.BoolVar(&dm, "dm", false, "send the interaction from a DM instead of a guild")
+	}
+	switch action {
+	case "command", "autocomplete":
+		fs.StringVar(&name, "name", "", "name of the command")
+		fs.Var(&options, "option", "option of the command, as `name=value`; can be repeated")
+	}
+	switch action {
+	case "autocomplete":
+		fs.StringVar(&focused, "focused", "", "name of the option being autocompleted")
+	case "component":
+		fs.StringVar(&customID, "custom-id", "", "custom ID of the component")
+		fs.Var(&values, "value", "selected value of a select menu; can be repeated")
+	}
+
+	err := fs.Parse(args[1:])
+	if err != nil {
+		return flagsExitCode(err)
+	}
+
+	key, generated, err := simulator.LoadOrGenerateKey(keyPath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+
+		return 1
+	}
+	if generated || action == "keygen" {
+		publicKey := key.Public().(ed25519.PublicKey)
+
+		fmt.Fprintf(os.Stderr, "Public key (in %s): %s\n", simulator.PublicKeyFilePath(keyPath), hex.EncodeToString(publicKey))
+		fmt.Fprintln(os.Stderr, "Add it to the public key file of the application to accept the simulated interactions.")
+	}
+	if action == "keygen" {
+		return 0
+	}
+
+	if dm {
+		ic.GuildID = ""
+	}
+
+	var interaction *discordgo.Interaction
+	switch action {
+	case "ping":
+		interaction = ic.Ping()
+	case "command", "autocomplete":
+		if name == "" {
+			fmt.Fprintln(os.Stderr, "-name is required")
+
+			return 2
+		}
+
+		var commandOptions []*discordgo.ApplicationCommandInteractionDataOption
+		for _, option := range options {
+			optionName, rawValue, ok := strings.Cut(option, "=")
+			if !ok {
+				fmt.Fprintf(os.Stderr, "Option %#v must be formatted as `name=value`.\n", option)
+
+				return 2
+			}
+
+			o := simulator.ParseOption(optionName, rawValue)
+			if optionName == focused {
+				// Autocompleted options are always strings while being
+				// typed.
+				o = simulator.Focused(simulator.Option(optionName, rawValue))
+			}
+
+			commandOptions = append(commandOptions, o)
+		}
+
+		if action == "command" {
+			interaction = ic.Command(name, commandOptions...)
+		} else {
+			interaction = ic.Autocomplete(name, commandOptions...)
+		}
+	case "component":
+		if customID == "" {
+			fmt.Fprintln(os.Stderr, "-custom-id is required")
+
+			return 2
+		}
+
+		interaction = ic.Component(customID, values...)
+	}
+
+	c := &simulator.Client{
+		URL: url,
+		Key: key,
+		HTTPClient: &http.Client{
+			Timeout: httpServerTimeout,
+		},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), httpServerTimeout)
+	defer cancel()
+
+	start := time.Now()
+	resp, err := c.Send(ctx, interaction)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+
+		return 1
+	}
+
+	fmt.Printf("HTTP %d %s (%s)\n", resp.StatusCode, http.StatusText(resp.StatusCode), time.Since(start).Round(time.Millisecond))
+	fmt.Println(resp.Pretty())
+
+	if resp.StatusCode != http.StatusOK {
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,195 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	ffxivapi "github.com/c032/ffxiv-world-status-discord/ffxivapi"
+)
+
+// worldStatus is a row of the output of `status`.
+type worldStatus struct {
+	World                      string `json:"world"`
+	DataCenter                 string `json:"datacenter"`
+	Region                     string `json:"region"`
+	Status                     string `json:"status"`
+	Online                     bool   `json:"online"`
+	Maintenance                bool   `json:"maintenance"`
+	CharacterCreationAvailable bool   `json:"character_creation_available"`
+	Congested                  bool   `json:"congested"`
+	Preferred                  bool   `json:"preferred"`
+	New                        bool   `json:"new"`
+}
+
+func newWorldStatus(w ffxivapi.World) worldStatus {
+	return worldStatus{
+		World:                      w.Name,
+		DataCenter:                 w.Group,
+		Region:                     w.Region(),
+		Status:                     w.ServerStatus,
+		Online:                     w.IsOnline,
+		Maintenance:                w.IsMaintenance,
+		CharacterCreationAvailable: w.CanCreateNewCharacters,
+		Congested:                  w.IsCongested,
+		Preferred:                  w.IsPreferred,
+		New:                        w.IsNew,
+	}
+}
+
+var worldStatusColumns = []string{
+	"world",
+	"datacenter",
+	"region",
+	"status",
+	"online",
+	"maintenance",
+	"character_creation_available",
+	"congested",
+	"preferred",
+	"new",
+}
+
+func (ws worldStatus) values(formatBool func(bool) string) []string {
+	return []string{
+		ws.World,
+		ws.DataCenter,
+		ws.Region,
+		ws.Status,
+		formatBool(ws.Online),
+		formatBool(ws.Maintenance),
+		formatBool(ws.CharacterCreationAvailable),
+		formatBool(ws.Congested),
+		formatBool(ws.Preferred),
+		formatBool(ws.New),
+	}
+}
+
+func yesNo(v bool) string {
+	if v {
+		return "yes"
+	}
+
+	return "no"
+}
+
+// writeStatus writes the status of `worlds` to `w` in the given format, which
+// can be `text`, `json` or `csv`.
+func writeStatus(w io.Writer, format string, worlds []ffxivapi.World) error {
+	rows := make([]worldStatus, 0, len(worlds))
+	for _, world := range worlds {
+		rows = append(rows, newWorldStatus(world))
+	}
+
+	switch format {
+	case "text":
+		tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+		for i, column := range worldStatusColumns {
+			if i > 0 {
+				fmt.Fprint(tw, "\t")
+			}
+			fmt.Fprint(tw, column)
+		}
+		fmt.Fprintln(tw)
+
+		for _, row := range rows {
+			for i, value := range row.values(yesNo) {
+				if i > 0 {
+					fmt.Fprint(tw, "\t")
+				}
+				fmt.Fprint(tw, value)
+			}
+			fmt.Fprintln(tw)
+		}
+
+		return tw.Flush()
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+
+		return enc.Encode(rows)
+	case "csv":
+		cw := csv.NewWriter(w)
+
+		err := cw.Write(worldStatusColumns)
+		if err != nil {
+			return err
+		}
+
+		for _, row := range rows {
+			err = cw.Write(row.values(strconv.FormatBool))
+			if err != nil {
+				return err
+			}
+		}
+
+		cw.Flush()
+
+		return cw.Error()
+	default:
+		return fmt.Errorf("unsupported format: %#v", format)
+	}
+}
+
+// statusMain prints the current status of every world. It only needs the
+// FFXIV API.
+func statusMain(args []string) int {
+	var (
+		configPath string
+		format     string
+	)
+
+	fs := newFlagSet("status", &configPath)
+	fs.StringVar(&format, "format", "text", "output format: text, json or csv")
+
+	err := fs.Parse(args)
+	if err != nil {
+		return flagsExitCode(err)
+	}
+
+	switch format {
+	case "text", "json", "csv":
+	default:
+		fmt.Fprintf(os.Stderr, "Unsupported format: %#v\n", format)
+
+		return 2
+	}
+
+	cfg, err := loadConfig(configPath)
+	if err == nil {
+		err = cfg.ValidateAPI()
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+
+		return 1
+	}
+
+	ac, err := newAPIClient(cfg, nil, nil)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+
+		return 1
+	}
+
+	wr, err := ac.Worlds(context.Background())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Could not get the status of worlds: %s\n", err.Error())
+
+		return 1
+	}
+
+	err = writeStatus(os.Stdout, format, wr.Worlds)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	ffxivapi "github.com/c032/ffxiv-world-status-discord/ffxivapi"
+)
+
+var testWorlds = []ffxivapi.World{
+	{
+		Group:                  "Light",
+		Name:                   "Odin",
+		ServerStatus:           "Online",
+		IsOnline:               true,
+		CanCreateNewCharacters: true,
+	},
+	{
+		Group:         "Aether",
+		Name:          "Gilgamesh",
+		ServerStatus:  "Maintenance",
+		IsMaintenance: true,
+		IsCongested:   true,
+	},
+}
+
+func TestWriteStatus_CSV(t *testing.T) {
+	var sb strings.Builder
+
+	err := writeStatus(&sb, "csv", testWorlds)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "" +
+		"world,datacenter,region,status,online,maintenance,character_creation_available,congested,preferred,new\n" +
+		"Odin,Light,Europe,Online,true,false,true,false,false,false\n" +
+		"Gilgamesh,Aether,North America,Maintenance,false,true,false,true,false,false\n"
+	if got := sb.String(); got != want {
+		t.Fatalf("writeStatus(csv) = %q; want %q", got, want)
+	}
+}
+
+func TestWriteStatus_JSON(t *testing.T) {
+	var sb strings.Builder
+
+	err := writeStatus(&sb, "json", testWorlds[:1])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := sb.String(); !strings.Contains(got, `"character_creation_available": true`) || !strings.Contains(got, `"region": "Europe"`) {
+		t.Fatalf("writeStatus(json) = %s", got)
+	}
+}
+
+func TestWriteStatus_Text(t *testing.T) {
+	var sb strings.Builder
+
+	err := writeStatus(&sb, "text", testWorlds)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(sb.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("writeStatus(text) has %d lines; want 3", len(lines))
+	}
+	if !strings.HasPrefix(lines[1], "Odin ") || !strings.Contains(lines[2], "Maintenance") {
+		t.Fatalf("writeStatus(text) = %s", sb.String())
+	}
+}
+
+func TestWriteStatus_UnsupportedFormat(t *testing.T) {
+	err := writeStatus(&strings.Builder{}, "xml", testWorlds)
+	if err == nil {
+		t.Fatal("writeStatus(xml) succeeded; want error")
+	}
+}
@@ -0,0 +1,275 @@
+// Package config loads the configuration of the bot from a JSON or TOML file
+// and environment variables.
+//
+// Every setting can be set in the file, and overridden by the environment
+// variable in its `env` tag. The value of an environment variable `X` can
+// also be read from the file in `X_FILE`. Settings tagged as `secret` can
+// also be read from the systemd credential named like the environment
+// variable, in lowercase (e.g. `$CREDENTIALS_DIRECTORY/ffxiv_api_token`).
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+)
+
+// Duration is a `time.Duration` that is written in configuration files as a
+// string accepted by `time.ParseDuration`, e.g. `"1m30s"`.
+type Duration time.Duration
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+
+	err := json.Unmarshal(data, &s)
+	if err != nil {
+		return fmt.Errorf("duration must be a string: %w", err)
+	}
+
+	parsed, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+
+	*d = Duration(parsed)
+
+	return nil
+}
+
+type Config struct {
+	Log      LogConfig      `json:"log"`
+	FFXIVAPI FFXIVAPIConfig `json:"ffxiv_api"`
+	Discord  DiscordConfig  `json:"discord"`
+	Tracing  TracingConfig  `json:"tracing"`
+	Features FeaturesConfig `json:"features"`
+
+	ListenAddress         string `json:"listen_address" env:"INTERACTIONS_API_LISTEN_ADDRESS"`
+	AdminListenAddress    string `json:"admin_listen_address" env:"ADMIN_LISTEN_ADDRESS"`
+	ExporterListenAddress string `json:"exporter_listen_address" env:"EXPORTER_LISTEN_ADDRESS"`
+
+	GuildSettingsFile string `json:"guild_settings_file" env:"GUILD_SETTINGS_FILE"`
+
+	// ShutdownTimeout is how long in-flight interactions are waited for on
+	// shutdown.
+	ShutdownTimeout Duration `json:"shutdown_timeout" env:"SHUTDOWN_TIMEOUT"`
+
+	// ShutdownGracePeriod is how long the HTTP server keeps listening on
+	// shutdown after `/readyz` starts failing, so load balancers polling it
+	// stop sending requests before the port is closed. It's part of
+	// `ShutdownTimeout`.
+	ShutdownGracePeriod Duration `json:"shutdown_grace_period" env:"SHUTDOWN_GRACE_PERIOD"`
+}
+
+type LogConfig struct {
+	// Format is `text` (the default) or `json`.
+	Format string `json:"format" env:"LOG_FORMAT"`
+
+	PersonalData bool   `json:"personal_data" env:"LOG_PERSONAL_DATA" reload:"true"`
+	HashKey      string `json:"hash_key" env:"LOG_HASH_KEY" secret:"true"`
+}
+
+type FFXIVAPIConfig struct {
+	URL   string `json:"url" env:"FFXIV_API_URL"`
+	Token string `json:"token" env:"FFXIV_API_TOKEN" secret:"true"`
+
+	CacheTTL     Duration `json:"cache_ttl" env:"FFXIV_API_CACHE_TTL" reload:"true"`
+	PollInterval Duration `json:"poll_interval" env:"FFXIV_API_POLL_INTERVAL" reload:"true"`
+}
+
+type DiscordConfig struct {
+	Applications []ApplicationConfig `json:"applications"`
+
+	// APIURL replaces the base URL of the Discord REST API, e.g. to use a
+	// local fake.
+	APIURL string `json:"api_url" env:"DISCORD_API_URL"`
+
+	ThumbnailURL       string            `json:"thumbnail_url" env:"DISCORD_THUMBNAIL_URL" reload:"true"`
+	StatusIcons        StatusIconsConfig `json:"status_icons"`
+	Templates          TemplatesConfig   `json:"templates"`
+	CommandsSyncDryRun bool              `json:"commands_sync_dry_run" env:"DISCORD_COMMANDS_SYNC_DRY_RUN"`
+	CommandCooldown    Duration          `json:"command_cooldown" env:"DISCORD_COMMAND_COOLDOWN" reload:"true"`
+	MaxTimestampSkew   Duration          `json:"max_timestamp_skew" env:"DISCORD_MAX_TIMESTAMP_SKEW"`
+	ReplayWindow       Duration          `json:"replay_window" env:"DISCORD_REPLAY_WINDOW"`
+}
+
+// ApplicationConfig configures a Discord application. The `env` tags are
+// suffixes of the prefix returned by `ApplicationEnvironmentPrefix`.
+type ApplicationConfig struct {
+	Name string `json:"name"`
+
+	ApplicationID string `json:"application_id" env:"APPLICATION_ID"`
+	Token         string `json:"token" env:"TOKEN" secret:"true"`
+	PublicKeyFile string `json:"public_key_file" env:"PUBLIC_KEY_FILE"`
+
+	Commands    []string `json:"commands" env:"COMMANDS"`
+	DevGuildIDs []string `json:"dev_guild_ids" env:"DEV_GUILD_IDS"`
+
+	SkipRequestValidation bool `json:"skip_request_validation" env:"SKIP_REQUEST_VALIDATION"`
+}
+
+// StatusIconsConfig contains the icons shown next to the names of worlds, for
+// each state. Each icon is a Unicode emoji or a custom emoji (`<:name:id>`).
+// Empty icons use the defaults.
+type StatusIconsConfig struct {
+	Online                  string `json:"online" env:"DISCORD_STATUS_ICON_ONLINE" reload:"true"`
+	Maintenance             string `json:"maintenance" env:"DISCORD_STATUS_ICON_MAINTENANCE" reload:"true"`
+	Congested               string `json:"congested" env:"DISCORD_STATUS_ICON_CONGESTED" reload:"true"`
+	Preferred               string `json:"preferred" env:"DISCORD_STATUS_ICON_PREFERRED" reload:"true"`
+	New                     string `json:"new" env:"DISCORD_STATUS_ICON_NEW" reload:"true"`
+	CharacterCreationLocked string `json:"character_creation_locked" env:"DISCORD_STATUS_ICON_CHARACTER_CREATION_LOCKED" reload:"true"`
+}
+
+// TemplatesConfig replaces the templates of responses, by template name. The
+// templates are checked when the bot starts.
+type TemplatesConfig struct {
+	Default map[string]string            `json:"default"`
+	Guilds  map[string]map[string]string `json:"guilds"`
+}
+
+// FeaturesConfig turns off optional features of the commands.
+type FeaturesConfig struct {
+	DisableImages bool `json:"disable_images" env:"FEATURE_DISABLE_IMAGES" reload:"true"`
+	DisableANSI   bool `json:"disable_ansi" env:"FEATURE_DISABLE_ANSI" reload:"true"`
+}
+
+type TracingConfig struct {
+	OTLPEndpoint string `json:"otlp_endpoint" env:"OTEL_EXPORTER_OTLP_ENDPOINT"`
+	ServiceName  string `json:"service_name" env:"OTEL_SERVICE_NAME"`
+}
+
+// DefaultApplicationName is the name of the application configured with the
+// unprefixed environment variables (e.g. `DISCORD_APPLICATION_ID`).
+const DefaultApplicationName = "default"
+
+// ApplicationEnvironmentPrefix returns the prefix of the environment variables
+// that configure the application named `name`.
+func ApplicationEnvironmentPrefix(name string) string {
+	if name == DefaultApplicationName {
+		return "DISCORD_"
+	}
+
+	return "DISCORD_" + strings.ToUpper(strings.ReplaceAll(name, "-", "_")) + "_"
+}
+
+// LookupEnvFunc has the signature of `os.LookupEnv`.
+type LookupEnvFunc func(key string) (string, bool)
+
+// Load reads the configuration file at `path`, if it's not empty, and applies
+// the overrides from the environment.
+//
+// The configuration is not validated.
+func Load(path string, lookupEnv LookupEnvFunc) (*Config, error) {
+	cfg := &Config{}
+
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("could not read configuration file: %w", err)
+		}
+
+		err = decodeFile(path, data, cfg)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse configuration file %#v: %w", path, err)
+		}
+	}
+
+	env := &environment{
+		lookupEnv: lookupEnv,
+	}
+
+	env.apply(cfg, "")
+	env.applyApplications(cfg)
+
+	if len(env.errs) > 0 {
+		return nil, &Errors{Errs: env.errs}
+	}
+
+	return cfg, nil
+}
+
+// decodeFile decodes the configuration file at `path`, with contents `data`,
+// into `cfg`. Files with the `.toml` extension are TOML, and any other file is
+// JSON.
+//
+// TOML files are converted to JSON first, so both formats use the same keys
+// and reject unknown settings in the same way.
+func decodeFile(path string, data []byte, cfg *Config) error {
+	if strings.EqualFold(filepath.Ext(path), ".toml") {
+		var values map[string]any
+
+		err := toml.Unmarshal(data, &values)
+		if err != nil {
+			return err
+		}
+
+		data, err = json.Marshal(values)
+		if err != nil {
+			return err
+		}
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+
+	return dec.Decode(cfg)
+}
+
+// Reload returns a copy of `cfg` with the settings that can be changed while
+// running (tagged with `reload`) taken from `next`.
+//
+// It also returns whether `next` changes other settings, which are ignored
+// and require a restart.
+func (cfg *Config) Reload(next *Config) (*Config, bool) {
+	reloaded := cfg.clone()
+
+	copyReloadable(reflect.ValueOf(reloaded).Elem(), reflect.ValueOf(next).Elem())
+
+	restartRequired := !reflect.DeepEqual(reloaded, next.clone())
+
+	return reloaded, restartRequired
+}
+
+func (cfg *Config) clone() *Config {
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		panic(fmt.Sprintf("config: could not clone configuration: %s", err))
+	}
+
+	clone := &Config{}
+
+	err = json.Unmarshal(data, clone)
+	if err != nil {
+		panic(fmt.Sprintf("config: could not clone configuration: %s", err))
+	}
+
+	return clone
+}
+
+func copyReloadable(dst reflect.Value, src reflect.Value) {
+	rt := dst.Type()
+
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+
+		if field.Type.Kind() == reflect.Struct {
+			copyReloadable(dst.Field(i), src.Field(i))
+
+			continue
+		}
+
+		if field.Tag.Get("reload") == "true" {
+			dst.Field(i).Set(src.Field(i))
+		}
+	}
+}
@@ -0,0 +1,299 @@
+package config_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/c032/ffxiv-world-status-discord/config"
+)
+
+func lookupEnvMap(env map[string]string) config.LookupEnvFunc {
+	return func(key string) (string, bool) {
+		value, ok := env[key]
+
+		return value, ok
+	}
+}
+
+func writeFile(t *testing.T, dir string, name string, content string) string {
+	t.Helper()
+
+	path := filepath.Join(dir, name)
+
+	err := os.WriteFile(path, []byte(content), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func TestLoad_FileAndEnvironment(t *testing.T) {
+	dir := t.TempDir()
+
+	path := writeFile(t, dir, "config.json", `{
+		"listen_address": ":8080",
+		"ffxiv_api": {
+			"url": "https://example.com/api/",
+			"cache_ttl": "30s"
+		},
+		"discord": {
+			"thumbnail_url": "https://example.com/file.png",
+			"applications": [
+				{
+					"name": "main",
+					"application_id": "1",
+					"public_key_file": "/run/secrets/main"
+				}
+			]
+		}
+	}`)
+
+	tokenFile := writeFile(t, dir, "token", "secret-token\n")
+	credentialsDir := filepath.Join(dir, "credentials")
+	err := os.Mkdir(credentialsDir, 0o700)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, credentialsDir, "ffxiv_api_token", "api-token\n")
+
+	cfg, err := config.Load(path, lookupEnvMap(map[string]string{
+		"FFXIV_API_CACHE_TTL":     "2m",
+		"DISCORD_MAIN_TOKEN_FILE": tokenFile,
+		"DISCORD_MAIN_COMMANDS":   "ping, characters",
+		"CREDENTIALS_DIRECTORY":   credentialsDir,
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := time.Duration(cfg.FFXIVAPI.CacheTTL), 2*time.Minute; got != want {
+		t.Errorf("cfg.FFXIVAPI.CacheTTL = %s; want %s", got, want)
+	}
+	if got, want := cfg.FFXIVAPI.Token, "api-token"; got != want {
+		t.Errorf("cfg.FFXIVAPI.Token = %#v; want %#v", got, want)
+	}
+
+	app := cfg.Discord.Applications[0]
+	if got, want := app.Token, "secret-token"; got != want {
+		t.Errorf("app.Token = %#v; want %#v", got, want)
+	}
+	if got, want := strings.Join(app.Commands, ","), "ping,characters"; got != want {
+		t.Errorf("app.Commands = %#v; want %#v", got, want)
+	}
+
+	err = cfg.Validate()
+	if err != nil {
+		t.Errorf("cfg.Validate() = %s; want nil", err)
+	}
+}
+
+func TestLoad_TOML(t *testing.T) {
+	path := writeFile(t, t.TempDir(), "config.toml", `
+listen_address = ":8080"
+
+[ffxiv_api]
+url = "https://example.com/api/"
+cache_ttl = "30s"
+
+[features]
+disable_images = true
+
+[[discord.applications]]
+name = "main"
+application_id = "1"
+dev_guild_ids = ["10", "20"]
+`)
+
+	cfg, err := config.Load(path, lookupEnvMap(map[string]string{
+		"FEATURE_DISABLE_ANSI": "true",
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := cfg.ListenAddress, ":8080"; got != want {
+		t.Errorf("cfg.ListenAddress = %#v; want %#v", got, want)
+	}
+	if got, want := time.Duration(cfg.FFXIVAPI.CacheTTL), 30*time.Second; got != want {
+		t.Errorf("cfg.FFXIVAPI.CacheTTL = %s; want %s", got, want)
+	}
+	if !cfg.Features.DisableImages || !cfg.Features.DisableANSI {
+		t.Errorf("cfg.Features = %#v; want both features disabled", cfg.Features)
+	}
+
+	app := cfg.Discord.Applications[0]
+	if got, want := app.DevGuildIDs, []string{"10", "20"}; !slices.Equal(got, want) {
+		t.Errorf("app.DevGuildIDs = %#v; want %#v", got, want)
+	}
+
+	// Unknown settings are rejected, like in JSON files.
+	path = writeFile(t, t.TempDir(), "config.toml", `unknown_setting = 1`)
+
+	_, err = config.Load(path, lookupEnvMap(nil))
+	if err == nil {
+		t.Fatal("config.Load() = nil error; want an error for an unknown setting")
+	}
+}
+
+func TestLoad_LegacyEnvironment(t *testing.T) {
+	dir := t.TempDir()
+	tokenFile := writeFile(t, dir, "token", "legacy-token")
+
+	cfg, err := config.Load("", lookupEnvMap(map[string]string{
+		"DISCORD_APPLICATION_ID":          "1",
+		"DISCORD_TOKEN_FILE":              tokenFile,
+		"DISCORD_PUBLIC_KEY_FILE":         "/run/secrets/discord_public_key",
+		"SKIP_DISCORD_REQUEST_VALIDATION": "1",
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := len(cfg.Discord.Applications), 1; got != want {
+		t.Fatalf("len(cfg.Discord.Applications) = %d; want %d", got, want)
+	}
+
+	app := cfg.Discord.Applications[0]
+	if got, want := app.Name, config.DefaultApplicationName; got != want {
+		t.Errorf("app.Name = %#v; want %#v", got, want)
+	}
+	if got, want := app.Token, "legacy-token"; got != want {
+		t.Errorf("app.Token = %#v; want %#v", got, want)
+	}
+	if !app.SkipRequestValidation {
+		t.Errorf("app.SkipRequestValidation = false; want true")
+	}
+}
+
+func TestLoad_DevGuildIDs(t *testing.T) {
+	tests := []struct {
+		value string
+		want  []string
+	}{
+		{value: "10, 20", want: []string{"10", "20"}},
+
+		// Empty values register commands globally.
+		{value: "", want: nil},
+		{value: " , ", want: nil},
+	}
+
+	for _, tt := range tests {
+		cfg, err := config.Load("", lookupEnvMap(map[string]string{
+			"DISCORD_APPLICATION_ID": "1",
+			"DISCORD_DEV_GUILD_IDS":  tt.value,
+		}))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got := cfg.Discord.Applications[0].DevGuildIDs; !slices.Equal(got, tt.want) {
+			t.Errorf("DevGuildIDs for %#v = %#v; want %#v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestLoad_InvalidEnvironment(t *testing.T) {
+	_, err := config.Load("", lookupEnvMap(map[string]string{
+		"FFXIV_API_CACHE_TTL":           "soon",
+		"DISCORD_COMMANDS_SYNC_DRY_RUN": "maybe",
+	}))
+
+	var errs *config.Errors
+	if !errors.As(err, &errs) {
+		t.Fatalf("config.Load() error = %v; want *config.Errors", err)
+	}
+	if got, want := len(errs.Errs), 2; got != want {
+		t.Errorf("len(errs.Errs) = %d; want %d\n%s", got, want, err)
+	}
+}
+
+func TestConfig_Validate(t *testing.T) {
+	cfg := &config.Config{
+		FFXIVAPI: config.FFXIVAPIConfig{
+			URL:      "https://example.com/api",
+			CacheTTL: config.Duration(-time.Second),
+		},
+		Discord: config.DiscordConfig{
+			Applications: []config.ApplicationConfig{
+				{Name: "a", ApplicationID: "1", Token: "t", PublicKeyFile: "k"},
+				{Name: "a", ApplicationID: "2", Token: "t", PublicKeyFile: "k"},
+			},
+			StatusIcons: config.StatusIconsConfig{
+				Online:      "✅",
+				Maintenance: "<:maintenance>",
+			},
+			MaxTimestampSkew: config.Duration(10 * time.Minute),
+			ReplayWindow:     config.Duration(time.Minute),
+		},
+		ShutdownTimeout:     config.Duration(5 * time.Second),
+		ShutdownGracePeriod: config.Duration(5 * time.Second),
+	}
+
+	err := cfg.Validate()
+
+	var errs *config.Errors
+	if !errors.As(err, &errs) {
+		t.Fatalf("cfg.Validate() = %v; want *config.Errors", err)
+	}
+
+	for _, want := range []string{
+		`must end with a "/"`,
+		"FFXIV_API_TOKEN",
+		"FFXIV_API_CACHE_TTL",
+		"INTERACTIONS_API_LISTEN_ADDRESS",
+		"is duplicated",
+		"DISCORD_STATUS_ICON_MAINTENANCE",
+		"DISCORD_REPLAY_WINDOW",
+		"SHUTDOWN_GRACE_PERIOD",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("cfg.Validate() does not mention %#v:\n%s", want, err)
+		}
+	}
+}
+
+func TestConfig_Reload(t *testing.T) {
+	cfg := &config.Config{
+		ListenAddress: ":8080",
+		Discord: config.DiscordConfig{
+			ThumbnailURL: "https://example.com/old.png",
+		},
+	}
+
+	next := &config.Config{
+		ListenAddress: ":8080",
+		Discord: config.DiscordConfig{
+			ThumbnailURL: "https://example.com/new.png",
+		},
+		Features: config.FeaturesConfig{
+			DisableImages: true,
+		},
+	}
+
+	reloaded, restartRequired := cfg.Reload(next)
+	if restartRequired {
+		t.Errorf("restartRequired = true; want false")
+	}
+	if got, want := reloaded.Discord.ThumbnailURL, next.Discord.ThumbnailURL; got != want {
+		t.Errorf("reloaded.Discord.ThumbnailURL = %#v; want %#v", got, want)
+	}
+	if !reloaded.Features.DisableImages {
+		t.Errorf("reloaded.Features.DisableImages = false; want true")
+	}
+
+	next.ListenAddress = ":9090"
+
+	reloaded, restartRequired = cfg.Reload(next)
+	if !restartRequired {
+		t.Errorf("restartRequired = false; want true")
+	}
+	if got, want := reloaded.ListenAddress, cfg.ListenAddress; got != want {
+		t.Errorf("reloaded.ListenAddress = %#v; want %#v", got, want)
+	}
+}
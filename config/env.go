@@ -0,0 +1,197 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var durationType = reflect.TypeOf(Duration(0))
+
+// environment applies the overrides from environment variables to a
+// configuration, collecting every error.
+type environment struct {
+	lookupEnv LookupEnvFunc
+	errs      []error
+}
+
+func (env *environment) errorf(format string, v ...any) {
+	env.errs = append(env.errs, fmt.Errorf(format, v...))
+}
+
+// lookup returns the value of the environment variable `key`, read from
+// `key_FILE` or, if `secret` is `true`, from the systemd credentials.
+func (env *environment) lookup(key string, secret bool) (string, bool) {
+	if value, ok := env.lookupEnv(key); ok && strings.TrimSpace(value) != "" {
+		return strings.TrimSpace(value), true
+	}
+
+	if path, ok := env.lookupEnv(key + "_FILE"); ok && path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			env.errorf("%s_FILE: could not read file: %w", key, err)
+
+			return "", false
+		}
+
+		return strings.TrimSpace(string(data)), true
+	}
+
+	if !secret {
+		return "", false
+	}
+
+	credentialsDirectory, ok := env.lookupEnv("CREDENTIALS_DIRECTORY")
+	if !ok || credentialsDirectory == "" {
+		return "", false
+	}
+
+	data, err := os.ReadFile(filepath.Join(credentialsDirectory, strings.ToLower(key)))
+	if err != nil {
+		if !os.IsNotExist(err) {
+			env.errorf("%s: could not read credential: %w", key, err)
+		}
+
+		return "", false
+	}
+
+	return strings.TrimSpace(string(data)), true
+}
+
+// apply sets the fields of the struct pointed by `v` from the environment
+// variables in their `env` tags, prefixed with `prefix`. Nested structs are
+// walked recursively.
+func (env *environment) apply(v any, prefix string) {
+	rv := reflect.ValueOf(v).Elem()
+	rt := rv.Type()
+
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		fv := rv.Field(i)
+
+		if field.Type.Kind() == reflect.Struct {
+			env.apply(fv.Addr().Interface(), prefix)
+
+			continue
+		}
+
+		key := field.Tag.Get("env")
+		if key == "" {
+			continue
+		}
+
+		key = prefix + key
+
+		value, ok := env.lookup(key, field.Tag.Get("secret") == "true")
+		if !ok {
+			continue
+		}
+
+		err := setField(fv, value)
+		if err != nil {
+			env.errorf("%s: %w", key, err)
+		}
+	}
+}
+
+func setField(fv reflect.Value, value string) error {
+	if fv.Type() == durationType {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("invalid duration %#v", value)
+		}
+
+		fv.SetInt(int64(d))
+
+		return nil
+	}
+
+	switch fv.Kind() {
+	case reflect.String:
+		fv.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid boolean %#v", value)
+		}
+
+		fv.SetBool(b)
+	case reflect.Slice:
+		fv.Set(reflect.ValueOf(splitList(value)))
+	default:
+		return fmt.Errorf("unsupported type %s", fv.Type())
+	}
+
+	return nil
+}
+
+// applyApplications applies the overrides of the Discord applications.
+//
+// If `DISCORD_APPLICATIONS` is set, it replaces the list of applications,
+// keeping the settings from the file of the applications it names. If there
+// are no applications in the file nor in `DISCORD_APPLICATIONS`, and
+// `DISCORD_APPLICATION_ID` is set, a single application named `default` is
+// configured from the unprefixed environment variables.
+func (env *environment) applyApplications(cfg *Config) {
+	apps := cfg.Discord.Applications
+
+	if value, ok := env.lookup("DISCORD_APPLICATIONS", false); ok {
+		existing := map[string]ApplicationConfig{}
+		for _, app := range apps {
+			existing[app.Name] = app
+		}
+
+		apps = nil
+		for _, name := range splitList(value) {
+			app, ok := existing[name]
+			if !ok {
+				app = ApplicationConfig{Name: name}
+			}
+
+			apps = append(apps, app)
+		}
+	}
+
+	if len(apps) == 0 {
+		if _, ok := env.lookup("DISCORD_APPLICATION_ID", false); ok {
+			apps = []ApplicationConfig{
+				{Name: DefaultApplicationName},
+			}
+		}
+	}
+
+	for i := range apps {
+		app := &apps[i]
+
+		env.apply(app, ApplicationEnvironmentPrefix(app.Name))
+
+		// Kept for compatibility with configurations that predate
+		// `DISCORD_APPLICATIONS`.
+		if app.Name == DefaultApplicationName {
+			if value, ok := env.lookup("SKIP_DISCORD_REQUEST_VALIDATION", false); ok && value == "1" {
+				app.SkipRequestValidation = true
+			}
+		}
+	}
+
+	cfg.Discord.Applications = apps
+}
+
+// splitList splits a comma-separated list, ignoring empty items.
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		items = append(items, item)
+	}
+
+	return items
+}
@@ -0,0 +1,211 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// Errors contains every problem found in a configuration.
+type Errors struct {
+	Errs []error
+}
+
+func (errs *Errors) Error() string {
+	lines := make([]string, 0, len(errs.Errs)+1)
+	lines = append(lines, "invalid configuration:")
+	for _, err := range errs.Errs {
+		lines = append(lines, "  - "+err.Error())
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func (errs *Errors) Unwrap() []error {
+	return errs.Errs
+}
+
+type validator struct {
+	errs []error
+}
+
+func (v *validator) errorf(format string, args ...any) {
+	v.errs = append(v.errs, fmt.Errorf(format, args...))
+}
+
+func (v *validator) required(name string, value string) {
+	if value == "" {
+		v.errorf("%s is required", name)
+	}
+}
+
+func (v *validator) nonNegative(name string, d Duration) {
+	if d < 0 {
+		v.errorf("%s must not be negative", name)
+	}
+}
+
+var customEmojiRegexp = regexp.MustCompile(`^<a?:\w{2,32}:\d+>$`)
+
+// icon checks that `value` is a Unicode emoji or a custom emoji. Only the
+// format of custom emoji is checked.
+func (v *validator) icon(name string, value string) {
+	if strings.ContainsFunc(value, unicode.IsSpace) {
+		v.errorf("%s must not contain spaces", name)
+
+		return
+	}
+
+	if strings.HasPrefix(value, "<") && !customEmojiRegexp.MatchString(value) {
+		v.errorf("%s must be an emoji or a custom emoji like <:name:id>", name)
+	}
+}
+
+func (v *validator) httpURL(name string, value string) {
+	if value == "" {
+		return
+	}
+
+	u, err := url.Parse(value)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		v.errorf("%s must be an HTTP or HTTPS URL", name)
+	}
+}
+
+func (v *validator) err() error {
+	if len(v.errs) == 0 {
+		return nil
+	}
+
+	return &Errors{Errs: v.errs}
+}
+
+var applicationNameRegexp = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+func (cfg *Config) validateCommon(v *validator) {
+	switch cfg.Log.Format {
+	case "", "text", "json":
+	default:
+		v.errorf("log.format must be \"text\" or \"json\"")
+	}
+
+	v.required("ffxiv_api.url (FFXIV_API_URL)", cfg.FFXIVAPI.URL)
+	v.httpURL("ffxiv_api.url (FFXIV_API_URL)", cfg.FFXIVAPI.URL)
+	if cfg.FFXIVAPI.URL != "" && !strings.HasSuffix(cfg.FFXIVAPI.URL, "/") {
+		v.errorf("ffxiv_api.url (FFXIV_API_URL) must end with a \"/\"")
+	}
+	v.required("ffxiv_api.token (FFXIV_API_TOKEN)", cfg.FFXIVAPI.Token)
+	v.nonNegative("ffxiv_api.cache_ttl (FFXIV_API_CACHE_TTL)", cfg.FFXIVAPI.CacheTTL)
+	v.nonNegative("ffxiv_api.poll_interval (FFXIV_API_POLL_INTERVAL)", cfg.FFXIVAPI.PollInterval)
+
+	v.httpURL("tracing.otlp_endpoint (OTEL_EXPORTER_OTLP_ENDPOINT)", cfg.Tracing.OTLPEndpoint)
+}
+
+// Validate returns an `*Errors` with every problem that prevents the bot from
+// running with the configuration.
+func (cfg *Config) Validate() error {
+	v := &validator{}
+
+	cfg.validateCommon(v)
+
+	v.required("listen_address (INTERACTIONS_API_LISTEN_ADDRESS)", cfg.ListenAddress)
+
+	v.httpURL("discord.thumbnail_url (DISCORD_THUMBNAIL_URL)", cfg.Discord.ThumbnailURL)
+
+	icons := cfg.Discord.StatusIcons
+	v.icon("discord.status_icons.online (DISCORD_STATUS_ICON_ONLINE)", icons.Online)
+	v.icon("discord.status_icons.maintenance (DISCORD_STATUS_ICON_MAINTENANCE)", icons.Maintenance)
+	v.icon("discord.status_icons.congested (DISCORD_STATUS_ICON_CONGESTED)", icons.Congested)
+	v.icon("discord.status_icons.preferred (DISCORD_STATUS_ICON_PREFERRED)", icons.Preferred)
+	v.icon("discord.status_icons.new (DISCORD_STATUS_ICON_NEW)", icons.New)
+	v.icon("discord.status_icons.character_creation_locked (DISCORD_STATUS_ICON_CHARACTER_CREATION_LOCKED)", icons.CharacterCreationLocked)
+	v.nonNegative("discord.command_cooldown (DISCORD_COMMAND_COOLDOWN)", cfg.Discord.CommandCooldown)
+	v.nonNegative("discord.max_timestamp_skew (DISCORD_MAX_TIMESTAMP_SKEW)", cfg.Discord.MaxTimestampSkew)
+	v.nonNegative("discord.replay_window (DISCORD_REPLAY_WINDOW)", cfg.Discord.ReplayWindow)
+	if cfg.Discord.MaxTimestampSkew > 0 && cfg.Discord.ReplayWindow > 0 && cfg.Discord.ReplayWindow < cfg.Discord.MaxTimestampSkew {
+		// Interaction IDs would be forgotten while their timestamps are
+		// still accepted, letting replays through.
+		v.errorf("discord.replay_window (DISCORD_REPLAY_WINDOW) must not be shorter than discord.max_timestamp_skew (DISCORD_MAX_TIMESTAMP_SKEW)")
+	}
+
+	v.nonNegative("shutdown_timeout (SHUTDOWN_TIMEOUT)", cfg.ShutdownTimeout)
+	v.nonNegative("shutdown_grace_period (SHUTDOWN_GRACE_PERIOD)", cfg.ShutdownGracePeriod)
+	if cfg.ShutdownTimeout > 0 && cfg.ShutdownGracePeriod >= cfg.ShutdownTimeout {
+		// The remaining steps would run without waiting, closing
+		// requests abruptly.
+		v.errorf("shutdown_grace_period (SHUTDOWN_GRACE_PERIOD) must be shorter than shutdown_timeout (SHUTDOWN_TIMEOUT)")
+	}
+
+	cfg.validateApplications(v, true)
+
+	return v.err()
+}
+
+// ValidateExporter is like `Validate`, but only checks the settings used by
+// the exporter.
+func (cfg *Config) ValidateExporter() error {
+	v := &validator{}
+
+	cfg.validateCommon(v)
+
+	v.required("exporter_listen_address (EXPORTER_LISTEN_ADDRESS)", cfg.ExporterListenAddress)
+
+	return v.err()
+}
+
+// ValidateAPI is like `Validate`, but only checks the settings needed to use
+// the FFXIV API.
+func (cfg *Config) ValidateAPI() error {
+	v := &validator{}
+
+	cfg.validateCommon(v)
+
+	return v.err()
+}
+
+// ValidateDiscord is like `Validate`, but only checks the settings needed to
+// use the Discord API, e.g. to manage commands. Public keys are not required.
+func (cfg *Config) ValidateDiscord() error {
+	v := &validator{}
+
+	cfg.validateApplications(v, false)
+
+	return v.err()
+}
+
+// validateApplications checks the Discord applications. Public keys are only
+// required when `requirePublicKeys` is `true`, i.e. when they receive
+// interactions.
+func (cfg *Config) validateApplications(v *validator, requirePublicKeys bool) {
+	v.httpURL("discord.api_url (DISCORD_API_URL)", cfg.Discord.APIURL)
+
+	if len(cfg.Discord.Applications) == 0 {
+		v.errorf("at least one Discord application is required (discord.applications, DISCORD_APPLICATIONS or DISCORD_APPLICATION_ID)")
+
+		return
+	}
+
+	names := map[string]struct{}{}
+	for i, app := range cfg.Discord.Applications {
+		if !applicationNameRegexp.MatchString(app.Name) {
+			v.errorf("discord.applications[%d].name %#v must match %s", i, app.Name, applicationNameRegexp.String())
+
+			continue
+		}
+
+		if _, ok := names[app.Name]; ok {
+			v.errorf("discord.applications[%d].name %#v is duplicated", i, app.Name)
+		}
+		names[app.Name] = struct{}{}
+
+		prefix := ApplicationEnvironmentPrefix(app.Name)
+
+		v.required(fmt.Sprintf("application_id of application %#v (%sAPPLICATION_ID)", app.Name, prefix), app.ApplicationID)
+		v.required(fmt.Sprintf("token of application %#v (%sTOKEN)", app.Name, prefix), app.Token)
+		if requirePublicKeys && !app.SkipRequestValidation {
+			v.required(fmt.Sprintf("public_key_file of application %#v (%sPUBLIC_KEY_FILE)", app.Name, prefix), app.PublicKeyFile)
+		}
+	}
+}
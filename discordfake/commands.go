@@ -0,0 +1,193 @@
+package discordfake
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/bwmarrin/discordgo"
+	chi "github.com/go-chi/chi/v5"
+)
+
+func (s *Server) commandRoutes(r chi.Router) {
+	r.Get("/", s.handleCommandsList)
+	r.Put("/", s.handleCommandsBulkOverwrite)
+	r.Post("/", s.handleCommandCreate)
+	r.Get("/{command}", s.handleCommandGet)
+	r.Patch("/{command}", s.handleCommandEdit)
+	r.Delete("/{command}", s.handleCommandDelete)
+}
+
+func scopeFromRequest(req *http.Request) commandScope {
+	return commandScope{
+		applicationID: chi.URLParam(req, "application"),
+		guildID:       chi.URLParam(req, "guild"),
+	}
+}
+
+// register assigns the fields that Discord assigns to registered commands.
+// The ID of a command is kept when it's replaced by one with the same name.
+//
+// Like Discord, `dm_permission` is dropped from guild commands, where it
+// doesn't apply.
+func (s *Server) register(scope commandScope, cmd *discordgo.ApplicationCommand, previous []*discordgo.ApplicationCommand) {
+	cmd.ID = ""
+	for _, p := range previous {
+		if p.Name == cmd.Name {
+			cmd.ID = p.ID
+		}
+	}
+	if cmd.ID == "" {
+		cmd.ID = s.newID()
+	}
+
+	cmd.ApplicationID = scope.applicationID
+	cmd.GuildID = scope.guildID
+	cmd.Version = s.newID()
+	if cmd.Type == 0 {
+		cmd.Type = discordgo.ChatApplicationCommand
+	}
+	if scope.guildID != "" {
+		cmd.DMPermission = nil
+	}
+}
+
+func (s *Server) handleCommandsList(w http.ResponseWriter, req *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	commands := s.commands[scopeFromRequest(req)]
+	if commands == nil {
+		commands = []*discordgo.ApplicationCommand{}
+	}
+
+	respondJSON(w, http.StatusOK, commands)
+}
+
+func (s *Server) handleCommandsBulkOverwrite(w http.ResponseWriter, req *http.Request) {
+	var commands []*discordgo.ApplicationCommand
+
+	err := json.NewDecoder(req.Body).Decode(&commands)
+	if err != nil {
+		badRequest(w, err)
+
+		return
+	}
+
+	scope := scopeFromRequest(req)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	previous := s.commands[scope]
+	for _, cmd := range commands {
+		s.register(scope, cmd, previous)
+	}
+
+	s.commands[scope] = commands
+
+	respondJSON(w, http.StatusOK, commands)
+}
+
+func (s *Server) handleCommandCreate(w http.ResponseWriter, req *http.Request) {
+	var cmd *discordgo.ApplicationCommand
+
+	err := json.NewDecoder(req.Body).Decode(&cmd)
+	if err != nil {
+		badRequest(w, err)
+
+		return
+	}
+
+	scope := scopeFromRequest(req)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Creating a command with the name of an existing one replaces it.
+	previous := s.commands[scope]
+	s.register(scope, cmd, previous)
+
+	var commands []*discordgo.ApplicationCommand
+	for _, p := range previous {
+		if p.Name != cmd.Name {
+			commands = append(commands, p)
+		}
+	}
+	s.commands[scope] = append(commands, cmd)
+
+	respondJSON(w, http.StatusCreated, cmd)
+}
+
+// findCommand returns the index of the command with the ID in the request, or
+// -1 if there's none. `s.mu` must be held.
+func (s *Server) findCommand(req *http.Request) (commandScope, int) {
+	scope := scopeFromRequest(req)
+	commandID := chi.URLParam(req, "command")
+
+	for i, cmd := range s.commands[scope] {
+		if cmd.ID == commandID {
+			return scope, i
+		}
+	}
+
+	return scope, -1
+}
+
+func (s *Server) handleCommandGet(w http.ResponseWriter, req *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	scope, i := s.findCommand(req)
+	if i < 0 {
+		respondError(w, http.StatusNotFound, "Unknown application command")
+
+		return
+	}
+
+	respondJSON(w, http.StatusOK, s.commands[scope][i])
+}
+
+func (s *Server) handleCommandEdit(w http.ResponseWriter, req *http.Request) {
+	var edit *discordgo.ApplicationCommand
+
+	err := json.NewDecoder(req.Body).Decode(&edit)
+	if err != nil {
+		badRequest(w, err)
+
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	scope, i := s.findCommand(req)
+	if i < 0 {
+		respondError(w, http.StatusNotFound, "Unknown application command")
+
+		return
+	}
+
+	cmd := s.commands[scope][i]
+	edit.Name = cmd.Name
+	s.register(scope, edit, s.commands[scope])
+	s.commands[scope][i] = edit
+
+	respondJSON(w, http.StatusOK, edit)
+}
+
+func (s *Server) handleCommandDelete(w http.ResponseWriter, req *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	scope, i := s.findCommand(req)
+	if i < 0 {
+		respondError(w, http.StatusNotFound, "Unknown application command")
+
+		return
+	}
+
+	commands := s.commands[scope]
+	s.commands[scope] = append(commands[:i:i], commands[i+1:]...)
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,314 @@
+// Package discordfake is an in-memory stand-in for the subset of the Discord
+// REST API used by the bot: application commands, interaction responses and
+// webhooks, and channel messages.
+//
+// Point `discordgo` at it with `interactionsapi.Server.DiscordAPIURL` or
+// `interactionsapi.NewDiscordHTTPClient`, using `Server.URL`.
+package discordfake
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/bwmarrin/discordgo"
+	chi "github.com/go-chi/chi/v5"
+
+	"github.com/c032/ffxiv-world-status-discord/simulator"
+)
+
+// Request is a request received by the fake.
+type Request struct {
+	Method string
+	Path   string
+}
+
+// File is a file attached to a message or interaction response.
+type File struct {
+	Name        string
+	ContentType string
+	Data        []byte
+}
+
+// InteractionCallback is a response to an interaction, sent to the callback
+// endpoint.
+type InteractionCallback struct {
+	InteractionID string
+	Token         string
+	Response      *discordgo.InteractionResponse
+	Files         []File
+}
+
+type commandScope struct {
+	applicationID string
+	guildID       string
+}
+
+// Server is a fake of the Discord REST API.
+//
+// Every method is safe for concurrent use.
+type Server struct {
+	// URL is the base URL of the API, equivalent to `discordgo.EndpointAPI`.
+	URL string
+
+	httpServer *httptest.Server
+
+	mu sync.Mutex
+
+	nextID   int64
+	failNext []int
+
+	requests  []Request
+	commands  map[commandScope][]*discordgo.ApplicationCommand
+	callbacks []InteractionCallback
+
+	// originals contains the original response of each interaction, by
+	// token.
+	originals map[string]*discordgo.Message
+
+	// followups contains the follow-up messages of each interaction, by
+	// token.
+	followups map[string][]*discordgo.Message
+
+	channelMessages map[string][]*discordgo.Message
+	files           map[string][]File
+}
+
+// NewServer starts a fake. It must be closed with `Close`.
+func NewServer() *Server {
+	s := &Server{
+		nextID:          1000,
+		commands:        map[commandScope][]*discordgo.ApplicationCommand{},
+		originals:       map[string]*discordgo.Message{},
+		followups:       map[string][]*discordgo.Message{},
+		channelMessages: map[string][]*discordgo.Message{},
+		files:           map[string][]File{},
+	}
+
+	s.httpServer = httptest.NewServer(s.router())
+	s.URL = s.httpServer.URL + "/api/v" + discordgo.APIVersion + "/"
+
+	return s
+}
+
+// Close stops the fake.
+func (s *Server) Close() {
+	s.httpServer.Close()
+}
+
+func (s *Server) router() http.Handler {
+	r := chi.NewRouter()
+
+	r.Use(s.recordRequest)
+	r.Use(s.failRequest)
+	r.Use(requireAuthorization)
+
+	r.Route("/api/v"+discordgo.APIVersion, func(r chi.Router) {
+		r.Route("/applications/{application}", func(r chi.Router) {
+			r.Route("/commands", s.commandRoutes)
+			r.Route("/guilds/{guild}/commands", s.commandRoutes)
+		})
+
+		r.Post("/interactions/{interaction}/{token}/callback", s.handleInteractionCallback)
+
+		r.Route("/webhooks/{application}/{token}", func(r chi.Router) {
+			r.Post("/", s.handleFollowupCreate)
+			r.Get("/messages/{message}", s.handleWebhookMessageGet)
+			r.Patch("/messages/{message}", s.handleWebhookMessageEdit)
+			r.Delete("/messages/{message}", s.handleWebhookMessageDelete)
+		})
+
+		r.Route("/channels/{channel}/messages", func(r chi.Router) {
+			r.Get("/", s.handleChannelMessagesList)
+			r.Post("/", s.handleChannelMessageCreate)
+			r.Get("/{message}", s.handleChannelMessageGet)
+			r.Patch("/{message}", s.handleChannelMessageEdit)
+			r.Delete("/{message}", s.handleChannelMessageDelete)
+		})
+	})
+
+	return r
+}
+
+func (s *Server) recordRequest(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		s.mu.Lock()
+		s.requests = append(s.requests, Request{
+			Method: req.Method,
+			Path:   req.URL.Path,
+		})
+		s.mu.Unlock()
+
+		next.ServeHTTP(w, req)
+	})
+}
+
+func (s *Server) failRequest(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		s.mu.Lock()
+		status := 0
+		if len(s.failNext) > 0 {
+			status = s.failNext[0]
+			s.failNext = s.failNext[1:]
+		}
+		s.mu.Unlock()
+
+		if status != 0 {
+			respondError(w, status, "injected failure")
+
+			return
+		}
+
+		next.ServeHTTP(w, req)
+	})
+}
+
+func requireAuthorization(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		// Interaction callbacks and webhooks are authenticated by their
+		// token, like in Discord.
+		path := req.URL.Path
+		isTokenAuthenticated := strings.Contains(path, "/interactions/") || strings.Contains(path, "/webhooks/")
+
+		if !isTokenAuthenticated && !strings.HasPrefix(req.Header.Get("Authorization"), "Bot ") {
+			respondError(w, http.StatusUnauthorized, "401: Unauthorized")
+
+			return
+		}
+
+		next.ServeHTTP(w, req)
+	})
+}
+
+// FailNext makes the next request fail with `status`. Multiple calls fail
+// multiple requests, in order.
+func (s *Server) FailNext(status int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.failNext = append(s.failNext, status)
+}
+
+// Requests returns every request received so far.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]Request(nil), s.requests...)
+}
+
+func (s *Server) newID() string {
+	s.nextID++
+
+	return strconv.FormatInt(s.nextID, 10)
+}
+
+func respondJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	if v != nil {
+		_ = json.NewEncoder(w).Encode(v)
+	}
+}
+
+// respondError responds with an error in the format used by Discord.
+func respondError(w http.ResponseWriter, status int, message string) {
+	respondJSON(w, status, map[string]any{
+		"code":    0,
+		"message": message,
+	})
+}
+
+// readBody returns the JSON payload of `req`. Multipart bodies are also
+// supported, with the payload in `payload_json`, and their files are
+// returned.
+func readBody(req *http.Request) ([]byte, []File, error) {
+	mediaType, params, err := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/form-data" {
+		payload, err := io.ReadAll(req.Body)
+
+		return payload, nil, err
+	}
+
+	var (
+		payload []byte
+		files   []File
+	)
+
+	mr := multipart.NewReader(req.Body, params["boundary"])
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+
+		data, err := io.ReadAll(part)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if part.FormName() == "payload_json" {
+			payload = data
+
+			continue
+		}
+
+		files = append(files, File{
+			Name:        part.FileName(),
+			ContentType: part.Header.Get("Content-Type"),
+			Data:        data,
+		})
+	}
+
+	return payload, files, nil
+}
+
+// messagePayload contains the fields of messages that can be set when
+// creating or editing them.
+//
+// Components are decoded separately, because `discordgo` can't decode them
+// into `[]discordgo.MessageComponent`.
+type messagePayload struct {
+	Content    *string                    `json:"content"`
+	Embeds     *[]*discordgo.MessageEmbed `json:"embeds"`
+	Components *[]json.RawMessage         `json:"components"`
+	Flags      *discordgo.MessageFlags    `json:"flags"`
+}
+
+// apply sets the fields of `msg` that are set in the payload.
+func (p *messagePayload) apply(msg *discordgo.Message) error {
+	if p.Content != nil {
+		msg.Content = *p.Content
+	}
+	if p.Embeds != nil {
+		msg.Embeds = *p.Embeds
+	}
+	if p.Components != nil {
+		components, err := simulator.DecodeComponents(*p.Components)
+		if err != nil {
+			return err
+		}
+
+		msg.Components = components
+	}
+	if p.Flags != nil {
+		msg.Flags = *p.Flags
+	}
+
+	return nil
+}
+
+func badRequest(w http.ResponseWriter, err error) {
+	respondError(w, http.StatusBadRequest, fmt.Sprintf("400: Bad Request (%s)", err.Error()))
+}
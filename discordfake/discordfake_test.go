@@ -0,0 +1,309 @@
+package discordfake_test
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/c032/ffxiv-world-status-discord/discordfake"
+	iapi "github.com/c032/ffxiv-world-status-discord/interactions-api"
+)
+
+const testApplicationID = "100"
+
+func newTestSession(t *testing.T) (*discordfake.Server, *discordgo.Session) {
+	t.Helper()
+
+	fake := discordfake.NewServer()
+	t.Cleanup(fake.Close)
+
+	session, err := discordgo.New("Bot token")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	session.Client, err = iapi.NewDiscordHTTPClient(fake.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return fake, session
+}
+
+func TestServer_Commands(t *testing.T) {
+	fake, session := newTestSession(t)
+
+	_, err := session.ApplicationCommandBulkOverwrite(testApplicationID, "", []*discordgo.ApplicationCommand{
+		{Name: "ping", Description: "Ping."},
+		{Name: "characters", Description: "Characters."},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fake.AssertCommands(t, testApplicationID, "", "characters", "ping")
+	fake.AssertCommands(t, testApplicationID, "200")
+
+	dmPermission := false
+	created, err := session.ApplicationCommandCreate(testApplicationID, "200", &discordgo.ApplicationCommand{
+		Name:         "settings",
+		Description:  "Settings.",
+		DMPermission: &dmPermission,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created.DMPermission != nil {
+		t.Fatalf("created.DMPermission = %t; want it unset for a guild command", *created.DMPermission)
+	}
+
+	fake.AssertCommands(t, testApplicationID, "200", "settings")
+
+	_, err = session.ApplicationCommandEdit(testApplicationID, "200", created.ID, &discordgo.ApplicationCommand{
+		Description: "New description.",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	commands, err := session.ApplicationCommands(testApplicationID, "200")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(commands) != 1 || commands[0].Name != "settings" || commands[0].Description != "New description." || commands[0].ID != created.ID {
+		t.Fatalf("commands after edit = %#v", commands)
+	}
+
+	err = session.ApplicationCommandDelete(testApplicationID, "200", created.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fake.AssertCommands(t, testApplicationID, "200")
+	fake.AssertRequested(t, http.MethodDelete, "applications/"+testApplicationID+"/guilds/200/commands/"+created.ID)
+}
+
+func TestServer_RequiresAuthorization(t *testing.T) {
+	fake, session := newTestSession(t)
+
+	session.Token = ""
+
+	_, err := session.ApplicationCommands(testApplicationID, "")
+
+	var restErr *discordgo.RESTError
+	if !errors.As(err, &restErr) || restErr.Response.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("err = %v; want 401", err)
+	}
+
+	fake.AssertNotRequested(t, http.MethodPut)
+}
+
+func TestServer_Interactions(t *testing.T) {
+	fake, session := newTestSession(t)
+
+	interaction := &discordgo.Interaction{
+		ID:    "300",
+		AppID: testApplicationID,
+		Token: "interaction-token",
+	}
+
+	err := session.InteractionRespond(interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: "Loading.",
+			Components: []discordgo.MessageComponent{
+				discordgo.ActionsRow{
+					Components: []discordgo.MessageComponent{
+						discordgo.Button{Label: "Next", CustomID: "characters:1", Style: discordgo.PrimaryButton},
+					},
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	callbacks := fake.InteractionCallbacks()
+	if len(callbacks) != 1 || callbacks[0].InteractionID != "300" {
+		t.Fatalf("callbacks = %#v", callbacks)
+	}
+	if got := len(callbacks[0].Response.Data.Components); got != 1 {
+		t.Fatalf("len(components) = %d; want 1", got)
+	}
+
+	content := "Done."
+	_, err = session.InteractionResponseEdit(interaction, &discordgo.WebhookEdit{
+		Content: &content,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := fake.OriginalResponse(interaction.Token); got == nil || got.Content != content || len(got.Components) != 1 {
+		t.Fatalf("original response = %#v", got)
+	}
+
+	followup, err := session.FollowupMessageCreate(interaction, true, &discordgo.WebhookParams{
+		Content: "Follow-up.",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := fake.Followups(interaction.Token); len(got) != 1 || got[0].ID != followup.ID {
+		t.Fatalf("followups = %#v", got)
+	}
+
+	err = session.InteractionResponseDelete(interaction)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := fake.OriginalResponse(interaction.Token); got != nil {
+		t.Fatalf("original response after delete = %#v; want nil", got)
+	}
+}
+
+func TestServer_Acknowledge(t *testing.T) {
+	fake, session := newTestSession(t)
+
+	interaction := &discordgo.Interaction{
+		ID:    "300",
+		AppID: testApplicationID,
+		Token: "interaction-token",
+	}
+
+	fake.Acknowledge(interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+	})
+
+	if got := fake.InteractionCallbacks(); len(got) != 0 {
+		t.Fatalf("callbacks = %#v; want none", got)
+	}
+
+	content := "Done."
+	msg, err := session.InteractionResponseEdit(interaction, &discordgo.WebhookEdit{
+		Content: &content,
+		Files: []*discordgo.File{
+			{Name: "status.png", ContentType: "image/png", Reader: bytes.NewReader([]byte("png"))},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := fake.OriginalResponse(interaction.Token); got == nil || got.Content != content {
+		t.Fatalf("original response = %#v", got)
+	}
+	if got := fake.Files(msg.ID); len(got) != 1 || got[0].Name != "status.png" {
+		t.Fatalf("files = %#v", got)
+	}
+}
+
+func TestServer_OriginalResponseIsCopied(t *testing.T) {
+	fake, session := newTestSession(t)
+
+	interaction := &discordgo.Interaction{
+		ID:    "300",
+		AppID: testApplicationID,
+		Token: "interaction-token",
+	}
+
+	fake.Acknowledge(interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+	})
+
+	before := fake.OriginalResponse(interaction.Token)
+	if before == nil {
+		t.Fatal("original response = nil")
+	}
+
+	content := "Done."
+	_, err := session.InteractionResponseEdit(interaction, &discordgo.WebhookEdit{
+		Content: &content,
+		Files: []*discordgo.File{
+			{Name: "status.png", ContentType: "image/png", Reader: bytes.NewReader([]byte("png"))},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if before.Content != "" || len(before.Attachments) != 0 {
+		t.Fatalf("original response read before the edit = %#v; want it unchanged", before)
+	}
+
+	after := fake.OriginalResponse(interaction.Token)
+	if after.Content != content || len(after.Attachments) != 1 {
+		t.Fatalf("original response = %#v", after)
+	}
+
+	after.Attachments[0].Filename = "changed.png"
+	if got := fake.OriginalResponse(interaction.Token); got.Attachments[0].Filename != "status.png" {
+		t.Fatalf("attachment = %#v; want changes to copies to be ignored", got.Attachments[0])
+	}
+}
+
+func TestServer_ChannelMessages(t *testing.T) {
+	fake, session := newTestSession(t)
+
+	png := []byte("\x89PNG\r\n\x1a\n")
+
+	msg, err := session.ChannelMessageSendComplex("400", &discordgo.MessageSend{
+		Content: "Status.",
+		Files: []*discordgo.File{
+			{Name: "status.png", ContentType: "image/png", Reader: bytes.NewReader(png)},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(msg.Attachments) != 1 || msg.Attachments[0].Filename != "status.png" {
+		t.Fatalf("msg.Attachments = %#v", msg.Attachments)
+	}
+
+	files := fake.Files(msg.ID)
+	if len(files) != 1 || !bytes.Equal(files[0].Data, png) {
+		t.Fatalf("files = %#v", files)
+	}
+
+	_, err = session.ChannelMessageEdit("400", msg.ID, "Edited.")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	messages := fake.ChannelMessages("400")
+	if len(messages) != 1 || messages[0].Content != "Edited." {
+		t.Fatalf("messages = %#v", messages)
+	}
+
+	err = session.ChannelMessageDelete("400", msg.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if messages := fake.ChannelMessages("400"); len(messages) != 0 {
+		t.Fatalf("messages after delete = %#v", messages)
+	}
+}
+
+func TestServer_FailNext(t *testing.T) {
+	fake, session := newTestSession(t)
+
+	fake.FailNext(http.StatusBadRequest)
+
+	_, err := session.ApplicationCommands(testApplicationID, "")
+	if err == nil {
+		t.Fatal("request succeeded; want injected failure")
+	}
+
+	_, err = session.ApplicationCommands(testApplicationID, "")
+	if err != nil {
+		t.Fatalf("request after the injected failure failed: %s", err)
+	}
+}
@@ -0,0 +1,340 @@
+package discordfake
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	chi "github.com/go-chi/chi/v5"
+
+	"github.com/c032/ffxiv-world-status-discord/simulator"
+)
+
+// newMessage creates a message from `payload`, with `files` attached. `s.mu`
+// must be held.
+func (s *Server) newMessage(channelID string, payload *messagePayload, files []File) (*discordgo.Message, error) {
+	msg := &discordgo.Message{
+		ID:        s.newID(),
+		ChannelID: channelID,
+		Timestamp: time.Now(),
+	}
+
+	err := payload.apply(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	s.attach(msg, files)
+
+	return msg, nil
+}
+
+// attach adds `files` to the attachments of `msg`. `s.mu` must be held.
+func (s *Server) attach(msg *discordgo.Message, files []File) {
+	for _, f := range files {
+		msg.Attachments = append(msg.Attachments, &discordgo.MessageAttachment{
+			ID:          s.newID(),
+			Filename:    f.Name,
+			ContentType: f.ContentType,
+			Size:        len(f.Data),
+		})
+	}
+
+	s.files[msg.ID] = append(s.files[msg.ID], files...)
+}
+
+func decodeMessagePayload(w http.ResponseWriter, req *http.Request) (*messagePayload, []File, bool) {
+	data, files, err := readBody(req)
+	if err != nil {
+		badRequest(w, err)
+
+		return nil, nil, false
+	}
+
+	var payload messagePayload
+	err = json.Unmarshal(data, &payload)
+	if err != nil {
+		badRequest(w, err)
+
+		return nil, nil, false
+	}
+
+	return &payload, files, true
+}
+
+func (s *Server) handleInteractionCallback(w http.ResponseWriter, req *http.Request) {
+	data, files, err := readBody(req)
+	if err != nil {
+		badRequest(w, err)
+
+		return
+	}
+
+	resp, err := simulator.DecodeInteractionResponse(data)
+	if err != nil {
+		badRequest(w, err)
+
+		return
+	}
+
+	token := chi.URLParam(req, "token")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.callbacks = append(s.callbacks, InteractionCallback{
+		InteractionID: chi.URLParam(req, "interaction"),
+		Token:         token,
+		Response:      resp,
+		Files:         files,
+	})
+
+	s.respond(token, resp, files)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// respond creates the original response of the interaction with `token`, if
+// `resp` creates a message. `s.mu` must be held.
+func (s *Server) respond(token string, resp *discordgo.InteractionResponse, files []File) {
+	switch resp.Type {
+	case discordgo.InteractionResponseChannelMessageWithSource, discordgo.InteractionResponseDeferredChannelMessageWithSource:
+		msg := &discordgo.Message{
+			ID:        s.newID(),
+			Timestamp: time.Now(),
+		}
+		if resp.Data != nil {
+			msg.Content = resp.Data.Content
+			msg.Embeds = resp.Data.Embeds
+			msg.Components = resp.Data.Components
+			msg.Flags = resp.Data.Flags
+		}
+		s.attach(msg, files)
+
+		s.originals[token] = msg
+	}
+}
+
+// findWebhookMessage returns the message of the interaction with the token in
+// the request. `s.mu` must be held.
+func (s *Server) findWebhookMessage(req *http.Request) *discordgo.Message {
+	token := chi.URLParam(req, "token")
+	messageID := chi.URLParam(req, "message")
+
+	if messageID == "@original" {
+		return s.originals[token]
+	}
+
+	for _, msg := range s.followups[token] {
+		if msg.ID == messageID {
+			return msg
+		}
+	}
+
+	return nil
+}
+
+func (s *Server) handleFollowupCreate(w http.ResponseWriter, req *http.Request) {
+	payload, files, ok := decodeMessagePayload(w, req)
+	if !ok {
+		return
+	}
+
+	token := chi.URLParam(req, "token")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	msg, err := s.newMessage("", payload, files)
+	if err != nil {
+		badRequest(w, err)
+
+		return
+	}
+
+	s.followups[token] = append(s.followups[token], msg)
+
+	respondJSON(w, http.StatusOK, msg)
+}
+
+func (s *Server) handleWebhookMessageGet(w http.ResponseWriter, req *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	msg := s.findWebhookMessage(req)
+	if msg == nil {
+		respondError(w, http.StatusNotFound, "Unknown Message")
+
+		return
+	}
+
+	respondJSON(w, http.StatusOK, msg)
+}
+
+func (s *Server) handleWebhookMessageEdit(w http.ResponseWriter, req *http.Request) {
+	payload, files, ok := decodeMessagePayload(w, req)
+	if !ok {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	msg := s.findWebhookMessage(req)
+	if msg == nil {
+		respondError(w, http.StatusNotFound, "Unknown Message")
+
+		return
+	}
+
+	err := payload.apply(msg)
+	if err != nil {
+		badRequest(w, err)
+
+		return
+	}
+	s.attach(msg, files)
+
+	respondJSON(w, http.StatusOK, msg)
+}
+
+func (s *Server) handleWebhookMessageDelete(w http.ResponseWriter, req *http.Request) {
+	token := chi.URLParam(req, "token")
+	messageID := chi.URLParam(req, "message")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	msg := s.findWebhookMessage(req)
+	if msg == nil {
+		respondError(w, http.StatusNotFound, "Unknown Message")
+
+		return
+	}
+
+	if messageID == "@original" {
+		delete(s.originals, token)
+	} else {
+		s.followups[token] = slices.DeleteFunc(s.followups[token], func(m *discordgo.Message) bool {
+			return m.ID == messageID
+		})
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// findChannelMessage returns the index of the message in the request, or -1
+// if there's none. `s.mu` must be held.
+func (s *Server) findChannelMessage(req *http.Request) (string, int) {
+	channelID := chi.URLParam(req, "channel")
+	messageID := chi.URLParam(req, "message")
+
+	for i, msg := range s.channelMessages[channelID] {
+		if msg.ID == messageID {
+			return channelID, i
+		}
+	}
+
+	return channelID, -1
+}
+
+func (s *Server) handleChannelMessagesList(w http.ResponseWriter, req *http.Request) {
+	channelID := chi.URLParam(req, "channel")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Discord returns the newest messages first.
+	messages := slices.Clone(s.channelMessages[channelID])
+	slices.Reverse(messages)
+	if messages == nil {
+		messages = []*discordgo.Message{}
+	}
+
+	respondJSON(w, http.StatusOK, messages)
+}
+
+func (s *Server) handleChannelMessageCreate(w http.ResponseWriter, req *http.Request) {
+	payload, files, ok := decodeMessagePayload(w, req)
+	if !ok {
+		return
+	}
+
+	channelID := chi.URLParam(req, "channel")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	msg, err := s.newMessage(channelID, payload, files)
+	if err != nil {
+		badRequest(w, err)
+
+		return
+	}
+
+	s.channelMessages[channelID] = append(s.channelMessages[channelID], msg)
+
+	respondJSON(w, http.StatusOK, msg)
+}
+
+func (s *Server) handleChannelMessageGet(w http.ResponseWriter, req *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	channelID, i := s.findChannelMessage(req)
+	if i < 0 {
+		respondError(w, http.StatusNotFound, "Unknown Message")
+
+		return
+	}
+
+	respondJSON(w, http.StatusOK, s.channelMessages[channelID][i])
+}
+
+func (s *Server) handleChannelMessageEdit(w http.ResponseWriter, req *http.Request) {
+	payload, files, ok := decodeMessagePayload(w, req)
+	if !ok {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	channelID, i := s.findChannelMessage(req)
+	if i < 0 {
+		respondError(w, http.StatusNotFound, "Unknown Message")
+
+		return
+	}
+
+	msg := s.channelMessages[channelID][i]
+
+	err := payload.apply(msg)
+	if err != nil {
+		badRequest(w, err)
+
+		return
+	}
+	s.attach(msg, files)
+
+	respondJSON(w, http.StatusOK, msg)
+}
+
+func (s *Server) handleChannelMessageDelete(w http.ResponseWriter, req *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	channelID, i := s.findChannelMessage(req)
+	if i < 0 {
+		respondError(w, http.StatusNotFound, "Unknown Message")
+
+		return
+	}
+
+	messages := s.channelMessages[channelID]
+	s.channelMessages[channelID] = append(messages[:i:i], messages[i+1:]...)
+
+	w.WriteHeader(http.StatusNoContent)
+}
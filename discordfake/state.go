@@ -0,0 +1,182 @@
+package discordfake
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Commands returns the commands registered for the application in the
+// guild, or the global commands if `guildID` is empty.
+func (s *Server) Commands(applicationID string, guildID string) []*discordgo.ApplicationCommand {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return slices.Clone(s.commands[commandScope{applicationID, guildID}])
+}
+
+// SetCommands replaces the commands registered for the application in the
+// guild, e.g. to simulate commands registered by a previous deploy.
+func (s *Server) SetCommands(applicationID string, guildID string, commands []*discordgo.ApplicationCommand) {
+	scope := commandScope{applicationID, guildID}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	registered := make([]*discordgo.ApplicationCommand, 0, len(commands))
+	for _, cmd := range commands {
+		c := *cmd
+		s.register(scope, &c, s.commands[scope])
+		registered = append(registered, &c)
+	}
+
+	s.commands[scope] = registered
+}
+
+// CommandNames returns the sorted names of the commands registered for the
+// application in the guild.
+func (s *Server) CommandNames(applicationID string, guildID string) []string {
+	var names []string
+	for _, cmd := range s.Commands(applicationID, guildID) {
+		names = append(names, cmd.Name)
+	}
+
+	slices.Sort(names)
+
+	return names
+}
+
+// InteractionCallbacks returns every response to interactions sent to the
+// callback endpoint.
+func (s *Server) InteractionCallbacks() []InteractionCallback {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return slices.Clone(s.callbacks)
+}
+
+// Acknowledge records `resp` as the response to `interaction` sent in the
+// body of the HTTP request that delivered it, like Discord does for
+// interactions received by an HTTP endpoint. Afterwards, the original
+// response can be edited through the webhook endpoints.
+func (s *Server) Acknowledge(interaction *discordgo.Interaction, resp *discordgo.InteractionResponse) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.respond(interaction.Token, resp, nil)
+}
+
+// OriginalResponse returns the original response of the interaction with
+// `token`, or `nil` if there's none.
+func (s *Server) OriginalResponse(token string) *discordgo.Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	msg := s.originals[token]
+	if msg == nil {
+		return nil
+	}
+
+	return cloneMessage(msg)
+}
+
+// Followups returns the follow-up messages of the interaction with `token`.
+func (s *Server) Followups(token string) []*discordgo.Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return cloneMessages(s.followups[token])
+}
+
+// ChannelMessages returns the messages sent to the channel, oldest first.
+func (s *Server) ChannelMessages(channelID string) []*discordgo.Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return cloneMessages(s.channelMessages[channelID])
+}
+
+// cloneMessage returns a copy of `msg` that doesn't share its attachments,
+// embeds or components, so tests can read it while the server edits the
+// original. `s.mu` must be held.
+func cloneMessage(msg *discordgo.Message) *discordgo.Message {
+	c := *msg
+
+	c.Attachments = nil
+	for _, a := range msg.Attachments {
+		attachment := *a
+		c.Attachments = append(c.Attachments, &attachment)
+	}
+
+	c.Embeds = nil
+	for _, e := range msg.Embeds {
+		embed := *e
+		embed.Fields = slices.Clone(e.Fields)
+		c.Embeds = append(c.Embeds, &embed)
+	}
+
+	c.Components = slices.Clone(msg.Components)
+
+	return &c
+}
+
+// cloneMessages returns copies of `messages` made with cloneMessage. `s.mu`
+// must be held.
+func cloneMessages(messages []*discordgo.Message) []*discordgo.Message {
+	var clones []*discordgo.Message
+	for _, msg := range messages {
+		clones = append(clones, cloneMessage(msg))
+	}
+
+	return clones
+}
+
+// Files returns the files attached to the message with `messageID`.
+func (s *Server) Files(messageID string) []File {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return slices.Clone(s.files[messageID])
+}
+
+// AssertCommands fails the test if the names of the commands registered for
+// the application in the guild are not `names`, in any order.
+func (s *Server) AssertCommands(t testing.TB, applicationID string, guildID string, names ...string) {
+	t.Helper()
+
+	want := slices.Clone(names)
+	slices.Sort(want)
+
+	got := s.CommandNames(applicationID, guildID)
+	if !slices.Equal(got, want) {
+		t.Fatalf("registered commands (application %#v, guild %#v) = %v; want %v", applicationID, guildID, got, want)
+	}
+}
+
+// AssertRequested fails the test if no request with `method` and `path`
+// (relative to `URL`) was received.
+func (s *Server) AssertRequested(t testing.TB, method string, path string) {
+	t.Helper()
+
+	fullPath := "/api/v" + discordgo.APIVersion + "/" + path
+	for _, req := range s.Requests() {
+		if req.Method == method && req.Path == fullPath {
+			return
+		}
+	}
+
+	t.Fatalf("%s %s was not requested; requests: %v", method, fullPath, s.Requests())
+}
+
+// AssertNotRequested fails the test if any request with `method` was
+// received, e.g. to check that nothing was changed.
+func (s *Server) AssertNotRequested(t testing.TB, method string) {
+	t.Helper()
+
+	for _, req := range s.Requests() {
+		if req.Method == method {
+			t.Fatalf("unexpected request: %s %s", req.Method, req.Path)
+		}
+	}
+}
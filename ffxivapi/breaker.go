@@ -0,0 +1,191 @@
+package ffxivapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultCircuitBreakerFailureThreshold = 5
+	DefaultCircuitBreakerOpenDuration     = 30 * time.Second
+)
+
+// ErrCircuitOpen is returned by `CircuitBreaker` when requests are not being
+// sent to the upstream API because it failed recently.
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// HealthChecker is implemented by clients that can tell whether the upstream
+// API is currently usable, without sending a request.
+type HealthChecker interface {
+	CheckHealth() error
+}
+
+type CircuitState int
+
+const (
+	// CircuitClosed lets every request through.
+	CircuitClosed CircuitState = iota
+
+	// CircuitOpen rejects every request with `ErrCircuitOpen`.
+	CircuitOpen
+
+	// CircuitHalfOpen lets a single request through, to check whether the
+	// upstream API recovered.
+	CircuitHalfOpen
+)
+
+func (state CircuitState) String() string {
+	switch state {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	}
+
+	return fmt.Sprintf("CircuitState(%d)", int(state))
+}
+
+type CircuitBreakerOptions struct {
+	// FailureThreshold is the number of consecutive failures that opens the
+	// circuit. Defaults to `DefaultCircuitBreakerFailureThreshold`.
+	FailureThreshold int
+
+	// OpenDuration is how long the circuit stays open before a request is
+	// let through to check whether the upstream API recovered. Defaults to
+	// `DefaultCircuitBreakerOpenDuration`.
+	OpenDuration time.Duration
+}
+
+var (
+	_ Client        = (*CircuitBreaker)(nil)
+	_ HealthChecker = (*CircuitBreaker)(nil)
+)
+
+// CircuitBreaker is a `Client` that stops sending requests to another
+// `Client` for some time after several consecutive failures.
+type CircuitBreaker struct {
+	c       Client
+	options CircuitBreakerOptions
+
+	mu            sync.Mutex
+	state         CircuitState
+	failures      int
+	openedAt      time.Time
+	trialInFlight bool
+
+	// clock replaces `time.Now` in tests.
+	clock func() time.Time
+}
+
+func NewCircuitBreaker(c Client, options CircuitBreakerOptions) *CircuitBreaker {
+	if options.FailureThreshold <= 0 {
+		options.FailureThreshold = DefaultCircuitBreakerFailureThreshold
+	}
+
+	if options.OpenDuration <= 0 {
+		options.OpenDuration = DefaultCircuitBreakerOpenDuration
+	}
+
+	return &CircuitBreaker{
+		c:       c,
+		options: options,
+	}
+}
+
+func (cb *CircuitBreaker) now() time.Time {
+	if cb.clock == nil {
+		return time.Now()
+	}
+
+	return cb.clock()
+}
+
+// State returns the current state of the circuit.
+func (cb *CircuitBreaker) State() CircuitState {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	if cb.state == CircuitOpen && cb.now().Sub(cb.openedAt) >= cb.options.OpenDuration {
+		return CircuitHalfOpen
+	}
+
+	return cb.state
+}
+
+// CheckHealth returns `ErrCircuitOpen` if the circuit is open.
+func (cb *CircuitBreaker) CheckHealth() error {
+	if cb.State() == CircuitOpen {
+		return ErrCircuitOpen
+	}
+
+	return nil
+}
+
+// allow returns whether a request can be sent to the upstream API.
+func (cb *CircuitBreaker) allow() bool {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	if cb.state == CircuitOpen && cb.now().Sub(cb.openedAt) >= cb.options.OpenDuration {
+		cb.state = CircuitHalfOpen
+	}
+
+	switch cb.state {
+	case CircuitOpen:
+		return false
+	case CircuitHalfOpen:
+		if cb.trialInFlight {
+			return false
+		}
+
+		cb.trialInFlight = true
+	}
+
+	return true
+}
+
+func (cb *CircuitBreaker) record(err error) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	cb.trialInFlight = false
+
+	if err == nil {
+		cb.state = CircuitClosed
+		cb.failures = 0
+
+		return
+	}
+
+	cb.failures++
+	if cb.state == CircuitHalfOpen || cb.failures >= cb.options.FailureThreshold {
+		cb.state = CircuitOpen
+		cb.openedAt = cb.now()
+	}
+}
+
+func (cb *CircuitBreaker) Worlds(ctx context.Context) (*WorldsResponse, error) {
+	if !cb.allow() {
+		return nil, ErrCircuitOpen
+	}
+
+	worlds, err := cb.c.Worlds(ctx)
+
+	// Requests canceled by the caller say nothing about the upstream API.
+	if err != nil && ctx.Err() != nil {
+		cb.mu.Lock()
+		cb.trialInFlight = false
+		cb.mu.Unlock()
+
+		return nil, err
+	}
+
+	cb.record(err)
+
+	return worlds, err
+}
@@ -0,0 +1,82 @@
+package ffxivapi
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type fakeClient struct {
+	err   error
+	calls int
+}
+
+func (fc *fakeClient) Worlds(ctx context.Context) (*WorldsResponse, error) {
+	fc.calls++
+	if fc.err != nil {
+		return nil, fc.err
+	}
+
+	return &WorldsResponse{}, nil
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 12, 1, 12, 0, 0, 0, time.UTC)
+
+	fc := &fakeClient{err: errors.New("upstream failure")}
+	cb := NewCircuitBreaker(fc, CircuitBreakerOptions{
+		FailureThreshold: 2,
+		OpenDuration:     time.Minute,
+	})
+	cb.clock = func() time.Time {
+		return now
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := cb.Worlds(ctx); errors.Is(err, ErrCircuitOpen) {
+			t.Fatalf("request %d: circuit opened too early", i)
+		}
+	}
+
+	if got, want := cb.State(), CircuitOpen; got != want {
+		t.Fatalf("cb.State() = %s; want %s", got, want)
+	}
+	if err := cb.CheckHealth(); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("cb.CheckHealth() = %v; want %v", err, ErrCircuitOpen)
+	}
+
+	if _, err := cb.Worlds(ctx); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("cb.Worlds() error = %v; want %v", err, ErrCircuitOpen)
+	}
+	if got, want := fc.calls, 2; got != want {
+		t.Errorf("fc.calls = %d; want %d", got, want)
+	}
+
+	now = now.Add(time.Minute)
+	if got, want := cb.State(), CircuitHalfOpen; got != want {
+		t.Fatalf("cb.State() = %s; want %s", got, want)
+	}
+
+	// A failed trial opens the circuit again.
+	if _, err := cb.Worlds(ctx); errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("trial request was not sent")
+	}
+	if got, want := cb.State(), CircuitOpen; got != want {
+		t.Fatalf("cb.State() = %s; want %s", got, want)
+	}
+
+	now = now.Add(time.Minute)
+	fc.err = nil
+
+	if _, err := cb.Worlds(ctx); err != nil {
+		t.Fatalf("cb.Worlds() error = %v; want nil", err)
+	}
+	if got, want := cb.State(), CircuitClosed; got != want {
+		t.Errorf("cb.State() = %s; want %s", got, want)
+	}
+	if err := cb.CheckHealth(); err != nil {
+		t.Errorf("cb.CheckHealth() = %v; want nil", err)
+	}
+}
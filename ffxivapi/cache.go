@@ -0,0 +1,212 @@
+package ffxivapi
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const DefaultCacheTTL = time.Minute
+
+// DefaultRequestTimeout is the default of `CachedClient.RequestTimeout`. It's
+// below the 3 seconds Discord waits for the response to an interaction.
+const DefaultRequestTimeout = 2 * time.Second
+
+var (
+	_ Client        = (*CachedClient)(nil)
+	_ HealthChecker = (*CachedClient)(nil)
+)
+
+// CachedClient is a `Client` that reuses responses of another `Client` for
+// some time.
+//
+// Responses are shared by every caller, so they must not be modified.
+type CachedClient struct {
+	c   Client
+	ttl time.Duration
+
+	// Observer receives cache lookups and refreshed responses. It's
+	// optional, and must be set before the client is used.
+	Observer Observer
+
+	// RequestTimeout limits how long `Worlds` waits for a refresh,
+	// including waiting for another one in progress and retries of the
+	// upstream client. Defaults to `DefaultRequestTimeout`. `Refresh` is
+	// not limited.
+	RequestTimeout time.Duration
+
+	// refreshLock ensures only one request to the upstream client is done
+	// at a time. It's a channel instead of a mutex so waiting for it can
+	// be cancelled.
+	refreshLock chan struct{}
+
+	mu        sync.Mutex
+	worlds    *WorldsResponse
+	fetchedAt time.Time
+	lastErr   error
+}
+
+// NewCachedClient returns a `CachedClient` that keeps responses of `c` for
+// `ttl`. If `ttl` is zero or negative, `DefaultCacheTTL` is used.
+func NewCachedClient(c Client, ttl time.Duration) *CachedClient {
+	if ttl <= 0 {
+		ttl = DefaultCacheTTL
+	}
+
+	return &CachedClient{
+		c:   c,
+		ttl: ttl,
+
+		refreshLock: make(chan struct{}, 1),
+	}
+}
+
+// lockRefresh waits until no other refresh is in progress, or until `ctx` is
+// done.
+func (cc *CachedClient) lockRefresh(ctx context.Context) error {
+	select {
+	case cc.refreshLock <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("could not wait for refresh in progress: %w", ctx.Err())
+	}
+}
+
+func (cc *CachedClient) unlockRefresh() {
+	<-cc.refreshLock
+}
+
+func (cc *CachedClient) requestTimeout() time.Duration {
+	if cc.RequestTimeout <= 0 {
+		return DefaultRequestTimeout
+	}
+
+	return cc.RequestTimeout
+}
+
+// Snapshot returns the cached response, even if it's expired, and when it
+// was fetched. It returns `nil` if nothing was fetched yet.
+func (cc *CachedClient) Snapshot() (*WorldsResponse, time.Time) {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	return cc.worlds, cc.fetchedAt
+}
+
+// SetTTL changes how long responses are reused. If `ttl` is zero or negative,
+// `DefaultCacheTTL` is used.
+func (cc *CachedClient) SetTTL(ttl time.Duration) {
+	if ttl <= 0 {
+		ttl = DefaultCacheTTL
+	}
+
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	cc.ttl = ttl
+}
+
+func (cc *CachedClient) fresh(now time.Time) *WorldsResponse {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	if cc.worlds == nil || now.Sub(cc.fetchedAt) >= cc.ttl {
+		return nil
+	}
+
+	return cc.worlds
+}
+
+// Worlds returns the cached response if it's not expired, and otherwise
+// fetches a new one, waiting at most `RequestTimeout`.
+func (cc *CachedClient) Worlds(ctx context.Context) (*WorldsResponse, error) {
+	if worlds := cc.fresh(time.Now()); worlds != nil {
+		cc.observeCacheLookup(true)
+
+		return worlds, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, cc.requestTimeout())
+	defer cancel()
+
+	err := cc.lockRefresh(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer cc.unlockRefresh()
+
+	// Another caller might have refreshed the cache while waiting for the
+	// lock.
+	if worlds := cc.fresh(time.Now()); worlds != nil {
+		cc.observeCacheLookup(true)
+
+		return worlds, nil
+	}
+
+	cc.observeCacheLookup(false)
+
+	return cc.refresh(ctx)
+}
+
+func (cc *CachedClient) observeCacheLookup(hit bool) {
+	if cc.Observer != nil {
+		cc.Observer.ObserveCacheLookup(hit)
+	}
+}
+
+// Refresh fetches a new response and stores it in the cache, regardless of
+// whether the cached one expired.
+func (cc *CachedClient) Refresh(ctx context.Context) (*WorldsResponse, error) {
+	err := cc.lockRefresh(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer cc.unlockRefresh()
+
+	return cc.refresh(ctx)
+}
+
+// refresh must be called while holding `cc.refreshLock`.
+func (cc *CachedClient) refresh(ctx context.Context) (*WorldsResponse, error) {
+	worlds, err := cc.c.Worlds(ctx)
+	if err != nil {
+		if ctx.Err() == nil {
+			cc.mu.Lock()
+			cc.lastErr = err
+			cc.mu.Unlock()
+		}
+
+		return nil, fmt.Errorf("could not refresh cache: %w", err)
+	}
+
+	cc.mu.Lock()
+	cc.worlds = worlds
+	cc.fetchedAt = time.Now()
+	cc.lastErr = nil
+	cc.mu.Unlock()
+
+	if cc.Observer != nil {
+		cc.Observer.ObserveWorlds(worlds)
+	}
+
+	return worlds, nil
+}
+
+// CheckHealth returns the error of the last refresh, if it failed.
+//
+// If the wrapped client implements `HealthChecker`, its health is checked
+// first.
+func (cc *CachedClient) CheckHealth() error {
+	if hc, ok := cc.c.(HealthChecker); ok {
+		err := hc.CheckHealth()
+		if err != nil {
+			return err
+		}
+	}
+
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	return cc.lastErr
+}
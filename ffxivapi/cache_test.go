@@ -0,0 +1,55 @@
+package ffxivapi_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/c032/ffxiv-world-status-discord/ffxivapi"
+)
+
+// blockingClient is a `Client` that doesn't respond until `ctx` is done.
+type blockingClient struct{}
+
+func (blockingClient) Worlds(ctx context.Context) (*ffxivapi.WorldsResponse, error) {
+	<-ctx.Done()
+
+	return nil, ctx.Err()
+}
+
+func TestCachedClient_RequestTimeout(t *testing.T) {
+	cc := ffxivapi.NewCachedClient(blockingClient{}, time.Minute)
+	cc.RequestTimeout = 50 * time.Millisecond
+
+	start := time.Now()
+	_, err := cc.Worlds(context.Background())
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("cc.Worlds() error = %v; want %v", err, context.DeadlineExceeded)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("cc.Worlds() took %s", elapsed)
+	}
+
+	// Waiting for a refresh in progress is limited too.
+	refreshCtx, stopRefresh := context.WithCancel(context.Background())
+	defer stopRefresh()
+
+	refreshDone := make(chan struct{})
+	go func() {
+		defer close(refreshDone)
+
+		cc.Refresh(refreshCtx)
+	}()
+
+	// Give the refresh time to start.
+	time.Sleep(10 * time.Millisecond)
+
+	_, err = cc.Worlds(context.Background())
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("cc.Worlds() error while refreshing = %v; want %v", err, context.DeadlineExceeded)
+	}
+
+	stopRefresh()
+	<-refreshDone
+}
@@ -1,24 +1,70 @@
 package ffxivapi
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
 	"strings"
+	"time"
 
 	chttp "github.com/c032/go-http"
+
+	"github.com/c032/ffxiv-world-status-discord/tracing"
 )
 
 const httpUserAgent = "github.com/c032/ffxiv-world-status/discord"
 
-func request[T any](ac *apiClient, method string, path string, body []byte) (*T, error) {
+const (
+	DefaultMaxRetries   = 2
+	DefaultRetryBackoff = 250 * time.Millisecond
+)
+
+// StatusError is returned when the API responds with a non-2xx status.
+type StatusError struct {
+	StatusCode int
+}
+
+func (err *StatusError) Error() string {
+	return fmt.Sprintf("unexpected HTTP status %d", err.StatusCode)
+}
+
+// temporaryError marks errors that might not happen again if the request is
+// retried.
+type temporaryError struct {
+	err error
+}
+
+func (err *temporaryError) Error() string {
+	return err.err.Error()
+}
+
+func (err *temporaryError) Unwrap() error {
+	return err.err
+}
+
+func isTemporary(err error) bool {
+	var tempErr *temporaryError
+
+	return errors.As(err, &tempErr)
+}
+
+func request[T any](ctx context.Context, ac *apiClient, method string, urlStr string, body []byte) (*T, error) {
 	var (
 		err error
 
-		req *http.Request
+		req     *http.Request
+		reqBody io.Reader
 	)
-	req, err = http.NewRequest(http.MethodGet, ac.worldsURL.String(), nil)
+	if body != nil {
+		reqBody = bytes.NewReader(body)
+	}
+
+	req, err = http.NewRequestWithContext(ctx, method, urlStr, reqBody)
 	if err != nil {
 		return nil, fmt.Errorf("could not create request: %w", err)
 	}
@@ -31,10 +77,22 @@ func request[T any](ac *apiClient, method string, path string, body []byte) (*T,
 
 	resp, err = ac.c.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("could not send HTTP request: %w", err)
+		return nil, &temporaryError{fmt.Errorf("could not send HTTP request: %w", err)}
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		statusErr := &StatusError{
+			StatusCode: resp.StatusCode,
+		}
+
+		if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
+			return nil, &temporaryError{statusErr}
+		}
+
+		return nil, statusErr
+	}
+
 	var result T
 
 	dec := json.NewDecoder(resp.Body)
@@ -47,9 +105,76 @@ func request[T any](ac *apiClient, method string, path string, body []byte) (*T,
 	return &result, nil
 }
 
+// requestWithRetries calls `request`, and retries it with exponential backoff
+// while it fails with temporary errors.
+func requestWithRetries[T any](ctx context.Context, ac *apiClient, endpoint string, method string, urlStr string, body []byte) (*T, error) {
+	backoff := ac.retryBackoff
+
+	for attempt := 0; ; attempt++ {
+		attemptCtx, span := ac.tracer.Start(ctx, "ffxivapi.request",
+			tracing.String("endpoint", endpoint),
+			tracing.String("http.method", method),
+			tracing.Int("attempt", attempt+1),
+		)
+		span.SetKind(tracing.SpanKindClient)
+
+		start := time.Now()
+		result, err := request[T](attemptCtx, ac, method, urlStr, body)
+
+		var statusErr *StatusError
+		if errors.As(err, &statusErr) {
+			span.SetAttributes(tracing.Int("http.status_code", statusErr.StatusCode))
+		}
+		span.SetError(err)
+		span.End()
+
+		if ac.observer != nil {
+			ac.observer.ObserveRequest(endpoint, time.Since(start), err)
+		}
+
+		if err == nil {
+			return result, nil
+		}
+
+		if attempt >= ac.maxRetries || !isTemporary(err) || ctx.Err() != nil {
+			return nil, err
+		}
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+
+			return nil, err
+		case <-timer.C:
+		}
+
+		backoff *= 2
+
+		if ac.observer != nil {
+			ac.observer.ObserveRetry(endpoint)
+		}
+	}
+}
+
 type ClientOptions struct {
 	BaseURL string
 	Token   string
+
+	// MaxRetries is how many times a request is retried after failing with
+	// a network error, a 429 or a 5xx status. Zero disables retries.
+	MaxRetries int
+
+	// RetryBackoff is the time to wait before the first retry. It's doubled
+	// after each retry. Defaults to `DefaultRetryBackoff`.
+	RetryBackoff time.Duration
+
+	// Observer receives measurements of every request. It's optional.
+	Observer Observer
+
+	// Tracer records a span for every call, and for every attempt of each
+	// request. It's optional.
+	Tracer *tracing.Tracer
 }
 
 func NewClient(options ClientOptions) (Client, error) {
@@ -58,10 +183,20 @@ func NewClient(options ClientOptions) (Client, error) {
 		return nil, fmt.Errorf("could not create API client: %w", err)
 	}
 
+	retryBackoff := options.RetryBackoff
+	if retryBackoff <= 0 {
+		retryBackoff = DefaultRetryBackoff
+	}
+
 	ac := &apiClient{
 		c:          c,
 		token:      strings.TrimSpace(options.Token),
 		rawBaseURL: options.BaseURL,
+
+		maxRetries:   options.MaxRetries,
+		retryBackoff: retryBackoff,
+		observer:     options.Observer,
+		tracer:       options.Tracer,
 	}
 
 	err = ac.init()
@@ -73,7 +208,7 @@ func NewClient(options ClientOptions) (Client, error) {
 }
 
 type Client interface {
-	Worlds() (*WorldsResponse, error)
+	Worlds(ctx context.Context) (*WorldsResponse, error)
 }
 
 var _ Client = (*apiClient)(nil)
@@ -83,6 +218,11 @@ type apiClient struct {
 
 	token string
 
+	maxRetries   int
+	retryBackoff time.Duration
+	observer     Observer
+	tracer       *tracing.Tracer
+
 	rawBaseURL string
 	baseURL    *url.URL
 
@@ -133,9 +273,14 @@ func (ac *apiClient) resolve(urlStr string) (*url.URL, error) {
 	return parsedURL, nil
 }
 
-func (ac *apiClient) Worlds() (*WorldsResponse, error) {
-	worldsResponse, err := request[WorldsResponse](ac, http.MethodGet, ac.worldsURL.String(), nil)
+func (ac *apiClient) Worlds(ctx context.Context) (*WorldsResponse, error) {
+	ctx, span := ac.tracer.Start(ctx, "ffxivapi.Worlds")
+	defer span.End()
+
+	worldsResponse, err := requestWithRetries[WorldsResponse](ctx, ac, "worlds", http.MethodGet, ac.worldsURL.String(), nil)
 	if err != nil {
+		span.SetError(err)
+
 		return nil, fmt.Errorf("could not fetch worlds: %w", err)
 	}
 
@@ -1,7 +1,13 @@
 package ffxivapi_test
 
 import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/c032/ffxiv-world-status-discord/ffxivapi"
 )
@@ -20,7 +26,7 @@ func TestClient_Worlds(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	worldsResponse, err := c.Worlds()
+	worldsResponse, err := c.Worlds(context.Background())
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -33,3 +39,89 @@ func TestClient_Worlds(t *testing.T) {
 		t.Fatalf("len(c.Worlds().Worlds) = 0; want at least one")
 	}
 }
+
+type countingObserver struct {
+	requests int
+	retries  int
+}
+
+func (o *countingObserver) ObserveRequest(endpoint string, duration time.Duration, err error) {
+	o.requests++
+}
+
+func (o *countingObserver) ObserveRetry(endpoint string) {
+	o.retries++
+}
+
+func (o *countingObserver) ObserveCacheLookup(hit bool) {}
+
+func (o *countingObserver) ObserveWorlds(worlds *ffxivapi.WorldsResponse) {}
+
+func TestClient_Retries(t *testing.T) {
+	var calls atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"worlds":[{"name":"Alpha"}]}`))
+	}))
+	defer ts.Close()
+
+	observer := &countingObserver{}
+	c, err := ffxivapi.NewClient(ffxivapi.ClientOptions{
+		BaseURL:      ts.URL + "/",
+		MaxRetries:   2,
+		RetryBackoff: time.Millisecond,
+		Observer:     observer,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	worldsResponse, err := c.Worlds(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := len(worldsResponse.Worlds), 1; got != want {
+		t.Errorf("len(worldsResponse.Worlds) = %d; want %d", got, want)
+	}
+	if got, want := observer.requests, 3; got != want {
+		t.Errorf("observer.requests = %d; want %d", got, want)
+	}
+	if got, want := observer.retries, 2; got != want {
+		t.Errorf("observer.retries = %d; want %d", got, want)
+	}
+}
+
+func TestClient_StatusError(t *testing.T) {
+	var calls atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		calls.Add(1)
+
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer ts.Close()
+
+	c, err := ffxivapi.NewClient(ffxivapi.ClientOptions{
+		BaseURL:      ts.URL + "/",
+		MaxRetries:   2,
+		RetryBackoff: time.Millisecond,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = c.Worlds(context.Background())
+
+	var statusErr *ffxivapi.StatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusNotFound {
+		t.Errorf("c.Worlds() error = %v; want status %d", err, http.StatusNotFound)
+	}
+	if got, want := calls.Load(), int32(1); got != want {
+		t.Errorf("calls = %d; want %d (4xx must not be retried)", got, want)
+	}
+}
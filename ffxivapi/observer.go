@@ -0,0 +1,24 @@
+package ffxivapi
+
+import (
+	"time"
+)
+
+// Observer receives measurements of the requests to the upstream API, e.g.
+// to export them as metrics.
+//
+// Methods can be called concurrently.
+type Observer interface {
+	// ObserveRequest is called after every attempt of a request.
+	ObserveRequest(endpoint string, duration time.Duration, err error)
+
+	// ObserveRetry is called before retrying a failed request.
+	ObserveRetry(endpoint string)
+
+	// ObserveCacheLookup is called every time `CachedClient` is asked for a
+	// response.
+	ObserveCacheLookup(hit bool)
+
+	// ObserveWorlds is called with every response stored by `CachedClient`.
+	ObserveWorlds(worlds *WorldsResponse)
+}
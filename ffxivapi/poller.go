@@ -0,0 +1,77 @@
+package ffxivapi
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	logger "github.com/c032/go-logger"
+)
+
+const DefaultPollInterval = time.Minute
+
+// Poller keeps a `CachedClient` warm by refreshing it periodically, so
+// callers rarely need to wait for the upstream API.
+type Poller struct {
+	Logger logger.Logger
+
+	Cache *CachedClient
+
+	// Interval is the time between refreshes. Defaults to
+	// `DefaultPollInterval`. It can be changed while running with
+	// `SetInterval`.
+	Interval time.Duration
+
+	intervalMutex sync.Mutex
+}
+
+// SetInterval changes the time between refreshes. It takes effect after the
+// next refresh.
+func (p *Poller) SetInterval(interval time.Duration) {
+	p.intervalMutex.Lock()
+	defer p.intervalMutex.Unlock()
+
+	p.Interval = interval
+}
+
+func (p *Poller) interval() time.Duration {
+	p.intervalMutex.Lock()
+	defer p.intervalMutex.Unlock()
+
+	if p.Interval <= 0 {
+		return DefaultPollInterval
+	}
+
+	return p.Interval
+}
+
+func (p *Poller) logger() logger.Logger {
+	if p.Logger == nil {
+		return logger.Discard
+	}
+
+	return p.Logger
+}
+
+// Run refreshes the cache immediately, and then every `Interval`, until `ctx`
+// is done.
+func (p *Poller) Run(ctx context.Context) {
+	log := p.logger()
+
+	for {
+		_, err := p.Cache.Refresh(ctx)
+		if err != nil && ctx.Err() == nil {
+			log.Errorf("Could not poll worlds: %s", err.Error())
+		}
+
+		timer := time.NewTimer(p.interval())
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+
+			return
+		case <-timer.C:
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package ffxivapi
+
+import (
+	"cmp"
+	"maps"
+	"slices"
+)
+
+// dataCenterRegions maps the name of each logical data center to the region
+// where it's located.
+var dataCenterRegions = map[string]string{
+	"Elemental": "Japan",
+	"Gaia":      "Japan",
+	"Mana":      "Japan",
+	"Meteor":    "Japan",
+
+	"Aether":  "North America",
+	"Crystal": "North America",
+	"Dynamis": "North America",
+	"Primal":  "North America",
+
+	"Chaos":  "Europe",
+	"Light":  "Europe",
+	"Shadow": "Europe",
+
+	"Materia": "Oceania",
+}
+
+// DataCenterRegion returns the region of the data center named `dataCenter`,
+// or an empty string if it's unknown.
+func DataCenterRegion(dataCenter string) string {
+	return dataCenterRegions[dataCenter]
+}
+
+// Region returns the region of the data center of the world, or an empty
+// string if it's unknown.
+func (w World) Region() string {
+	return DataCenterRegion(w.Group)
+}
+
+// DataCenter is a data center with some of its worlds.
+type DataCenter struct {
+	Name   string
+	Worlds []World
+}
+
+// compareDataCenters orders data centers by region and name, with the ones
+// in unknown regions last.
+func compareDataCenters(a, b string) int {
+	regionA := DataCenterRegion(a)
+	regionB := DataCenterRegion(b)
+
+	if (regionA == "") != (regionB == "") {
+		if regionA == "" {
+			return 1
+		}
+
+		return -1
+	}
+
+	return cmp.Or(cmp.Compare(regionA, regionB), cmp.Compare(a, b))
+}
+
+// GroupByDataCenter returns `worlds` grouped by data center. Data centers are
+// sorted by region and name, with the ones in unknown regions last, and
+// worlds by name.
+func GroupByDataCenter(worlds []World) []DataCenter {
+	byName := map[string][]World{}
+	for _, w := range worlds {
+		byName[w.Group] = append(byName[w.Group], w)
+	}
+
+	names := slices.SortedFunc(maps.Keys(byName), compareDataCenters)
+
+	dataCenters := make([]DataCenter, 0, len(names))
+	for _, name := range names {
+		dataCenterWorlds := slices.SortedFunc(slices.Values(byName[name]), func(a, b World) int {
+			return cmp.Compare(a.Name, b.Name)
+		})
+
+		dataCenters = append(dataCenters, DataCenter{
+			Name:   name,
+			Worlds: dataCenterWorlds,
+		})
+	}
+
+	return dataCenters
+}
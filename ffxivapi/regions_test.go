@@ -0,0 +1,56 @@
+package ffxivapi_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/c032/ffxiv-world-status-discord/ffxivapi"
+)
+
+func TestGroupByDataCenter(t *testing.T) {
+	worlds := []ffxivapi.World{
+		{Group: "Unknown", Name: "Somewhere"},
+		{Group: "Light", Name: "Odin"},
+		{Group: "Aether", Name: "Gilgamesh"},
+		{Group: "Light", Name: "Alpha"},
+		{Group: "Chaos", Name: "Omega"},
+		{Group: "Elemental", Name: "Tonberry"},
+	}
+
+	var got []string
+	for _, dataCenter := range ffxivapi.GroupByDataCenter(worlds) {
+		for _, w := range dataCenter.Worlds {
+			got = append(got, dataCenter.Name+"/"+w.Name)
+		}
+	}
+
+	// Europe, Japan and North America, then unknown regions.
+	want := []string{
+		"Chaos/Omega",
+		"Light/Alpha",
+		"Light/Odin",
+		"Elemental/Tonberry",
+		"Aether/Gilgamesh",
+		"Unknown/Somewhere",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("GroupByDataCenter() = %#v; want %#v", got, want)
+	}
+}
+
+func TestWorld_State(t *testing.T) {
+	for _, tc := range []struct {
+		world ffxivapi.World
+		want  ffxivapi.State
+	}{
+		{ffxivapi.World{}, ffxivapi.StateOffline},
+		{ffxivapi.World{IsOnline: true}, ffxivapi.StateOnline},
+		{ffxivapi.World{IsOnline: true, IsPreferred: true}, ffxivapi.StatePreferred},
+		{ffxivapi.World{IsOnline: true, IsPreferred: true, IsCongested: true}, ffxivapi.StateCongested},
+		{ffxivapi.World{IsOnline: true, IsCongested: true, IsMaintenance: true}, ffxivapi.StateMaintenance},
+	} {
+		if got := tc.world.State(); got != tc.want {
+			t.Errorf("%#v.State() = %d; want %d", tc.world, got, tc.want)
+		}
+	}
+}
@@ -16,3 +16,33 @@ type World struct {
 	IsPreferred            bool   `json:"isPreferred"`
 	IsNew                  bool   `json:"isNew"`
 }
+
+// State is the most severe of the states of a world that exclude each other.
+// States that can be combined with any other, like being new, are fields of
+// `World`.
+type State int
+
+const (
+	StateOffline State = iota
+	StateOnline
+	StatePreferred
+	StateCongested
+	StateMaintenance
+)
+
+// State returns the most severe state of the world: maintenance, congested,
+// preferred, online or, if none applies, offline.
+func (w World) State() State {
+	switch {
+	case w.IsMaintenance:
+		return StateMaintenance
+	case w.IsCongested:
+		return StateCongested
+	case w.IsPreferred:
+		return StatePreferred
+	case w.IsOnline:
+		return StateOnline
+	}
+
+	return StateOffline
+}
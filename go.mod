@@ -3,6 +3,7 @@ module github.com/c032/ffxiv-world-status-discord
 go 1.23.3
 
 require (
+	github.com/BurntSushi/toml v1.4.0
 	github.com/bwmarrin/discordgo v0.28.1
 	github.com/c032/go-http v0.0.0-20231209141432-ffee49c569c1
 	github.com/c032/go-logger v0.0.0-20240607170559-97d102556f7a
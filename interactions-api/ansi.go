@@ -0,0 +1,226 @@
+package interactionsapi
+
+import (
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/c032/ffxiv-world-status-discord/ffxivapi"
+)
+
+// ANSI escape codes supported by the `ansi` code blocks of Discord.
+const (
+	ansiReset   = "\x1b[0m"
+	ansiBold    = "\x1b[1m"
+	ansiHeading = "\x1b[1;4m"
+
+	ansiGray   = "\x1b[30m"
+	ansiRed    = "\x1b[31m"
+	ansiGreen  = "\x1b[32m"
+	ansiYellow = "\x1b[33m"
+	ansiBlue   = "\x1b[34m"
+)
+
+const (
+	ansiCodeBlockStart = "```ansi\n"
+	ansiCodeBlockEnd   = "\n```"
+
+	// ansiColumnGap separates the columns of tables.
+	ansiColumnGap = "  "
+)
+
+// ansiStateColors are the colours of the states of worlds, like in the
+// status board.
+var ansiStateColors = map[ffxivapi.State]string{
+	ffxivapi.StateOffline:     ansiGray,
+	ffxivapi.StateOnline:      ansiGreen,
+	ffxivapi.StatePreferred:   ansiBlue,
+	ffxivapi.StateCongested:   ansiYellow,
+	ffxivapi.StateMaintenance: ansiRed,
+}
+
+// ansiColor returns the colour of the most severe state of `w`.
+func ansiColor(w TemplateWorld) string {
+	return ansiStateColors[w.world.State()]
+}
+
+// ansiSpan is a part of a line, written with `Style` (an escape code, or
+// empty for none) and reset afterwards.
+type ansiSpan struct {
+	Style string
+	Text  string
+}
+
+// ansiLine is a line of an `ansi` code block.
+type ansiLine []ansiSpan
+
+// render returns the line with its escape codes, in at most `limit`
+// characters. Only the text of spans is shortened, so escape codes are never
+// cut and every styled span is reset.
+func (line ansiLine) render(limit int) string {
+	var sb strings.Builder
+
+	remaining := limit
+	for _, span := range line {
+		var overhead int
+		if span.Style != "" {
+			overhead = countCharacters(span.Style) + countCharacters(ansiReset)
+		}
+
+		available := remaining - overhead
+		if available <= 0 {
+			break
+		}
+
+		text := truncate(span.Text, available)
+		if span.Style != "" {
+			sb.WriteString(span.Style + text + ansiReset)
+		} else {
+			sb.WriteString(text)
+		}
+
+		remaining -= overhead + countCharacters(text)
+		if text != span.Text {
+			break
+		}
+	}
+
+	return sb.String()
+}
+
+// ansiStyle returns the style of worlds in `ansi` code blocks. Tables show
+// states with colours and their names, so the `world` template is rendered
+// without icons.
+func ansiStyle(locale discordgo.Locale) worldListStyle {
+	return worldListStyle{
+		Locale: locale,
+	}
+}
+
+// padRight pads `s` with spaces up to `width` characters.
+func padRight(s string, width int) string {
+	return s + strings.Repeat(" ", max(0, width-countCharacters(s)))
+}
+
+// ansiCell returns the output of a template as the text of a single cell.
+func ansiCell(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// ansiTable returns the lines of a table with the worlds of `groups`. The
+// columns are the data center, shown only in the first line of each group,
+// the world, coloured by its state, and the names of its states. Data
+// centers and worlds are rendered with the `group_name` and `world`
+// templates of the guild with `guildID`.
+func (rt *ResponseTemplates) ansiTable(guildID string, groups []TemplateGroup) ([]ansiLine, error) {
+	groupNames := make([]string, len(groups))
+	worldNames := make([][]string, len(groups))
+
+	var groupWidth, worldWidth int
+	for i, group := range groups {
+		name, err := rt.execute(guildID, TemplateNameGroupName, group)
+		if err != nil {
+			return nil, err
+		}
+
+		groupNames[i] = ansiCell(name)
+		groupWidth = max(groupWidth, countCharacters(groupNames[i]))
+
+		for _, w := range group.Worlds {
+			name, err := rt.execute(guildID, TemplateNameWorld, w)
+			if err != nil {
+				return nil, err
+			}
+
+			name = ansiCell(name)
+			worldNames[i] = append(worldNames[i], name)
+			worldWidth = max(worldWidth, countCharacters(name))
+		}
+	}
+
+	var lines []ansiLine
+	for i, group := range groups {
+		for j, w := range group.Worlds {
+			var line ansiLine
+
+			if j == 0 {
+				line = append(line, ansiSpan{Style: ansiBold, Text: padRight(groupNames[i], groupWidth)})
+			} else {
+				line = append(line, ansiSpan{Text: padRight("", groupWidth)})
+			}
+
+			line = append(line,
+				ansiSpan{Text: ansiColumnGap},
+				ansiSpan{Style: ansiColor(w), Text: padRight(worldNames[i][j], worldWidth)},
+			)
+
+			if len(w.Labels) > 0 {
+				line = append(line, ansiSpan{Text: ansiColumnGap + strings.Join(w.Labels, ", ")})
+			}
+
+			lines = append(lines, line)
+		}
+	}
+
+	return lines, nil
+}
+
+// ansiSection returns `table` under `title`.
+func ansiSection(title string, table []ansiLine) []ansiLine {
+	return append([]ansiLine{{{Style: ansiHeading, Text: ansiCell(title)}}}, table...)
+}
+
+// ansiCodeBlocks returns `lines` in `ansi` code blocks, split across as many
+// blocks as needed so none exceeds `limit` characters. Empty lines at the
+// start of a block are skipped.
+func ansiCodeBlocks(lines []ansiLine, limit int) []string {
+	overhead := countCharacters(ansiCodeBlockStart) + countCharacters(ansiCodeBlockEnd)
+	lineLimit := limit - overhead
+
+	var (
+		blocks []string
+		block  []string
+		size   int
+	)
+
+	flush := func() {
+		if len(block) == 0 {
+			return
+		}
+
+		blocks = append(blocks, ansiCodeBlockStart+strings.Join(block, "\n")+ansiCodeBlockEnd)
+
+		block = nil
+		size = 0
+	}
+
+	for _, l := range lines {
+		if len(l) == 0 && len(block) == 0 {
+			continue
+		}
+
+		line := l.render(lineLimit)
+
+		lineSize := countCharacters(line)
+		if len(block) > 0 {
+			// Newline that separates it from the previous line.
+			lineSize++
+		}
+
+		if size+lineSize > lineLimit {
+			flush()
+
+			if line == "" {
+				continue
+			}
+
+			lineSize = countCharacters(line)
+		}
+
+		block = append(block, line)
+		size += lineSize
+	}
+	flush()
+
+	return blocks
+}
@@ -0,0 +1,154 @@
+package interactionsapi
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// renderANSILines renders `lines` without a limit.
+func renderANSILines(lines []ansiLine) []string {
+	rendered := make([]string, 0, len(lines))
+	for _, line := range lines {
+		rendered = append(rendered, line.render(messageContentLimit))
+	}
+
+	return rendered
+}
+
+func TestANSITable(t *testing.T) {
+	rt, err := NewResponseTemplates(TemplateOverrides{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data := newTemplateData(ansiStyle(discordgo.EnglishUS), "", e2eWorlds)
+
+	table, err := rt.ansiTable("", data.CharacterCreationUnavailable)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := renderANSILines(table)
+	want := []string{
+		ansiBold + "Light " + ansiReset + "  " + ansiYellow + "Lich     " + ansiReset + "  Congested, Creation locked",
+		"        " + ansiYellow + "Odin     " + ansiReset + "  Congested, Creation locked",
+		ansiBold + "Aether" + ansiReset + "  " + ansiRed + "Gilgamesh" + ansiReset + "  Maintenance, Creation locked",
+	}
+
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("ansiTable() = %#v; want %#v", got, want)
+	}
+}
+
+func TestANSITable_Templates(t *testing.T) {
+	rt, err := NewResponseTemplates(TemplateOverrides{
+		Guilds: map[string]map[string]string{
+			"1234": {
+				TemplateNameGroupName: `DC {{.Name}}`,
+				TemplateNameWorld:     `{{upper .Name}}{{if .New}} (new!){{end}}`,
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	table, err := rt.ansiTable("1234", newTemplateGroups(ansiStyle(discordgo.EnglishUS), e2eWorlds))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := renderANSILines(table)
+	if len(got) == 0 || !strings.HasPrefix(got[0], ansiBold+"DC ") {
+		t.Errorf("ansiTable() = %#v; want the group_name template to be used", got)
+	}
+
+	var hasWorld bool
+	for _, line := range got {
+		if strings.Contains(line, "ALPHA (new!)") {
+			hasWorld = true
+		}
+	}
+	if !hasWorld {
+		t.Errorf("ansiTable() = %#v; want the world template to be used", got)
+	}
+}
+
+func TestANSILine_Render(t *testing.T) {
+	line := ansiLine{
+		{Style: ansiBold, Text: "Light"},
+		{Text: ansiColumnGap},
+		{Style: ansiRed, Text: strings.Repeat("x", 50)},
+		{Text: ansiColumnGap + "Maintenance"},
+	}
+
+	for limit := 0; limit <= 100; limit++ {
+		got := line.render(limit)
+		if n := countCharacters(got); n > limit {
+			t.Errorf("render(%d) has %d characters", limit, n)
+		}
+
+		// Every escape code is complete, and every styled span is reset.
+		rest := got
+		for {
+			i := strings.Index(rest, "\x1b[")
+			if i < 0 {
+				break
+			}
+
+			end := strings.Index(rest[i:], "m")
+			if end < 0 {
+				t.Fatalf("render(%d) = %#v; has an incomplete escape code", limit, got)
+			}
+
+			rest = rest[i+end+1:]
+		}
+		if got != "" && strings.Count(got, "\x1b[") != 2*strings.Count(got, ansiReset) {
+			t.Errorf("render(%d) = %#v; want every style to be reset", limit, got)
+		}
+	}
+
+	if got, want := line.render(messageContentLimit), ansiBold+"Light"+ansiReset+ansiColumnGap+ansiRed+strings.Repeat("x", 50)+ansiReset+ansiColumnGap+"Maintenance"; got != want {
+		t.Errorf("render() = %#v; want %#v", got, want)
+	}
+}
+
+func TestANSICodeBlocks(t *testing.T) {
+	rt, err := NewResponseTemplates(TemplateOverrides{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	table, err := rt.ansiTable("", newTemplateData(ansiStyle(discordgo.EnglishUS), "", manyWorlds()).Maintenance)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lines := ansiSection("Maintenance", table)
+
+	const limit = 500
+
+	blocks := ansiCodeBlocks(lines, limit)
+	if len(blocks) < 2 {
+		t.Fatalf("len(blocks) = %d; want more than 1", len(blocks))
+	}
+
+	var rendered int
+	for i, block := range blocks {
+		if n := countCharacters(block); n > limit {
+			t.Errorf("block %d has %d characters", i, n)
+		}
+		if !strings.HasPrefix(block, ansiCodeBlockStart) || !strings.HasSuffix(block, ansiCodeBlockEnd) {
+			t.Errorf("block %d is not a code block: %#v", i, block)
+		}
+
+		content := strings.TrimSuffix(strings.TrimPrefix(block, ansiCodeBlockStart), ansiCodeBlockEnd)
+		rendered += len(strings.Split(content, "\n"))
+	}
+
+	if rendered != len(lines) {
+		t.Errorf("rendered %d lines; want %d", rendered, len(lines))
+	}
+}
@@ -0,0 +1,175 @@
+package interactionsapi
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+
+	"github.com/bwmarrin/discordgo"
+	logger "github.com/c032/go-logger"
+)
+
+// Application is a Discord application hosted by the server.
+//
+// Each application receives its interactions at `/interactions/{name}`. The
+// first application of the server also receives them at `/interactions`.
+type Application struct {
+	// Name identifies the application in routes and logs.
+	Name string
+
+	ID         string
+	Token      string
+	PublicKeys *PublicKeyRing
+
+	// Commands contains the names of the commands available in this
+	// application. If it's empty, every command is available.
+	Commands []string
+
+	// DevGuildIDs makes commands be registered only in these guilds,
+	// instead of globally. Guild commands are updated instantly, and
+	// registering them doesn't affect the global commands used in
+	// production.
+	DevGuildIDs []string
+
+	// SkipRequestValidation disables the verification of the signature and
+	// timestamp of requests. Only for development.
+	SkipRequestValidation bool
+
+	session  *discordgo.Session
+	commands *CommandRegistry
+}
+
+var applicationNameRegexp = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// validate returns an error if the application can't be used. Public keys
+// are only required when `requirePublicKeys` is `true`, i.e. when the
+// application receives interactions.
+func (app *Application) validate(requirePublicKeys bool) error {
+	if !applicationNameRegexp.MatchString(app.Name) {
+		return fmt.Errorf("application name %#v must match %s", app.Name, applicationNameRegexp.String())
+	}
+
+	if app.ID == "" {
+		return fmt.Errorf("application %#v has no ID", app.Name)
+	}
+
+	if app.Token == "" {
+		return fmt.Errorf("application %#v has no token", app.Name)
+	}
+
+	if requirePublicKeys && app.PublicKeys == nil && !app.SkipRequestValidation {
+		return fmt.Errorf("application %#v has no public keys", app.Name)
+	}
+
+	return nil
+}
+
+// hasCommand returns whether the command named `name` is available in the
+// application.
+func (app *Application) hasCommand(name string) bool {
+	return len(app.Commands) == 0 || slices.Contains(app.Commands, name)
+}
+
+func (s *Server) application(name string) *Application {
+	for _, app := range s.Applications {
+		if app.Name == name {
+			return app
+		}
+	}
+
+	return nil
+}
+
+func (s *Server) initializeApplications(requirePublicKeys bool) error {
+	log := s.logger()
+
+	if len(s.Applications) == 0 {
+		return fmt.Errorf("no applications configured")
+	}
+
+	names := map[string]struct{}{}
+	for _, app := range s.Applications {
+		err := app.validate(requirePublicKeys)
+		if err != nil {
+			return err
+		}
+
+		if _, ok := names[app.Name]; ok {
+			return fmt.Errorf("application %#v is duplicated", app.Name)
+		}
+		names[app.Name] = struct{}{}
+	}
+
+	for _, app := range s.Applications {
+		log.WithFields(logger.Fields{
+			"application": app.Name,
+		}).Print("Initializing application.")
+
+		var err error
+
+		app.commands, err = s.newCommandRegistry(app)
+		if err != nil {
+			return fmt.Errorf("could not initialize commands of application %#v: %w", app.Name, err)
+		}
+
+		app.session, err = discordgo.New("Bot " + app.Token)
+		if err != nil {
+			return fmt.Errorf("could not initialize Discord session of application %#v: %w", app.Name, err)
+		}
+		if s.DiscordAPIURL != "" {
+			app.session.Client, err = NewDiscordHTTPClient(s.DiscordAPIURL)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (s *Server) cleanupApplications() error {
+	log := s.logger()
+
+	var errs []error
+	for _, app := range s.Applications {
+		if app.session == nil {
+			continue
+		}
+
+		log.WithFields(logger.Fields{
+			"application": app.Name,
+		}).Print("Closing Discord session.")
+
+		err := app.session.Close()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("could not close Discord session of application %#v: %w", app.Name, err))
+
+			continue
+		}
+
+		app.session = nil
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("could not cleanup applications: %v", errs)
+	}
+
+	log.Print("Discord sessions closed successfully.")
+
+	return nil
+}
+
+type applicationContextKey struct{}
+
+func withApplication(ctx context.Context, app *Application) context.Context {
+	return context.WithValue(ctx, applicationContextKey{}, app)
+}
+
+// applicationFromContext returns the application that received the request
+// with context `ctx`.
+func applicationFromContext(ctx context.Context) *Application {
+	app, _ := ctx.Value(applicationContextKey{}).(*Application)
+
+	return app
+}
@@ -0,0 +1,61 @@
+package interactionsapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestApplication_HasCommand(t *testing.T) {
+	app := &Application{}
+	if !app.hasCommand(CmdPing) {
+		t.Errorf("app.hasCommand(%#v) = false; want true", CmdPing)
+	}
+
+	app.Commands = []string{CmdCharacters}
+	if app.hasCommand(CmdPing) {
+		t.Errorf("app.hasCommand(%#v) = true; want false", CmdPing)
+	}
+	if !app.hasCommand(CmdCharacters) {
+		t.Errorf("app.hasCommand(%#v) = false; want true", CmdCharacters)
+	}
+}
+
+func TestRouter_Applications(t *testing.T) {
+	s := &Server{
+		Applications: []*Application{
+			{Name: "first", SkipRequestValidation: true},
+			{Name: "second", SkipRequestValidation: true},
+		},
+	}
+
+	err := s.initializeRouter()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path string
+		want int
+	}{
+		{"/interactions", http.StatusOK},
+		{"/interactions/first", http.StatusOK},
+		{"/interactions/second", http.StatusOK},
+		{"/interactions/third", http.StatusNotFound},
+	}
+
+	for i, tt := range tests {
+		body := `{"id":"` + strconv.Itoa(i) + `","type":1}`
+		req := httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		w := httptest.NewRecorder()
+		s.ServeHTTP(w, req)
+
+		if got := w.Code; got != tt.want {
+			t.Errorf("status of %s = %d; want %d", tt.path, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package interactionsapi
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/c032/ffxiv-world-status-discord/ffxivapi"
+	"github.com/c032/ffxiv-world-status-discord/statusboard"
+)
+
+// boardFileName is the name of the attached image with the status board.
+const boardFileName = "status.png"
+
+// boardTitle is the title of boards with every world.
+const boardTitle = "FFXIV world status"
+
+// boardFile renders the status board of `worlds`, with `title`, as a file
+// that can be attached to a response.
+func (s *Server) boardFile(ctx context.Context, title string, worlds []ffxivapi.World) (*discordgo.File, error) {
+	_, span := s.Tracer.Start(ctx, "render board")
+	defer span.End()
+
+	var buf bytes.Buffer
+
+	err := statusboard.EncodePNG(&buf, worlds, statusboard.Options{
+		Title: title,
+	})
+	if err != nil {
+		err = fmt.Errorf("could not render board: %w", err)
+
+		span.SetError(err)
+
+		return nil, err
+	}
+
+	return &discordgo.File{
+		Name:        boardFileName,
+		ContentType: statusboard.ContentType,
+		Reader:      bytes.NewReader(buf.Bytes()),
+	}, nil
+}
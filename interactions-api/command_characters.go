@@ -0,0 +1,349 @@
+package interactionsapi
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/c032/ffxiv-world-status-discord/ffxivapi"
+)
+
+// componentArgPage is the first argument of the custom ID of the buttons to
+// change pages. The second one is the index of the page.
+const componentArgPage = "page"
+
+type charactersCommand struct {
+	s *Server
+}
+
+func (cmd *charactersCommand) ApplicationCommand() *discordgo.ApplicationCommand {
+	return &discordgo.ApplicationCommand{
+		Name: CmdCharacters,
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type: discordgo.ApplicationCommandOptionBoolean,
+				Name: OptEphemeral,
+			},
+			{
+				Type: discordgo.ApplicationCommandOptionBoolean,
+				Name: OptImage,
+			},
+			formatOption(),
+		},
+	}
+}
+
+func (cmd *charactersCommand) Execute(req *InteractionRequest) (*discordgo.InteractionResponse, error) {
+	s := cmd.s
+
+	pages, err := cmd.render(req, s.responseFormat(req))
+	if err != nil {
+		return nil, err
+	}
+
+	var flags discordgo.MessageFlags
+	if s.isEphemeral(req) {
+		flags |= discordgo.MessageFlagsEphemeral
+	}
+
+	interactionResponse := &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: cmd.page(req.Locale, pages, 0),
+	}
+	interactionResponse.Data.Flags = flags
+
+	if s.includeImage(req.Interaction.ApplicationCommandData()) {
+		file, err := s.boardFile(req.Context, boardTitle, pages.worlds)
+		if err != nil {
+			return nil, err
+		}
+
+		interactionResponse.Data.Files = []*discordgo.File{file}
+	}
+
+	return interactionResponse, nil
+}
+
+// HandleComponent shows another page of the response, when one of the
+// buttons to change pages is clicked.
+func (cmd *charactersCommand) HandleComponent(req *InteractionRequest, args []string) (*discordgo.InteractionResponse, error) {
+	if len(args) < 2 || len(args) > 3 || args[0] != componentArgPage {
+		return nil, fmt.Errorf("%w: unknown component arguments %#v", ErrUnsupportedInteractionType, args)
+	}
+
+	format := FormatEmbed
+	if len(args) == 3 {
+		format = parseFormat(args[2])
+	}
+
+	pageIndex, err := strconv.Atoi(args[1])
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid page %#v", ErrUnsupportedInteractionType, args[1])
+	}
+
+	pages, err := cmd.render(req, format)
+	if err != nil {
+		return nil, err
+	}
+
+	// Worlds can change between pages, so there could be fewer now.
+	pageIndex = max(0, min(pageIndex, pages.count()-1))
+
+	data := cmd.page(req.Locale, pages, pageIndex)
+
+	// Empty instead of `nil`, so the message loses the embeds and buttons of
+	// the previous page.
+	if data.Embeds == nil {
+		data.Embeds = []*discordgo.MessageEmbed{}
+	}
+	if data.Components == nil {
+		data.Components = []discordgo.MessageComponent{}
+	}
+
+	interactionResponse := &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseUpdateMessage,
+		Data: data,
+	}
+
+	return interactionResponse, nil
+}
+
+// charactersPages contains every page of the response.
+type charactersPages struct {
+	format string
+
+	// pages contains the pages in `FormatEmbed`.
+	pages []embedPage
+
+	// blocks contains the pages in `FormatANSI`.
+	blocks []string
+
+	// content is the content of the response when there are no pages.
+	content string
+
+	// legend explains the icons used in the pages. It's shown in the footer
+	// of every page.
+	legend string
+
+	// worlds contains every world, including the ones that are not shown
+	// in the pages.
+	worlds []ffxivapi.World
+}
+
+// count returns the number of pages.
+func (cp *charactersPages) count() int {
+	if cp.format == FormatANSI {
+		return len(cp.blocks)
+	}
+
+	return len(cp.pages)
+}
+
+// render returns every page of the response with the current status of the
+// worlds, in `format`.
+func (cmd *charactersCommand) render(req *InteractionRequest, format string) (*charactersPages, error) {
+	s := cmd.s
+	settings := s.settings()
+	guildID := req.Interaction.GuildID
+
+	wr, err := s.API.Worlds(req.Context)
+	if err != nil {
+		return nil, upstreamError(err)
+	}
+
+	_, span := s.Tracer.Start(req.Context, "render embeds")
+	defer span.End()
+
+	style := worldListStyle{
+		Locale:    req.Locale,
+		Icons:     settings.StatusIcons.withDefaults(),
+		PlainText: s.guildSettings(req).PlainText,
+	}
+	if format == FormatANSI {
+		style = ansiStyle(req.Locale)
+	}
+
+	data := newTemplateData(style, guildID, wr.Worlds)
+
+	var (
+		sections   []embedSection
+		ansiLines  []ansiLine
+		usedWorlds []ffxivapi.World
+	)
+
+	for _, part := range []struct {
+		titleTemplate string
+		groups        []TemplateGroup
+	}{
+		{TemplateNameMaintenanceTitle, data.Maintenance},
+		{TemplateNameCharacterCreationTitle, data.CharacterCreationUnavailable},
+	} {
+		if len(part.groups) == 0 {
+			continue
+		}
+
+		if format == FormatANSI {
+			title, err := s.Templates.execute(guildID, part.titleTemplate, data)
+			if err != nil {
+				span.SetError(err)
+
+				return nil, err
+			}
+
+			table, err := s.Templates.ansiTable(guildID, part.groups)
+			if err != nil {
+				span.SetError(err)
+
+				return nil, err
+			}
+
+			if len(ansiLines) > 0 {
+				ansiLines = append(ansiLines, nil)
+			}
+			ansiLines = append(ansiLines, ansiSection(title, table)...)
+
+			continue
+		}
+
+		section, sectionWorlds, err := cmd.renderSection(guildID, data, part.titleTemplate, part.groups)
+		if err != nil {
+			span.SetError(err)
+
+			return nil, err
+		}
+
+		sections = append(sections, section)
+		usedWorlds = append(usedWorlds, sectionWorlds...)
+	}
+
+	cp := &charactersPages{
+		format: format,
+		worlds: wr.Worlds,
+	}
+
+	switch format {
+	case FormatANSI:
+		cp.blocks = ansiCodeBlocks(ansiLines, messageContentLimit-pageNumberReserve)
+	default:
+		cp.legend = truncate(style.legend(usedWorlds), embedFooterLimit-pageNumberReserve)
+		cp.pages = renderEmbedPages(sections, settings.DiscordThumbnailURL, countCharacters(cp.legend)+pageNumberReserve)
+	}
+
+	if cp.count() == 0 {
+		content, err := s.Templates.execute(guildID, TemplateNameEverythingGood, data)
+		if err != nil {
+			span.SetError(err)
+
+			return nil, err
+		}
+
+		cp.content = truncate(content, messageContentLimit)
+	}
+
+	return cp, nil
+}
+
+// renderSection renders `groups` with the templates of the guild, under the
+// title rendered by `titleTemplate`. It also returns the rendered worlds.
+func (cmd *charactersCommand) renderSection(guildID string, data TemplateData, titleTemplate string, groups []TemplateGroup) (embedSection, []ffxivapi.World, error) {
+	templates := cmd.s.Templates
+
+	title, err := templates.execute(guildID, titleTemplate, data)
+	if err != nil {
+		return embedSection{}, nil, err
+	}
+
+	worldGroups, worlds, err := templates.embedGroups(guildID, groups)
+	if err != nil {
+		return embedSection{}, nil, err
+	}
+
+	section := embedSection{
+		Title:  title,
+		Color:  severityColor(worlds),
+		Groups: worldGroups,
+	}
+
+	return section, worlds, nil
+}
+
+// page returns the message with the page at `pageIndex`, and the buttons to
+// change pages if there is more than one.
+func (cmd *charactersCommand) page(locale discordgo.Locale, cp *charactersPages, pageIndex int) *discordgo.InteractionResponseData {
+	pageCount := cp.count()
+
+	if pageCount == 0 {
+		return &discordgo.InteractionResponseData{
+			Content: cp.content,
+		}
+	}
+
+	pageNumber := pageNumber(locale, pageIndex, pageCount)
+
+	var data *discordgo.InteractionResponseData
+	switch cp.format {
+	case FormatANSI:
+		content := cp.blocks[pageIndex]
+		if pageNumber != "" {
+			content += "\n" + pageNumber
+		}
+
+		data = &discordgo.InteractionResponseData{
+			Content: content,
+		}
+	default:
+		data = &discordgo.InteractionResponseData{
+			Embeds: cp.embeds(pageIndex, pageNumber),
+		}
+	}
+
+	data.Components = pageButtons(locale, pageIndex, pageCount, func(pageIndex int) string {
+		return pageCustomID(cp.format, pageIndex)
+	})
+
+	return data
+}
+
+// embeds returns the embeds of the page at `pageIndex`, with the legend and
+// `pageNumber` in the footer.
+func (cp *charactersPages) embeds(pageIndex int, pageNumber string) []*discordgo.MessageEmbed {
+	var footer []string
+	if cp.legend != "" {
+		footer = append(footer, cp.legend)
+	}
+	if pageNumber != "" {
+		footer = append(footer, pageNumber)
+	}
+
+	embeds := cp.pages[pageIndex]
+	if len(footer) == 0 {
+		return embeds
+	}
+
+	// The embeds are shared by the pages rendered in the same request, so
+	// the last one is copied instead of changing it.
+	embeds = slices.Clone(embeds)
+
+	lastEmbed := *embeds[len(embeds)-1]
+	lastEmbed.Footer = &discordgo.MessageEmbedFooter{
+		Text: strings.Join(footer, "\n"),
+	}
+	embeds[len(embeds)-1] = &lastEmbed
+
+	return embeds
+}
+
+// pageCustomID returns the custom ID of the button that shows the page at
+// `pageIndex` in `format`. The format is omitted for `FormatEmbed`, so the
+// buttons of older messages keep working.
+func pageCustomID(format string, pageIndex int) string {
+	args := []string{componentArgPage, strconv.Itoa(pageIndex)}
+	if format != FormatEmbed {
+		args = append(args, format)
+	}
+
+	return componentCustomID(CmdCharacters, args...)
+}
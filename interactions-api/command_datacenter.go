@@ -0,0 +1,333 @@
+package interactionsapi
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/c032/ffxiv-world-status-discord/ffxivapi"
+)
+
+// maxAutocompleteChoices is the maximum number of choices that Discord
+// accepts in the response to an autocompletion.
+const maxAutocompleteChoices = 25
+
+type dataCenterCommand struct {
+	s *Server
+}
+
+func (cmd *dataCenterCommand) ApplicationCommand() *discordgo.ApplicationCommand {
+	return &discordgo.ApplicationCommand{
+		Name: CmdDataCenter,
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:         discordgo.ApplicationCommandOptionString,
+				Name:         OptName,
+				Required:     true,
+				Autocomplete: true,
+			},
+			{
+				Type: discordgo.ApplicationCommandOptionBoolean,
+				Name: OptEphemeral,
+			},
+			{
+				Type: discordgo.ApplicationCommandOptionBoolean,
+				Name: OptImage,
+			},
+			formatOption(),
+		},
+	}
+}
+
+func (cmd *dataCenterCommand) Execute(req *InteractionRequest) (*discordgo.InteractionResponse, error) {
+	s := cmd.s
+	data := req.Interaction.ApplicationCommandData()
+
+	name, _ := stringOption(data, OptName)
+
+	wr, err := s.API.Worlds(req.Context)
+	if err != nil {
+		return nil, upstreamError(err)
+	}
+
+	worlds := dataCenterWorlds(wr.Worlds, name)
+	if len(worlds) == 0 {
+		content := fmt.Sprintf(localize(req.Locale, msgUnknownDataCenter), name)
+
+		return messageResponse(truncate(content, messageContentLimit), discordgo.MessageFlagsEphemeral), nil
+	}
+
+	var responseData *discordgo.InteractionResponseData
+	if s.responseFormat(req) == FormatANSI {
+		responseData, err = cmd.ansiPage(req, worlds, 0)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if responseData == nil {
+		embed, err := cmd.render(req, worlds)
+		if err != nil {
+			return nil, err
+		}
+
+		responseData = &discordgo.InteractionResponseData{
+			Embeds: []*discordgo.MessageEmbed{embed},
+		}
+	}
+
+	if s.isEphemeral(req) {
+		responseData.Flags |= discordgo.MessageFlagsEphemeral
+	}
+
+	interactionResponse := &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: responseData,
+	}
+
+	if s.includeImage(data) {
+		file, err := s.boardFile(req.Context, worlds[0].Group, worlds)
+		if err != nil {
+			return nil, err
+		}
+
+		interactionResponse.Data.Files = []*discordgo.File{file}
+	}
+
+	return interactionResponse, nil
+}
+
+// render returns an embed with every world of a single data center.
+func (cmd *dataCenterCommand) render(req *InteractionRequest, worlds []ffxivapi.World) (*discordgo.MessageEmbed, error) {
+	s := cmd.s
+	settings := s.settings()
+	guildID := req.Interaction.GuildID
+
+	_, span := s.Tracer.Start(req.Context, "render embeds")
+	defer span.End()
+
+	style := worldListStyle{
+		Locale:    req.Locale,
+		Icons:     settings.StatusIcons.withDefaults(),
+		PlainText: s.guildSettings(req).PlainText,
+	}
+
+	group := newTemplateGroups(style, worlds)[0]
+
+	title, err := s.Templates.execute(guildID, TemplateNameGroupName, group)
+	if err != nil {
+		span.SetError(err)
+
+		return nil, err
+	}
+
+	lines := make([]string, 0, len(group.Worlds))
+	for _, w := range group.Worlds {
+		line, err := s.Templates.execute(guildID, TemplateNameWorld, w)
+		if err != nil {
+			span.SetError(err)
+
+			return nil, err
+		}
+
+		lines = append(lines, line)
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title:       truncate(title, embedTitleLimit),
+		Description: truncate(strings.Join(lines, "\n"), embedDescriptionLimit),
+		Color:       severityColor(worlds),
+	}
+	if settings.DiscordThumbnailURL != "" {
+		embed.Thumbnail = &discordgo.MessageEmbedThumbnail{
+			URL: settings.DiscordThumbnailURL,
+		}
+	}
+
+	footerLimit := min(embedFooterLimit, messageEmbedsCharactersLimit-countCharacters(embed.Title)-countCharacters(embed.Description))
+	if legend := style.legend(worlds); legend != "" && footerLimit > 0 {
+		embed.Footer = &discordgo.MessageEmbedFooter{
+			Text: truncate(legend, footerLimit),
+		}
+	}
+
+	return embed, nil
+}
+
+// renderANSI returns the pages of a table, in `ansi` code blocks, with every
+// world of a single data center.
+func (cmd *dataCenterCommand) renderANSI(req *InteractionRequest, worlds []ffxivapi.World) ([]string, error) {
+	s := cmd.s
+
+	_, span := s.Tracer.Start(req.Context, "render ansi")
+	defer span.End()
+
+	table, err := s.Templates.ansiTable(req.Interaction.GuildID, newTemplateGroups(ansiStyle(req.Locale), worlds))
+	if err != nil {
+		span.SetError(err)
+
+		return nil, err
+	}
+
+	return ansiCodeBlocks(table, messageContentLimit-pageNumberReserve), nil
+}
+
+// ansiPage returns the message with the page at `pageIndex` of the table of
+// `worlds`, and the buttons to change pages if there is more than one. It
+// returns `nil` if the table is empty.
+func (cmd *dataCenterCommand) ansiPage(req *InteractionRequest, worlds []ffxivapi.World, pageIndex int) (*discordgo.InteractionResponseData, error) {
+	blocks, err := cmd.renderANSI(req, worlds)
+	if err != nil {
+		return nil, err
+	}
+	if len(blocks) == 0 {
+		return nil, nil
+	}
+
+	// Worlds can change between pages, so there could be fewer now.
+	pageIndex = max(0, min(pageIndex, len(blocks)-1))
+
+	content := blocks[pageIndex]
+	if pageNumber := pageNumber(req.Locale, pageIndex, len(blocks)); pageNumber != "" {
+		content += "\n" + pageNumber
+	}
+
+	dataCenter := worlds[0].Group
+
+	return &discordgo.InteractionResponseData{
+		Content: content,
+		Components: pageButtons(req.Locale, pageIndex, len(blocks), func(pageIndex int) string {
+			return componentCustomID(CmdDataCenter, componentArgPage, strconv.Itoa(pageIndex), dataCenter)
+		}),
+	}, nil
+}
+
+// HandleComponent shows another page of a table, when one of the buttons to
+// change pages is clicked. The arguments are `componentArgPage`, the index
+// of the page and the name of the data center.
+func (cmd *dataCenterCommand) HandleComponent(req *InteractionRequest, args []string) (*discordgo.InteractionResponse, error) {
+	s := cmd.s
+
+	if len(args) < 3 || args[0] != componentArgPage {
+		return nil, fmt.Errorf("%w: unknown component arguments %#v", ErrUnsupportedInteractionType, args)
+	}
+
+	pageIndex, err := strconv.Atoi(args[1])
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid page %#v", ErrUnsupportedInteractionType, args[1])
+	}
+
+	name := strings.Join(args[2:], componentCustomIDSeparator)
+
+	wr, err := s.API.Worlds(req.Context)
+	if err != nil {
+		return nil, upstreamError(err)
+	}
+
+	worlds := dataCenterWorlds(wr.Worlds, name)
+	if len(worlds) == 0 {
+		content := fmt.Sprintf(localize(req.Locale, msgUnknownDataCenter), name)
+
+		return messageResponse(truncate(content, messageContentLimit), discordgo.MessageFlagsEphemeral), nil
+	}
+
+	data, err := cmd.ansiPage(req, worlds, pageIndex)
+	if err != nil {
+		return nil, err
+	}
+	if data == nil {
+		embed, err := cmd.render(req, worlds)
+		if err != nil {
+			return nil, err
+		}
+
+		data = &discordgo.InteractionResponseData{
+			Embeds: []*discordgo.MessageEmbed{embed},
+		}
+	}
+
+	// Empty instead of `nil`, so the message loses the content, embeds and
+	// buttons of the previous page.
+	if data.Embeds == nil {
+		data.Embeds = []*discordgo.MessageEmbed{}
+	}
+	if data.Components == nil {
+		data.Components = []discordgo.MessageComponent{}
+	}
+
+	return &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseUpdateMessage,
+		Data: data,
+	}, nil
+}
+
+// Autocomplete suggests the data centers whose names contain what has been
+// typed so far.
+func (cmd *dataCenterCommand) Autocomplete(req *InteractionRequest) (*discordgo.InteractionResponse, error) {
+	s := cmd.s
+	log := s.requestLogger(req.Context)
+
+	var query string
+	for _, option := range req.Interaction.ApplicationCommandData().Options {
+		if option.Focused && option.Type == discordgo.ApplicationCommandOptionString {
+			query = strings.ToLower(option.StringValue())
+		}
+	}
+
+	choices := []*discordgo.ApplicationCommandOptionChoice{}
+
+	wr, err := s.API.Worlds(req.Context)
+	if err != nil {
+		// There's no way to show an error while autocompleting, so no
+		// choices are suggested.
+		log.Errorf("could not list data centers: %s", err.Error())
+	} else {
+		for _, name := range dataCenterNames(wr.Worlds) {
+			if len(choices) >= maxAutocompleteChoices {
+				break
+			}
+
+			if strings.Contains(strings.ToLower(name), query) {
+				choices = append(choices, &discordgo.ApplicationCommandOptionChoice{
+					Name:  name,
+					Value: name,
+				})
+			}
+		}
+	}
+
+	interactionResponse := &discordgo.InteractionResponse{
+		Type: discordgo.InteractionApplicationCommandAutocompleteResult,
+		Data: &discordgo.InteractionResponseData{
+			Choices: choices,
+		},
+	}
+
+	return interactionResponse, nil
+}
+
+// dataCenterWorlds returns the worlds of the data center named `name`,
+// ignoring case.
+func dataCenterWorlds(worlds []ffxivapi.World, name string) []ffxivapi.World {
+	var result []ffxivapi.World
+	for _, w := range worlds {
+		if strings.EqualFold(w.Group, name) {
+			result = append(result, w)
+		}
+	}
+
+	return result
+}
+
+// dataCenterNames returns the sorted names of the data centers of `worlds`.
+func dataCenterNames(worlds []ffxivapi.World) []string {
+	names := map[string]struct{}{}
+	for _, w := range worlds {
+		names[w.Group] = struct{}{}
+	}
+
+	return slices.Sorted(maps.Keys(names))
+}
@@ -0,0 +1,86 @@
+package interactionsapi
+
+import (
+	"context"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// InteractionRequest contains an interaction that is being handled.
+type InteractionRequest struct {
+	Context     context.Context
+	Application *Application
+	Interaction *discordgo.Interaction
+
+	// Locale is the locale that should be used for the response.
+	Locale discordgo.Locale
+
+	// CommandName is the name of the command the interaction belongs to.
+	CommandName string
+}
+
+// InteractionHandler handles an interaction, and returns the response that
+// should be sent back to Discord.
+type InteractionHandler interface {
+	HandleInteraction(req *InteractionRequest) (*discordgo.InteractionResponse, error)
+}
+
+// InteractionHandlerFunc is an adapter to use ordinary functions as
+// `InteractionHandler`.
+type InteractionHandlerFunc func(req *InteractionRequest) (*discordgo.InteractionResponse, error)
+
+func (f InteractionHandlerFunc) HandleInteraction(req *InteractionRequest) (*discordgo.InteractionResponse, error) {
+	return f(req)
+}
+
+// InteractionMiddleware wraps an `InteractionHandler`, e.g. to run code
+// before or after it, or to respond without calling it.
+type InteractionMiddleware func(next InteractionHandler) InteractionHandler
+
+// CommandHandler bundles the definition of an application command with the
+// code that runs when the command is used.
+//
+// Command handlers can also implement `AutocompleteHandler` and
+// `ComponentHandler`.
+type CommandHandler interface {
+	// ApplicationCommand returns the definition of the command.
+	//
+	// Only the untranslated name of the command and its options must be
+	// set. Descriptions and localizations are filled from the catalogue
+	// when the command is registered.
+	ApplicationCommand() *discordgo.ApplicationCommand
+
+	// Execute handles the usage of the command.
+	Execute(req *InteractionRequest) (*discordgo.InteractionResponse, error)
+}
+
+// AutocompleteHandler is implemented by commands that have options with
+// autocompletion.
+type AutocompleteHandler interface {
+	Autocomplete(req *InteractionRequest) (*discordgo.InteractionResponse, error)
+}
+
+// ComponentHandler is implemented by commands whose responses contain
+// message components.
+//
+// The custom ID of each component must be created with `componentCustomID`,
+// so interactions with the component are dispatched back to the command.
+type ComponentHandler interface {
+	HandleComponent(req *InteractionRequest, args []string) (*discordgo.InteractionResponse, error)
+}
+
+const componentCustomIDSeparator = ":"
+
+// componentCustomID returns the custom ID of a message component that belongs
+// to `commandName`. The `args` are passed back to the `ComponentHandler`.
+func componentCustomID(commandName string, args ...string) string {
+	return strings.Join(append([]string{commandName}, args...), componentCustomIDSeparator)
+}
+
+// parseComponentCustomID is the inverse of `componentCustomID`.
+func parseComponentCustomID(customID string) (commandName string, args []string) {
+	parts := strings.Split(customID, componentCustomIDSeparator)
+
+	return parts[0], parts[1:]
+}
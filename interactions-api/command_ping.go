@@ -0,0 +1,17 @@
+package interactionsapi
+
+import (
+	"github.com/bwmarrin/discordgo"
+)
+
+type pingCommand struct{}
+
+func (cmd *pingCommand) ApplicationCommand() *discordgo.ApplicationCommand {
+	return &discordgo.ApplicationCommand{
+		Name: CmdPing,
+	}
+}
+
+func (cmd *pingCommand) Execute(req *InteractionRequest) (*discordgo.InteractionResponse, error) {
+	return messageResponse(localize(req.Locale, msgPong), 0), nil
+}
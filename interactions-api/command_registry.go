@@ -0,0 +1,172 @@
+package interactionsapi
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+var (
+	ErrUnknownCommand             = errors.New("unknown command")
+	ErrUnsupportedInteractionType = errors.New("unsupported interaction type")
+)
+
+// CommandRegistry contains the commands known by the server. It's used both
+// to register the commands in Discord, and to dispatch interactions to them.
+type CommandRegistry struct {
+	handlers    map[string]CommandHandler
+	commands    map[string]*discordgo.ApplicationCommand
+	middlewares []InteractionMiddleware
+}
+
+func NewCommandRegistry() *CommandRegistry {
+	return &CommandRegistry{
+		handlers: map[string]CommandHandler{},
+		commands: map[string]*discordgo.ApplicationCommand{},
+	}
+}
+
+// Register adds a command to the registry.
+func (r *CommandRegistry) Register(h CommandHandler) error {
+	cmd := h.ApplicationCommand()
+	if cmd == nil || cmd.Name == "" {
+		return fmt.Errorf("command has no name")
+	}
+
+	if _, ok := r.handlers[cmd.Name]; ok {
+		return fmt.Errorf("command %#v is already registered", cmd.Name)
+	}
+
+	localizeCommand(cmd)
+
+	r.handlers[cmd.Name] = h
+	r.commands[cmd.Name] = cmd
+
+	return nil
+}
+
+// Use appends middlewares to the chain that wraps every handler.
+//
+// Middlewares run in the order they were added.
+func (r *CommandRegistry) Use(middlewares ...InteractionMiddleware) {
+	r.middlewares = append(r.middlewares, middlewares...)
+}
+
+// ApplicationCommands returns the definitions of all registered commands,
+// sorted by name.
+func (r *CommandRegistry) ApplicationCommands() []*discordgo.ApplicationCommand {
+	var names []string
+	for name := range r.commands {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	commands := make([]*discordgo.ApplicationCommand, 0, len(names))
+	for _, name := range names {
+		commands = append(commands, r.commands[name])
+	}
+
+	return commands
+}
+
+// ApplicationCommand returns the definition of the command named `name`, or
+// `nil` if there is no such command.
+func (r *CommandRegistry) ApplicationCommand(name string) *discordgo.ApplicationCommand {
+	return r.commands[name]
+}
+
+// Dispatch sends the interaction to the command it belongs to, through the
+// middleware chain.
+//
+// It returns `ErrUnknownCommand` if the command is not registered, and
+// `ErrUnsupportedInteractionType` if the command can't handle the type of
+// interaction.
+func (r *CommandRegistry) Dispatch(req *InteractionRequest) (*discordgo.InteractionResponse, error) {
+	var (
+		commandName string
+		handle      InteractionHandlerFunc
+	)
+
+	interaction := req.Interaction
+
+	switch interaction.Type {
+	case discordgo.InteractionApplicationCommand:
+		commandName = interaction.ApplicationCommandData().Name
+
+		h, ok := r.handlers[commandName]
+		if !ok {
+			return nil, fmt.Errorf("%w: %#v", ErrUnknownCommand, commandName)
+		}
+
+		handle = h.Execute
+	case discordgo.InteractionApplicationCommandAutocomplete:
+		commandName = interaction.ApplicationCommandData().Name
+
+		h, ok := r.handlers[commandName]
+		if !ok {
+			return nil, fmt.Errorf("%w: %#v", ErrUnknownCommand, commandName)
+		}
+
+		ah, ok := h.(AutocompleteHandler)
+		if !ok {
+			return nil, fmt.Errorf("%w: command %#v has no autocompletion", ErrUnsupportedInteractionType, commandName)
+		}
+
+		handle = ah.Autocomplete
+	case discordgo.InteractionMessageComponent:
+		var args []string
+
+		commandName, args = parseComponentCustomID(interaction.MessageComponentData().CustomID)
+
+		h, ok := r.handlers[commandName]
+		if !ok {
+			return nil, fmt.Errorf("%w: %#v", ErrUnknownCommand, commandName)
+		}
+
+		ch, ok := h.(ComponentHandler)
+		if !ok {
+			return nil, fmt.Errorf("%w: command %#v has no components", ErrUnsupportedInteractionType, commandName)
+		}
+
+		handle = func(req *InteractionRequest) (*discordgo.InteractionResponse, error) {
+			return ch.HandleComponent(req, args)
+		}
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedInteractionType, interaction.Type)
+	}
+
+	req.CommandName = commandName
+
+	var handler InteractionHandler = handle
+	for i := len(r.middlewares) - 1; i >= 0; i-- {
+		handler = r.middlewares[i](handler)
+	}
+
+	return handler.HandleInteraction(req)
+}
+
+// localizeCommand fills the descriptions and localizations of the command
+// and its options from the catalogue.
+func localizeCommand(cmd *discordgo.ApplicationCommand) {
+	nameLocalizations := localizations(commandNameKey(cmd.Name))
+	descriptionLocalizations := localizations(commandDescriptionKey(cmd.Name))
+
+	cmd.NameLocalizations = &nameLocalizations
+	cmd.Description = localize(defaultLocale, commandDescriptionKey(cmd.Name))
+	cmd.DescriptionLocalizations = &descriptionLocalizations
+
+	for _, option := range cmd.Options {
+		option.NameLocalizations = localizations(commandOptionNameKey(cmd.Name, option.Name))
+		option.Description = localize(defaultLocale, commandOptionDescriptionKey(cmd.Name, option.Name))
+		option.DescriptionLocalizations = localizations(commandOptionDescriptionKey(cmd.Name, option.Name))
+
+		for _, choice := range option.Choices {
+			key := commandOptionChoiceNameKey(cmd.Name, option.Name, fmt.Sprint(choice.Value))
+
+			choice.Name = localize(defaultLocale, key)
+			choice.NameLocalizations = localizations(key)
+		}
+	}
+}
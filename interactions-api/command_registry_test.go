@@ -0,0 +1,112 @@
+package interactionsapi
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+type testCommand struct {
+	name string
+
+	componentArgs []string
+}
+
+func (cmd *testCommand) ApplicationCommand() *discordgo.ApplicationCommand {
+	return &discordgo.ApplicationCommand{
+		Name: cmd.name,
+	}
+}
+
+func (cmd *testCommand) Execute(req *InteractionRequest) (*discordgo.InteractionResponse, error) {
+	return messageResponse("execute", 0), nil
+}
+
+func (cmd *testCommand) HandleComponent(req *InteractionRequest, args []string) (*discordgo.InteractionResponse, error) {
+	cmd.componentArgs = args
+
+	return messageResponse("component", 0), nil
+}
+
+func TestCommandRegistry_Dispatch(t *testing.T) {
+	var calls []string
+
+	middleware := func(name string) InteractionMiddleware {
+		return func(next InteractionHandler) InteractionHandler {
+			return InteractionHandlerFunc(func(req *InteractionRequest) (*discordgo.InteractionResponse, error) {
+				calls = append(calls, name)
+
+				return next.HandleInteraction(req)
+			})
+		}
+	}
+
+	cmd := &testCommand{name: "test"}
+
+	r := NewCommandRegistry()
+	r.Use(middleware("first"), middleware("second"))
+
+	err := r.Register(cmd)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = r.Register(&testCommand{name: "test"})
+	if err == nil {
+		t.Errorf("r.Register() with a duplicated name returned no error")
+	}
+
+	resp, err := r.Dispatch(&InteractionRequest{
+		Interaction: &discordgo.Interaction{
+			Type: discordgo.InteractionApplicationCommand,
+			Data: discordgo.ApplicationCommandInteractionData{Name: "test"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := resp.Data.Content, "execute"; got != want {
+		t.Errorf("resp.Data.Content = %#v; want %#v", got, want)
+	}
+	if got, want := calls, []string{"first", "second"}; !slices.Equal(got, want) {
+		t.Errorf("calls = %#v; want %#v", got, want)
+	}
+
+	resp, err = r.Dispatch(&InteractionRequest{
+		Interaction: &discordgo.Interaction{
+			Type: discordgo.InteractionMessageComponent,
+			Data: discordgo.MessageComponentInteractionData{CustomID: componentCustomID("test", "page", "2")},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := resp.Data.Content, "component"; got != want {
+		t.Errorf("resp.Data.Content = %#v; want %#v", got, want)
+	}
+	if got, want := cmd.componentArgs, []string{"page", "2"}; !slices.Equal(got, want) {
+		t.Errorf("cmd.componentArgs = %#v; want %#v", got, want)
+	}
+
+	_, err = r.Dispatch(&InteractionRequest{
+		Interaction: &discordgo.Interaction{
+			Type: discordgo.InteractionApplicationCommandAutocomplete,
+			Data: discordgo.ApplicationCommandInteractionData{Name: "test"},
+		},
+	})
+	if !errors.Is(err, ErrUnsupportedInteractionType) {
+		t.Errorf("r.Dispatch() for autocompletion = %v; want %v", err, ErrUnsupportedInteractionType)
+	}
+
+	_, err = r.Dispatch(&InteractionRequest{
+		Interaction: &discordgo.Interaction{
+			Type: discordgo.InteractionApplicationCommand,
+			Data: discordgo.ApplicationCommandInteractionData{Name: "unknown"},
+		},
+	})
+	if !errors.Is(err, ErrUnknownCommand) {
+		t.Errorf("r.Dispatch() for unknown command = %v; want %v", err, ErrUnknownCommand)
+	}
+}
@@ -0,0 +1,169 @@
+package interactionsapi
+
+import (
+	"fmt"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+var (
+	permissionManageServer int64 = discordgo.PermissionManageServer
+	dmPermissionDisabled         = false
+)
+
+type settingsCommand struct {
+	s *Server
+}
+
+func (cmd *settingsCommand) ApplicationCommand() *discordgo.ApplicationCommand {
+	return &discordgo.ApplicationCommand{
+		Name:                     CmdSettings,
+		DefaultMemberPermissions: &permissionManageServer,
+		DMPermission:             &dmPermissionDisabled,
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type: discordgo.ApplicationCommandOptionBoolean,
+				Name: OptEphemeral,
+			},
+			{
+				Type: discordgo.ApplicationCommandOptionBoolean,
+				Name: OptPlainText,
+			},
+			formatOption(),
+		},
+	}
+}
+
+func (cmd *settingsCommand) Execute(req *InteractionRequest) (*discordgo.InteractionResponse, error) {
+	s := cmd.s
+	log := s.requestLogger(req.Context)
+	locale := req.Locale
+	interaction := req.Interaction
+
+	respond := func(content string) (*discordgo.InteractionResponse, error) {
+		return messageResponse(content, discordgo.MessageFlagsEphemeral), nil
+	}
+
+	guildID := interaction.GuildID
+	if guildID == "" {
+		return respond(localize(locale, msgGuildOnly))
+	}
+
+	settings, err := s.GuildSettings.GuildSettings(guildID)
+	if err != nil {
+		log.Errorf("could not load guild settings: %s", err.Error())
+
+		return respond(localize(locale, msgCouldNotLoadSettings))
+	}
+
+	data := interaction.ApplicationCommandData()
+
+	ephemeral, hasEphemeral := boolOption(data, OptEphemeral)
+	plainText, hasPlainText := boolOption(data, OptPlainText)
+	format, hasFormat := stringOption(data, OptFormat)
+
+	describe := func(settings GuildSettings) string {
+		return fmt.Sprintf(localize(locale, msgSettingsEphemeral), localizeBool(locale, settings.Ephemeral)) + "\n" +
+			fmt.Sprintf(localize(locale, msgSettingsPlainText), localizeBool(locale, settings.PlainText)) + "\n" +
+			fmt.Sprintf(localize(locale, msgSettingsFormat), localize(locale, commandOptionChoiceNameKey(CmdSettings, OptFormat, parseFormat(settings.Format))))
+	}
+
+	if !hasEphemeral && !hasPlainText && !hasFormat {
+		return respond(describe(settings))
+	}
+
+	if hasEphemeral {
+		settings.Ephemeral = ephemeral
+	}
+	if hasPlainText {
+		settings.PlainText = plainText
+	}
+	if hasFormat {
+		settings.Format = parseFormat(format)
+	}
+
+	err = s.GuildSettings.SetGuildSettings(guildID, settings)
+	if err != nil {
+		log.Errorf("could not save guild settings: %s", err.Error())
+
+		return respond(localize(locale, msgCouldNotSaveSettings))
+	}
+
+	return respond(localize(locale, msgSettingsUpdated) + "\n" + describe(settings))
+}
+
+// isEphemeral returns whether the response to an informational command should
+// be visible only to the user that used it.
+//
+// The `ephemeral` option of the command takes precedence over the guild
+// settings.
+func (s *Server) isEphemeral(req *InteractionRequest) bool {
+	interaction := req.Interaction
+
+	if ephemeral, ok := boolOption(interaction.ApplicationCommandData(), OptEphemeral); ok {
+		return ephemeral
+	}
+
+	return s.guildSettings(req).Ephemeral
+}
+
+// responseFormat returns the format of world lists in the response to a
+// command.
+//
+// The `format` option of the command takes precedence over the guild
+// settings. Lists are always embeds if `Features.DisableANSI` is set.
+func (s *Server) responseFormat(req *InteractionRequest) string {
+	if s.settings().Features.DisableANSI {
+		return FormatEmbed
+	}
+
+	if format, ok := stringOption(req.Interaction.ApplicationCommandData(), OptFormat); ok {
+		return parseFormat(format)
+	}
+
+	return parseFormat(s.guildSettings(req).Format)
+}
+
+// includeImage returns whether the response to a command includes the status
+// board, because its `image` option is set and images are not disabled.
+func (s *Server) includeImage(data discordgo.ApplicationCommandInteractionData) bool {
+	if s.settings().Features.DisableImages {
+		return false
+	}
+
+	image, _ := boolOption(data, OptImage)
+
+	return image
+}
+
+// parseFormat returns `format` if it's a known format of world lists, or
+// `FormatEmbed` otherwise.
+func parseFormat(format string) string {
+	switch format {
+	case FormatEmbed, FormatANSI:
+		return format
+	}
+
+	return FormatEmbed
+}
+
+// guildSettings returns the settings of the guild where the interaction
+// happened, or the default settings outside of guilds, or if they can't be
+// loaded.
+func (s *Server) guildSettings(req *InteractionRequest) GuildSettings {
+	log := s.requestLogger(req.Context)
+	interaction := req.Interaction
+
+	if interaction.GuildID == "" {
+		return GuildSettings{}
+	}
+
+	settings, err := s.GuildSettings.GuildSettings(interaction.GuildID)
+	if err != nil {
+		log.Errorf("could not load guild settings: %s", err.Error())
+
+		return GuildSettings{}
+	}
+
+	return settings
+}
@@ -0,0 +1,478 @@
+package interactionsapi
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	logger "github.com/c032/go-logger"
+)
+
+// CommandSyncDiff contains the names of the commands that must be created,
+// updated or deleted so the registered commands match the desired ones.
+type CommandSyncDiff struct {
+	Created []string
+	Updated []string
+	Deleted []string
+}
+
+// Empty returns whether the registered commands already match the desired
+// ones.
+func (diff CommandSyncDiff) Empty() bool {
+	return len(diff.Created) == 0 && len(diff.Updated) == 0 && len(diff.Deleted) == 0
+}
+
+// String returns the diff in a format similar to `diff(1)`, one command per
+// line.
+func (diff CommandSyncDiff) String() string {
+	var lines []string
+	for _, name := range diff.Created {
+		lines = append(lines, "+ "+name)
+	}
+	for _, name := range diff.Updated {
+		lines = append(lines, "~ "+name)
+	}
+	for _, name := range diff.Deleted {
+		lines = append(lines, "- "+name)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// diffCommands compares the `desired` commands with the `registered` ones.
+//
+// Fields assigned by Discord (e.g. IDs and versions) are ignored, as well as
+// differences between unset fields and fields set to their default value.
+// `guildID` is the scope of the commands, which is empty for global commands.
+func diffCommands(guildID string, desired []*discordgo.ApplicationCommand, registered []*discordgo.ApplicationCommand) CommandSyncDiff {
+	registeredByName := map[string]*discordgo.ApplicationCommand{}
+	for _, cmd := range registered {
+		registeredByName[cmd.Name] = cmd
+	}
+
+	desiredNames := map[string]struct{}{}
+
+	var diff CommandSyncDiff
+	for _, cmd := range desired {
+		desiredNames[cmd.Name] = struct{}{}
+
+		registeredCmd, ok := registeredByName[cmd.Name]
+		if !ok {
+			diff.Created = append(diff.Created, cmd.Name)
+
+			continue
+		}
+
+		if !reflect.DeepEqual(canonicalizeCommand(guildID, cmd), canonicalizeCommand(guildID, registeredCmd)) {
+			diff.Updated = append(diff.Updated, cmd.Name)
+		}
+	}
+
+	for _, cmd := range registered {
+		if _, ok := desiredNames[cmd.Name]; !ok {
+			diff.Deleted = append(diff.Deleted, cmd.Name)
+		}
+	}
+
+	slices.Sort(diff.Created)
+	slices.Sort(diff.Updated)
+	slices.Sort(diff.Deleted)
+
+	return diff
+}
+
+type canonicalCommand struct {
+	Type                     discordgo.ApplicationCommandType
+	Name                     string
+	NameLocalizations        map[discordgo.Locale]string
+	Description              string
+	DescriptionLocalizations map[discordgo.Locale]string
+	DefaultMemberPermissions string
+	DMPermission             bool
+	NSFW                     bool
+	Options                  []canonicalOption
+}
+
+type canonicalOption struct {
+	Type                     discordgo.ApplicationCommandOptionType
+	Name                     string
+	NameLocalizations        map[discordgo.Locale]string
+	Description              string
+	DescriptionLocalizations map[discordgo.Locale]string
+	ChannelTypes             []discordgo.ChannelType
+	Required                 bool
+	Autocomplete             bool
+	Choices                  []canonicalChoice
+	MinValue                 string
+	MaxValue                 float64
+	MinLength                string
+	MaxLength                int
+	Options                  []canonicalOption
+}
+
+type canonicalChoice struct {
+	Name              string
+	NameLocalizations map[discordgo.Locale]string
+	Value             string
+}
+
+func canonicalizeLocalizations(m map[discordgo.Locale]string) map[discordgo.Locale]string {
+	if len(m) == 0 {
+		return nil
+	}
+
+	return m
+}
+
+// canonicalizeCommand returns the fields of `cmd` that are compared, with
+// unset fields replaced by their default value.
+//
+// Discord doesn't return `dm_permission` for guild commands, so it's only
+// compared for global commands, i.e. when `guildID` is empty.
+func canonicalizeCommand(guildID string, cmd *discordgo.ApplicationCommand) canonicalCommand {
+	c := canonicalCommand{
+		Type:        cmd.Type,
+		Name:        cmd.Name,
+		Description: cmd.Description,
+	}
+
+	if c.Type == 0 {
+		c.Type = discordgo.ChatApplicationCommand
+	}
+	if cmd.NameLocalizations != nil {
+		c.NameLocalizations = canonicalizeLocalizations(*cmd.NameLocalizations)
+	}
+	if cmd.DescriptionLocalizations != nil {
+		c.DescriptionLocalizations = canonicalizeLocalizations(*cmd.DescriptionLocalizations)
+	}
+	if cmd.DefaultMemberPermissions != nil {
+		c.DefaultMemberPermissions = fmt.Sprint(*cmd.DefaultMemberPermissions)
+	}
+	if guildID == "" {
+		c.DMPermission = true
+		if cmd.DMPermission != nil {
+			c.DMPermission = *cmd.DMPermission
+		}
+	}
+	if cmd.NSFW != nil {
+		c.NSFW = *cmd.NSFW
+	}
+
+	c.Options = canonicalizeOptions(cmd.Options)
+
+	return c
+}
+
+func canonicalizeOptions(options []*discordgo.ApplicationCommandOption) []canonicalOption {
+	if len(options) == 0 {
+		return nil
+	}
+
+	result := make([]canonicalOption, 0, len(options))
+	for _, option := range options {
+		o := canonicalOption{
+			Type:                     option.Type,
+			Name:                     option.Name,
+			NameLocalizations:        canonicalizeLocalizations(option.NameLocalizations),
+			Description:              option.Description,
+			DescriptionLocalizations: canonicalizeLocalizations(option.DescriptionLocalizations),
+			Required:                 option.Required,
+			Autocomplete:             option.Autocomplete,
+			MaxValue:                 option.MaxValue,
+			MaxLength:                option.MaxLength,
+			Options:                  canonicalizeOptions(option.Options),
+		}
+
+		if len(option.ChannelTypes) > 0 {
+			o.ChannelTypes = option.ChannelTypes
+		}
+		if option.MinValue != nil {
+			o.MinValue = fmt.Sprint(*option.MinValue)
+		}
+		if option.MinLength != nil {
+			o.MinLength = fmt.Sprint(*option.MinLength)
+		}
+
+		for _, choice := range option.Choices {
+			o.Choices = append(o.Choices, canonicalChoice{
+				Name:              choice.Name,
+				NameLocalizations: canonicalizeLocalizations(choice.NameLocalizations),
+				Value:             fmt.Sprint(choice.Value),
+			})
+		}
+
+		result = append(result, o)
+	}
+
+	return result
+}
+
+// CommandSyncResult is the result of synchronizing the commands of a single
+// application in a single scope.
+type CommandSyncResult struct {
+	Application string
+
+	// GuildID is empty for global commands.
+	GuildID string
+
+	Diff CommandSyncDiff
+}
+
+// commandScopes returns the guild IDs where the commands of `app` are
+// registered.
+//
+// An empty guild ID refers to global commands.
+func (app *Application) commandScopes() []string {
+	if len(app.DevGuildIDs) > 0 {
+		return app.DevGuildIDs
+	}
+
+	return []string{""}
+}
+
+// SyncCommands makes the commands registered in Discord match the commands in
+// the registry of each application.
+//
+// Commands are registered globally, unless `DevGuildIDs` is set, in which
+// case they are registered only in those guilds and global commands are left
+// untouched.
+//
+// Registered commands are only replaced when they differ from the desired
+// ones, and the replacement is done with a single bulk overwrite, so running
+// it concurrently from multiple replicas is safe.
+//
+// If `dryRun` is `true`, the differences are computed and logged but nothing
+// is changed.
+func (s *Server) SyncCommands(dryRun bool) ([]CommandSyncResult, error) {
+	log := s.logger()
+	log.Print("Synchronizing commands.")
+
+	var results []CommandSyncResult
+	for _, app := range s.Applications {
+		for _, guildID := range app.commandScopes() {
+			diff, err := s.syncCommandsInScope(app, guildID, dryRun)
+			if err != nil {
+				s.setCommandSyncStatus(dryRun, err)
+
+				return results, err
+			}
+
+			results = append(results, CommandSyncResult{
+				Application: app.Name,
+				GuildID:     guildID,
+				Diff:        diff,
+			})
+		}
+	}
+
+	s.setCommandSyncStatus(dryRun, nil)
+
+	return results, nil
+}
+
+// Delays between attempts of `RunCommandSync`.
+const (
+	minCommandSyncRetryDelay = time.Second
+	maxCommandSyncRetryDelay = time.Minute
+)
+
+// RunCommandSync synchronizes commands with `SyncCommands`, retrying with
+// exponential backoff until it succeeds or `ctx` is done. Until it succeeds,
+// `/readyz` reports that commands are not synchronized. With
+// `CommandSyncDryRun`, `/readyz` reports a dry run instead of synchronized
+// commands once the differences are computed.
+//
+// It's meant to run in the background once the server is listening, and it
+// does nothing if `SkipCommandSync` is set.
+func (s *Server) RunCommandSync(ctx context.Context) error {
+	if s.SkipCommandSync {
+		return nil
+	}
+
+	log := s.logger()
+
+	delay := minCommandSyncRetryDelay
+	for {
+		_, err := s.SyncCommands(s.CommandSyncDryRun)
+		if err == nil {
+			return nil
+		}
+
+		log.Errorf("could not synchronize Discord commands, retrying in %s: %s", delay, err.Error())
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("could not synchronize Discord commands: %w", err)
+		case <-time.After(delay):
+		}
+
+		delay = min(2*delay, maxCommandSyncRetryDelay)
+	}
+}
+
+// RegisteredCommands are the commands registered in Discord for a single
+// application in a single scope.
+type RegisteredCommands struct {
+	Application string
+
+	// GuildID is empty for global commands.
+	GuildID string
+
+	Commands []*discordgo.ApplicationCommand
+}
+
+// ListCommands returns the commands registered in Discord for each
+// application, in every scope where the server would register them.
+func (s *Server) ListCommands() ([]RegisteredCommands, error) {
+	var results []RegisteredCommands
+	for _, app := range s.Applications {
+		for _, guildID := range app.commandScopes() {
+			registered, err := app.session.ApplicationCommands(app.ID, guildID)
+			if err != nil {
+				return results, fmt.Errorf("could not list registered commands (application %#v, guild %#v): %w", app.Name, guildID, err)
+			}
+
+			results = append(results, RegisteredCommands{
+				Application: app.Name,
+				GuildID:     guildID,
+				Commands:    registered,
+			})
+		}
+	}
+
+	return results, nil
+}
+
+// DeleteCommands deletes every command registered in Discord for each
+// application, in every scope where the server would register them.
+//
+// If `dryRun` is `true`, the commands that would be deleted are returned but
+// nothing is changed.
+func (s *Server) DeleteCommands(dryRun bool) ([]CommandSyncResult, error) {
+	log := s.logger()
+
+	var results []CommandSyncResult
+	for _, app := range s.Applications {
+		for _, guildID := range app.commandScopes() {
+			registered, err := app.session.ApplicationCommands(app.ID, guildID)
+			if err != nil {
+				return results, fmt.Errorf("could not list registered commands (application %#v, guild %#v): %w", app.Name, guildID, err)
+			}
+
+			diff := diffCommands(guildID, nil, registered)
+
+			results = append(results, CommandSyncResult{
+				Application: app.Name,
+				GuildID:     guildID,
+				Diff:        diff,
+			})
+
+			if dryRun || diff.Empty() {
+				continue
+			}
+
+			_, err = app.session.ApplicationCommandBulkOverwrite(app.ID, guildID, []*discordgo.ApplicationCommand{})
+			if err != nil {
+				return results, fmt.Errorf("could not delete registered commands (application %#v, guild %#v): %w", app.Name, guildID, err)
+			}
+
+			log.WithFields(logger.Fields{
+				"application": app.Name,
+				"guild_id":    guildID,
+				"deleted":     diff.Deleted,
+			}).Print("Registered commands deleted.")
+		}
+	}
+
+	return results, nil
+}
+
+// commandSyncStatus is the outcome of the last call to `SyncCommands`.
+type commandSyncStatus struct {
+	mu     sync.Mutex
+	done   bool
+	dryRun bool
+	err    error
+}
+
+func (s *Server) setCommandSyncStatus(dryRun bool, err error) {
+	s.commandSync.mu.Lock()
+	defer s.commandSync.mu.Unlock()
+
+	s.commandSync.done = true
+	s.commandSync.dryRun = dryRun
+	s.commandSync.err = err
+}
+
+// commandSyncHealthCheck reports whether commands were synchronized
+// successfully. A successful dry run doesn't make the server unavailable,
+// but it is reported separately, since nothing was changed in Discord.
+func (s *Server) commandSyncHealthCheck() healthCheck {
+	s.commandSync.mu.Lock()
+	defer s.commandSync.mu.Unlock()
+
+	if !s.commandSync.done {
+		return newHealthCheck(fmt.Errorf("commands were not synchronized yet"))
+	}
+	if s.commandSync.err != nil {
+		return newHealthCheck(s.commandSync.err)
+	}
+	if s.commandSync.dryRun {
+		return healthCheck{
+			Status: healthStatusDryRun,
+		}
+	}
+
+	return newHealthCheck(nil)
+}
+
+func (s *Server) syncCommandsInScope(app *Application, guildID string, dryRun bool) (CommandSyncDiff, error) {
+	log := s.logger().WithFields(logger.Fields{
+		"application": app.Name,
+		"guild_id":    guildID,
+	})
+
+	ds := app.session
+
+	registered, err := ds.ApplicationCommands(app.ID, guildID)
+	if err != nil {
+		return CommandSyncDiff{}, fmt.Errorf("could not list registered commands (application %#v, guild %#v): %w", app.Name, guildID, err)
+	}
+
+	desired := app.commands.ApplicationCommands()
+
+	diff := diffCommands(guildID, desired, registered)
+	if diff.Empty() {
+		log.Print("Registered commands are up to date.")
+
+		return diff, nil
+	}
+
+	log.WithFields(logger.Fields{
+		"application": app.Name,
+		"guild_id":    guildID,
+		"created":     diff.Created,
+		"updated":     diff.Updated,
+		"deleted":     diff.Deleted,
+	}).Print("Registered commands differ from desired commands.")
+
+	if dryRun {
+		log.Print("Dry run. Not overwriting registered commands.")
+
+		return diff, nil
+	}
+
+	_, err = ds.ApplicationCommandBulkOverwrite(app.ID, guildID, desired)
+	if err != nil {
+		return diff, fmt.Errorf("could not overwrite registered commands (application %#v, guild %#v): %w", app.Name, guildID, err)
+	}
+
+	log.Print("Registered commands overwritten.")
+
+	return diff, nil
+}
@@ -0,0 +1,216 @@
+package interactionsapi
+
+import (
+	"encoding/json"
+	"slices"
+	"strconv"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/c032/ffxiv-world-status-discord/discordfake"
+)
+
+// asRegistered returns a copy of `commands` as they would be returned by
+// Discord after registering them.
+func asRegistered(t *testing.T, commands []*discordgo.ApplicationCommand) []*discordgo.ApplicationCommand {
+	t.Helper()
+
+	rawCommands, err := json.Marshal(commands)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var registered []*discordgo.ApplicationCommand
+	err = json.Unmarshal(rawCommands, &registered)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i, cmd := range registered {
+		cmd.ID = strconv.Itoa(100000 + i)
+		cmd.ApplicationID = "1"
+		cmd.Version = "1"
+		cmd.Type = discordgo.ChatApplicationCommand
+	}
+
+	return registered
+}
+
+func desiredCommands(t *testing.T) []*discordgo.ApplicationCommand {
+	t.Helper()
+
+	s := &Server{}
+
+	r, err := s.newCommandRegistry(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return r.ApplicationCommands()
+}
+
+func TestDiffCommands_UpToDate(t *testing.T) {
+	desired := desiredCommands(t)
+	registered := asRegistered(t, desired)
+
+	diff := diffCommands("", desired, registered)
+	if !diff.Empty() {
+		t.Fatalf("diffCommands(desired, registered) = %#v; want empty diff", diff)
+	}
+}
+
+func TestDiffCommands_GuildScope(t *testing.T) {
+	desired := desiredCommands(t)
+	registered := asRegistered(t, desired)
+
+	// Discord doesn't return `dm_permission` for guild commands.
+	for _, cmd := range registered {
+		cmd.DMPermission = nil
+	}
+
+	diff := diffCommands("1", desired, registered)
+	if !diff.Empty() {
+		t.Fatalf("diffCommands(desired, registered) = %#v; want empty diff", diff)
+	}
+
+	// The same commands registered globally allow being used in DMs.
+	diff = diffCommands("", desired, registered)
+	if got, want := diff.Updated, []string{CmdSettings}; !slices.Equal(got, want) {
+		t.Errorf("diff.Updated = %#v; want %#v", got, want)
+	}
+}
+
+func TestDiffCommands_Changes(t *testing.T) {
+	desired := desiredCommands(t)
+	registered := asRegistered(t, desired)
+
+	registered = slices.DeleteFunc(registered, func(cmd *discordgo.ApplicationCommand) bool {
+		return cmd.Name == CmdPing
+	})
+	for _, cmd := range registered {
+		if cmd.Name == CmdCharacters {
+			cmd.Description = "Outdated description."
+		}
+	}
+	registered = append(registered, &discordgo.ApplicationCommand{
+		ID:          "200000",
+		Name:        "removed",
+		Description: "Removed command.",
+	})
+
+	diff := diffCommands("", desired, registered)
+
+	if got, want := diff.Created, []string{CmdPing}; !slices.Equal(got, want) {
+		t.Errorf("diff.Created = %#v; want %#v", got, want)
+	}
+	if got, want := diff.Updated, []string{CmdCharacters}; !slices.Equal(got, want) {
+		t.Errorf("diff.Updated = %#v; want %#v", got, want)
+	}
+	if got, want := diff.Deleted, []string{"removed"}; !slices.Equal(got, want) {
+		t.Errorf("diff.Deleted = %#v; want %#v", got, want)
+	}
+
+	if got, want := diff.String(), "+ ping\n~ characters\n- removed"; got != want {
+		t.Errorf("diff.String() = %#v; want %#v", got, want)
+	}
+}
+
+// newCommandSyncServer returns a server that manages the commands of a single
+// application in a fake of Discord.
+func newCommandSyncServer(t *testing.T, devGuildIDs []string) (*Server, *discordfake.Server) {
+	t.Helper()
+
+	fake := discordfake.NewServer()
+	t.Cleanup(fake.Close)
+
+	s := &Server{
+		Applications: []*Application{
+			{
+				Name:        "default",
+				ID:          "1",
+				Token:       "token",
+				DevGuildIDs: devGuildIDs,
+			},
+		},
+		DiscordAPIURL: fake.URL,
+	}
+
+	err := s.InitializeCommands()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		s.Cleanup()
+	})
+
+	return s, fake
+}
+
+// desiredCommandNames returns the sorted names of the commands that should be
+// registered.
+func desiredCommandNames(t *testing.T) []string {
+	t.Helper()
+
+	var names []string
+	for _, cmd := range desiredCommands(t) {
+		names = append(names, cmd.Name)
+	}
+	slices.Sort(names)
+
+	return names
+}
+
+func TestSyncCommands_DevGuilds(t *testing.T) {
+	s, fake := newCommandSyncServer(t, []string{"10", "20"})
+
+	results, err := s.SyncCommands(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var guildIDs []string
+	for _, result := range results {
+		guildIDs = append(guildIDs, result.GuildID)
+	}
+	if want := []string{"10", "20"}; !slices.Equal(guildIDs, want) {
+		t.Fatalf("synchronized guilds = %#v; want %#v", guildIDs, want)
+	}
+
+	fake.AssertCommands(t, "1", "10", desiredCommandNames(t)...)
+	fake.AssertCommands(t, "1", "20", desiredCommandNames(t)...)
+	fake.AssertCommands(t, "1", "")
+
+	// Synchronizing again doesn't change anything.
+	results, err = s.SyncCommands(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, result := range results {
+		if !result.Diff.Empty() {
+			t.Errorf("diff in guild %#v = %#v; want empty diff", result.GuildID, result.Diff)
+		}
+	}
+}
+
+func TestSyncCommands_Global(t *testing.T) {
+	s, fake := newCommandSyncServer(t, nil)
+
+	results, err := s.SyncCommands(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 || results[0].GuildID != "" {
+		t.Fatalf("results = %#v; want a single global result", results)
+	}
+
+	fake.AssertCommands(t, "1", "", desiredCommandNames(t)...)
+
+	results, err = s.SyncCommands(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !results[0].Diff.Empty() {
+		t.Errorf("diff = %#v; want empty diff", results[0].Diff)
+	}
+}
@@ -1,25 +1,181 @@
 package interactionsapi
 
 import (
+	"fmt"
+
 	"github.com/bwmarrin/discordgo"
 )
 
 const (
 	CmdPing       = "ping"
 	CmdCharacters = "characters"
+	CmdSettings   = "settings"
+	CmdDataCenter = "datacenter"
+)
+
+const (
+	OptEphemeral = "ephemeral"
+	OptPlainText = "plain_text"
+	OptImage     = "image"
+	OptName      = "name"
+	OptFormat    = "format"
 )
 
-var Commands = map[string]*discordgo.ApplicationCommand{
-	CmdPing: &discordgo.ApplicationCommand{
-		Description: "Make the bot respond with a pong.",
-	},
-	CmdCharacters: &discordgo.ApplicationCommand{
-		Description: "Print character creation availability status of all worlds.",
-	},
+// Formats of world lists, which are the values of the `format` option.
+const (
+	// FormatEmbed lists worlds in embeds.
+	FormatEmbed = "embed"
+
+	// FormatANSI lists worlds in a code block coloured with ANSI escape
+	// codes, for users that disable embeds.
+	FormatANSI = "ansi"
+)
+
+// newCommandRegistry returns a registry with the commands available in `app`.
+//
+// If `app` is `nil`, every command supported by the server is registered.
+func (s *Server) newCommandRegistry(app *Application) (*CommandRegistry, error) {
+	r := NewCommandRegistry()
+
+	r.Use(
+		s.loggingMiddleware,
+		s.metricsMiddleware,
+		s.permissionsMiddleware(r),
+		s.cooldownMiddleware,
+	)
+
+	handlers := []CommandHandler{
+		&pingCommand{},
+		&charactersCommand{s: s},
+		&settingsCommand{s: s},
+		&dataCenterCommand{s: s},
+	}
+
+	for _, h := range handlers {
+		if app != nil && !app.hasCommand(h.ApplicationCommand().Name) {
+			continue
+		}
+
+		err := r.Register(h)
+		if err != nil {
+			return nil, fmt.Errorf("could not register command: %w", err)
+		}
+	}
+
+	return r, nil
+}
+
+// boolOption returns the value of the boolean option named `name`, and
+// whether the option was provided.
+func boolOption(data discordgo.ApplicationCommandInteractionData, name string) (value bool, ok bool) {
+	for _, option := range data.Options {
+		if option.Name != name || option.Type != discordgo.ApplicationCommandOptionBoolean {
+			continue
+		}
+
+		return option.BoolValue(), true
+	}
+
+	return false, false
+}
+
+// formatOption returns the definition of the option to choose the format of
+// world lists.
+func formatOption() *discordgo.ApplicationCommandOption {
+	return &discordgo.ApplicationCommandOption{
+		Type: discordgo.ApplicationCommandOptionString,
+		Name: OptFormat,
+		Choices: []*discordgo.ApplicationCommandOptionChoice{
+			{Name: FormatEmbed, Value: FormatEmbed},
+			{Name: FormatANSI, Value: FormatANSI},
+		},
+	}
 }
 
-func init() {
-	for key, cmd := range Commands {
-		cmd.Name = key
+// stringOption returns the value of the string option named `name`, and
+// whether the option was provided.
+func stringOption(data discordgo.ApplicationCommandInteractionData, name string) (value string, ok bool) {
+	for _, option := range data.Options {
+		if option.Name != name || option.Type != discordgo.ApplicationCommandOptionString {
+			continue
+		}
+
+		return option.StringValue(), true
+	}
+
+	return "", false
+}
+
+// messageResponse returns a response with a message that contains only
+// `content`.
+func messageResponse(content string, flags discordgo.MessageFlags) *discordgo.InteractionResponse {
+	return &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: content,
+			Flags:   flags,
+		},
+	}
+}
+
+// errorMessageResponse returns a response that shows `content` only to the
+// user of an interaction of type `interactionType`.
+//
+// Autocompletions can only be answered with choices, so they receive none
+// instead.
+func errorMessageResponse(interactionType discordgo.InteractionType, content string) *discordgo.InteractionResponse {
+	if interactionType == discordgo.InteractionApplicationCommandAutocomplete {
+		return &discordgo.InteractionResponse{
+			Type: discordgo.InteractionApplicationCommandAutocompleteResult,
+			Data: &discordgo.InteractionResponseData{
+				Choices: []*discordgo.ApplicationCommandOptionChoice{},
+			},
+		}
+	}
+
+	return messageResponse(content, discordgo.MessageFlagsEphemeral)
+}
+
+// pageNumber returns the localized number of the page at `pageIndex`, or an
+// empty string if there's a single page. It fits in `pageNumberReserve`,
+// with a separator.
+func pageNumber(locale discordgo.Locale, pageIndex int, pageCount int) string {
+	if pageCount <= 1 {
+		return ""
+	}
+
+	return truncate(fmt.Sprintf(localize(locale, msgPage), pageIndex+1, pageCount), pageNumberReserve-1)
+}
+
+// pageButtons returns the buttons to show the previous and next pages, or
+// `nil` if there's a single page. `customID` returns the custom ID of the
+// button that shows the page at `pageIndex`.
+func pageButtons(locale discordgo.Locale, pageIndex int, pageCount int, customID func(pageIndex int) string) []discordgo.MessageComponent {
+	if pageCount <= 1 {
+		return nil
+	}
+
+	// Both buttons always point to different pages, because custom IDs
+	// must be unique within a message.
+	previous := max(pageIndex-1, 0)
+	next := min(pageIndex+1, pageCount-1)
+
+	return []discordgo.MessageComponent{
+		discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{
+				discordgo.Button{
+					Label:    localize(locale, msgPreviousPage),
+					Style:    discordgo.SecondaryButton,
+					CustomID: customID(previous),
+					Disabled: pageIndex == 0,
+				},
+				discordgo.Button{
+					Label:    localize(locale, msgNextPage),
+					Style:    discordgo.SecondaryButton,
+					CustomID: customID(next),
+					Disabled: pageIndex == pageCount-1,
+				},
+			},
+		},
 	}
 }
@@ -0,0 +1,117 @@
+package interactionsapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	// maxEditOriginalAttempts is how many times the original response is
+	// edited before giving up, if Discord doesn't know about it yet.
+	maxEditOriginalAttempts = 3
+
+	// editOriginalRetryDelay is the time between attempts to edit the
+	// original response.
+	editOriginalRetryDelay = 250 * time.Millisecond
+)
+
+// deferFiles returns a deferred response to use instead of `resp`, which has
+// files attached, and edits the original response with `resp` in the
+// background.
+//
+// Files can only be uploaded as multipart bodies to the Discord REST API, not
+// in the response to the HTTP request of the interaction.
+func (s *Server) deferFiles(req *InteractionRequest, resp *discordgo.InteractionResponse) *discordgo.InteractionResponse {
+	deferredType := discordgo.InteractionResponseDeferredChannelMessageWithSource
+	if resp.Type == discordgo.InteractionResponseUpdateMessage {
+		deferredType = discordgo.InteractionResponseDeferredMessageUpdate
+	}
+
+	s.goDeferred(req, func(ctx context.Context) {
+		err := s.editOriginalResponse(ctx, req, resp.Data)
+		if err != nil {
+			s.requestLogger(ctx).Errorf("could not send deferred response: %s", err.Error())
+		}
+	})
+
+	return &discordgo.InteractionResponse{
+		Type: deferredType,
+		Data: &discordgo.InteractionResponseData{
+			Flags: resp.Data.Flags & discordgo.MessageFlagsEphemeral,
+		},
+	}
+}
+
+// editOriginalResponse replaces the original response of the interaction in
+// `req` with `data`, including its files.
+func (s *Server) editOriginalResponse(ctx context.Context, req *InteractionRequest, data *discordgo.InteractionResponseData) error {
+	ctx, span := s.Tracer.Start(ctx, "edit original response")
+	defer span.End()
+
+	edit := &discordgo.WebhookEdit{
+		Content: &data.Content,
+		Files:   data.Files,
+	}
+	if data.Embeds != nil {
+		edit.Embeds = &data.Embeds
+	}
+	if data.Components != nil {
+		edit.Components = &data.Components
+	}
+
+	var err error
+	for attempt := 1; attempt <= maxEditOriginalAttempts; attempt++ {
+		err = rewindFiles(data.Files)
+		if err != nil {
+			break
+		}
+
+		_, err = req.Application.session.InteractionResponseEdit(req.Interaction, edit, discordgo.WithContext(ctx))
+		if err == nil || !isUnknownOriginalResponse(err) || attempt == maxEditOriginalAttempts {
+			break
+		}
+
+		// The deferred response may not have been processed by Discord
+		// yet.
+		time.Sleep(editOriginalRetryDelay)
+	}
+	if err != nil {
+		span.SetError(err)
+
+		return fmt.Errorf("could not edit original response: %w", err)
+	}
+
+	return nil
+}
+
+// rewindFiles seeks the readers of `files` to their start, so they can be
+// sent again.
+func rewindFiles(files []*discordgo.File) error {
+	for _, f := range files {
+		seeker, ok := f.Reader.(io.Seeker)
+		if !ok {
+			continue
+		}
+
+		_, err := seeker.Seek(0, io.SeekStart)
+		if err != nil {
+			return fmt.Errorf("could not rewind file %#v: %w", f.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// isUnknownOriginalResponse returns whether `err` means that Discord doesn't
+// know about the original response.
+func isUnknownOriginalResponse(err error) bool {
+	var restErr *discordgo.RESTError
+
+	return errors.As(err, &restErr) && restErr.Response != nil && restErr.Response.StatusCode == http.StatusNotFound
+}
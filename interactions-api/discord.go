@@ -1,52 +1,273 @@
 package interactionsapi
 
 import (
-	"slices"
+	"errors"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/bwmarrin/discordgo"
 
 	"github.com/c032/ffxiv-world-status-discord/ffxivapi"
 )
 
-type Worlds []ffxivapi.World
+// Limits of Discord for the embeds of a single message.
+//
+// https://discord.com/developers/docs/resources/message#embed-object-embed-limits
+const (
+	messageContentLimit   = 2000
+	embedTitleLimit       = 256
+	embedDescriptionLimit = 4096
+	embedFieldNameLimit   = 256
+	embedFieldValueLimit  = 1024
+	embedFooterLimit      = 2048
+	embedFieldsLimit      = 25
+	messageEmbedsLimit    = 10
 
-func (worlds Worlds) Embed(title string, thumbnailURL string) (*discordgo.MessageEmbed, error) {
-	groups := map[string][]string{}
+	// messageEmbedsCharactersLimit is the maximum sum of the characters in
+	// the titles, field names, field values and footers of every embed of a
+	// message.
+	messageEmbedsCharactersLimit = 6000
+)
 
-	for _, w := range worlds {
-		groups[w.Group] = append(groups[w.Group], w.Name)
-	}
+// pageNumberReserve is the number of characters of each page that are kept
+// for the page number in the footer.
+const pageNumberReserve = 64
+
+// worldGroup contains the lines that represent the worlds of a group (i.e. a
+// data center).
+type worldGroup struct {
+	Name  string
+	Lines []string
+}
+
+// embedSection is a list of world groups under a title, e.g. the worlds that
+// are under maintenance.
+type embedSection struct {
+	Title  string
+	Color  int
+	Groups []worldGroup
+}
+
+// embedPage contains the embeds of a single message.
+type embedPage []*discordgo.MessageEmbed
 
-	var groupNames []string
-	for groupName, _ := range groups {
-		groupNames = append(groupNames, groupName)
+// countCharacters returns the length of `s` as counted by Discord.
+func countCharacters(s string) int {
+	return utf8.RuneCountInString(s)
+}
+
+// truncate shortens `s` to at most `limit` characters, ending it with an
+// ellipsis if anything was removed.
+func truncate(s string, limit int) string {
+	if countCharacters(s) <= limit {
+		return s
 	}
-	slices.Sort(groupNames)
 
-	var fields []*discordgo.MessageEmbedField
-	for _, groupName := range groupNames {
-		worldNames := groups[groupName]
+	runes := []rune(s)
+
+	return string(runes[:limit-1]) + "…"
+}
+
+// groupFields returns the fields with the lines of `group`,
+// split across as many fields as needed so none exceeds
+// `embedFieldValueLimit`.
+func groupFields(group worldGroup) []*discordgo.MessageEmbedField {
+	name := truncate(group.Name, embedFieldNameLimit)
+
+	var (
+		fields []*discordgo.MessageEmbedField
+		lines  []string
+		size   int
+	)
 
-		slices.Sort(worldNames)
+	flush := func() {
+		if len(lines) == 0 {
+			return
+		}
 
 		fields = append(fields, &discordgo.MessageEmbedField{
-			Name:   groupName,
-			Value:  strings.Join(worldNames, "\n"),
+			Name:   name,
+			Value:  strings.Join(lines, "\n"),
 			Inline: true,
 		})
+
+		lines = nil
+		size = 0
+	}
+
+	for _, line := range group.Lines {
+		line = truncate(line, embedFieldValueLimit)
+
+		lineSize := countCharacters(line)
+		if len(lines) > 0 {
+			// Newline that separates it from the previous line.
+			lineSize++
+		}
+
+		if size+lineSize > embedFieldValueLimit {
+			flush()
+
+			lineSize = countCharacters(line)
+		}
+
+		lines = append(lines, line)
+		size += lineSize
+	}
+	flush()
+
+	return fields
+}
+
+// renderEmbedPages renders `sections` as embeds, split across as many fields,
+// embeds and pages as needed to stay within the limits of Discord.
+//
+// Every section starts a new embed, which is continued in more embeds, with
+// the same title, if it doesn't fit in one. `footerReserve` characters are
+// left available in every page for the footer.
+func renderEmbedPages(sections []embedSection, thumbnailURL string, footerReserve int) []embedPage {
+	budget := messageEmbedsCharactersLimit - footerReserve
+
+	var (
+		pages     []embedPage
+		page      embedPage
+		pageSize  int
+		lastEmbed *discordgo.MessageEmbed
+	)
+
+	flushPage := func() {
+		if len(page) > 0 {
+			pages = append(pages, page)
+		}
+
+		page = nil
+		pageSize = 0
+		lastEmbed = nil
+	}
+
+	for _, section := range sections {
+		title := truncate(section.Title, embedTitleLimit)
+		titleSize := countCharacters(title)
+
+		lastEmbed = nil
+
+		for _, group := range section.Groups {
+			for _, field := range groupFields(group) {
+				fieldSize := countCharacters(field.Name) + countCharacters(field.Value)
+
+				if lastEmbed != nil && (len(lastEmbed.Fields) >= embedFieldsLimit || pageSize+fieldSize > budget) {
+					lastEmbed = nil
+				}
+
+				if lastEmbed == nil {
+					if len(page) >= messageEmbedsLimit || pageSize+titleSize+fieldSize > budget {
+						flushPage()
+					}
+
+					lastEmbed = &discordgo.MessageEmbed{
+						Title: title,
+						Color: section.Color,
+					}
+					if thumbnailURL != "" {
+						lastEmbed.Thumbnail = &discordgo.MessageEmbedThumbnail{
+							URL: thumbnailURL,
+						}
+					}
+
+					page = append(page, lastEmbed)
+					pageSize += titleSize
+				}
+
+				lastEmbed.Fields = append(lastEmbed.Fields, field)
+				pageSize += fieldSize
+			}
+		}
+	}
+	flushPage()
+
+	return pages
+}
+
+// embedGroups renders `groups` with the templates of the guild, as the
+// fields of an embed section. It also returns the rendered worlds.
+func (rt *ResponseTemplates) embedGroups(guildID string, groups []TemplateGroup) ([]worldGroup, []ffxivapi.World, error) {
+	var (
+		result []worldGroup
+		worlds []ffxivapi.World
+	)
+
+	for _, group := range groups {
+		name, err := rt.execute(guildID, TemplateNameGroupName, group)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		wg := worldGroup{
+			Name: name,
+		}
+		for _, w := range group.Worlds {
+			line, err := rt.execute(guildID, TemplateNameWorld, w)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			wg.Lines = append(wg.Lines, line)
+			worlds = append(worlds, w.world)
+		}
+
+		result = append(result, wg)
 	}
 
-	embed := &discordgo.MessageEmbed{
+	return result, worlds, nil
+}
+
+// ErrTooManyWorlds is returned by `Worlds.Embed` when the worlds don't fit in a
+// single embed.
+var ErrTooManyWorlds = errors.New("worlds don't fit in a single embed")
+
+type Worlds []ffxivapi.World
+
+// Embed returns an embed with `worlds`, in a field for each data center, like
+// the ones of `/characters` without overrides: data centers are in the order
+// of `ffxivapi.GroupByDataCenter`, and worlds are rendered with the default
+// templates and status icons.
+func (worlds Worlds) Embed(title string, thumbnailURL string) (*discordgo.MessageEmbed, error) {
+	rt, err := NewResponseTemplates(TemplateOverrides{})
+	if err != nil {
+		return nil, err
+	}
+
+	style := worldListStyle{
+		Locale: defaultLocale,
+		Icons:  DefaultStatusIcons,
+	}
+
+	groups, rendered, err := rt.embedGroups("", newTemplateGroups(style, worlds))
+	if err != nil {
+		return nil, err
+	}
+
+	section := embedSection{
 		Title:  title,
-		Fields: fields,
+		Color:  severityColor(rendered),
+		Groups: groups,
 	}
 
-	if thumbnailURL != "" {
-		embed.Thumbnail = &discordgo.MessageEmbedThumbnail{
-			URL: thumbnailURL,
+	pages := renderEmbedPages([]embedSection{section}, thumbnailURL, 0)
+	switch {
+	case len(pages) == 0:
+		embed := &discordgo.MessageEmbed{
+			Title: truncate(title, embedTitleLimit),
 		}
+		if thumbnailURL != "" {
+			embed.Thumbnail = &discordgo.MessageEmbedThumbnail{
+				URL: thumbnailURL,
+			}
+		}
+
+		return embed, nil
+	case len(pages) > 1 || len(pages[0]) > 1:
+		return nil, ErrTooManyWorlds
 	}
 
-	return embed, nil
+	return pages[0][0], nil
 }
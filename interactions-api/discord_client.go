@@ -0,0 +1,59 @@
+package interactionsapi
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// discordAPITransport sends the requests for the Discord REST API to
+// `baseURL` instead.
+type discordAPITransport struct {
+	baseURL *url.URL
+	next    http.RoundTripper
+}
+
+func (t *discordAPITransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	rawURL := req.URL.String()
+	if !strings.HasPrefix(rawURL, discordgo.EndpointAPI) {
+		return t.next.RoundTrip(req)
+	}
+
+	u, err := t.baseURL.Parse(strings.TrimPrefix(rawURL, discordgo.EndpointAPI))
+	if err != nil {
+		return nil, fmt.Errorf("could not rewrite Discord API URL: %w", err)
+	}
+
+	req = req.Clone(req.Context())
+	req.URL = u
+	req.Host = u.Host
+
+	return t.next.RoundTrip(req)
+}
+
+// NewDiscordHTTPClient returns an HTTP client for `discordgo.Session` that
+// sends the requests for the Discord REST API to `baseURL` instead of
+// `discordgo.EndpointAPI`, e.g. to use a local fake.
+func NewDiscordHTTPClient(baseURL string) (*http.Client, error) {
+	if !strings.HasSuffix(baseURL, "/") {
+		baseURL += "/"
+	}
+
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse Discord API URL: %w", err)
+	}
+
+	return &http.Client{
+		// Same as the client created by `discordgo.New`.
+		Timeout: 20 * time.Second,
+		Transport: &discordAPITransport{
+			baseURL: u,
+			next:    http.DefaultTransport,
+		},
+	}, nil
+}
@@ -0,0 +1,175 @@
+package interactionsapi
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/c032/ffxiv-world-status-discord/ffxivapi"
+)
+
+// manyWorlds returns enough worlds, with long names, to not fit in a single
+// message.
+func manyWorlds() []ffxivapi.World {
+	var worlds []ffxivapi.World
+	for group := 0; group < 8; group++ {
+		for world := 0; world < 60; world++ {
+			worlds = append(worlds, ffxivapi.World{
+				Group: fmt.Sprintf("Group %d", group),
+				Name:  fmt.Sprintf("World %02d %s", world, strings.Repeat("x", 20)),
+
+				IsMaintenance: true,
+			})
+		}
+	}
+
+	return worlds
+}
+
+// worldGroups returns the names of `worlds` grouped by their group.
+func worldGroups(worlds []ffxivapi.World) []worldGroup {
+	var groups []worldGroup
+	for _, w := range worlds {
+		if len(groups) == 0 || groups[len(groups)-1].Name != w.Group {
+			groups = append(groups, worldGroup{Name: w.Group})
+		}
+
+		group := &groups[len(groups)-1]
+		group.Lines = append(group.Lines, w.Name)
+	}
+
+	return groups
+}
+
+// embedsCharacters returns the number of characters of `embeds` that count
+// towards `messageEmbedsCharactersLimit`.
+func embedsCharacters(embeds []*discordgo.MessageEmbed) int {
+	var n int
+	for _, embed := range embeds {
+		n += countCharacters(embed.Title)
+		for _, field := range embed.Fields {
+			n += countCharacters(field.Name) + countCharacters(field.Value)
+		}
+		if embed.Footer != nil {
+			n += countCharacters(embed.Footer.Text)
+		}
+	}
+
+	return n
+}
+
+func TestRenderEmbedPages(t *testing.T) {
+	worlds := manyWorlds()
+
+	sections := []embedSection{
+		{Title: "Maintenance", Groups: worldGroups(worlds)},
+		{Title: "Character creation unavailable", Groups: worldGroups(worlds)},
+	}
+
+	pages := renderEmbedPages(sections, "https://example.com/thumbnail.png", pageNumberReserve)
+	if len(pages) < 2 {
+		t.Fatalf("len(pages) = %d; want more than 1", len(pages))
+	}
+
+	var rendered int
+	for i, page := range pages {
+		if len(page) > messageEmbedsLimit {
+			t.Errorf("page %d has %d embeds", i, len(page))
+		}
+		if n := embedsCharacters(page); n > messageEmbedsCharactersLimit-pageNumberReserve {
+			t.Errorf("page %d has %d characters", i, n)
+		}
+
+		for _, embed := range page {
+			if len(embed.Fields) > embedFieldsLimit {
+				t.Errorf("embed in page %d has %d fields", i, len(embed.Fields))
+			}
+
+			for _, field := range embed.Fields {
+				if n := countCharacters(field.Value); n > embedFieldValueLimit {
+					t.Errorf("field %#v in page %d has %d characters", field.Name, i, n)
+				}
+
+				rendered += len(strings.Split(field.Value, "\n"))
+			}
+		}
+	}
+
+	if want := 2 * len(worlds); rendered != want {
+		t.Errorf("rendered %d worlds; want %d", rendered, want)
+	}
+}
+
+func TestRenderEmbedPages_SinglePage(t *testing.T) {
+	pages := renderEmbedPages([]embedSection{
+		{Title: "Maintenance", Groups: worldGroups(e2eWorlds)},
+	}, "", pageNumberReserve)
+
+	if len(pages) != 1 || len(pages[0]) != 1 {
+		t.Fatalf("pages = %#v; want a single embed", pages)
+	}
+}
+
+func TestWorlds_Embed(t *testing.T) {
+	world := func(name string, group string) ffxivapi.World {
+		return ffxivapi.World{
+			Name:                   name,
+			Group:                  group,
+			IsOnline:               true,
+			CanCreateNewCharacters: true,
+		}
+	}
+
+	worlds := Worlds{
+		world("Ravana", "Materia"),
+		world("Zalera", "Crystal"),
+		world("Alpha", "Light"),
+		world("Bismarck", "Materia"),
+	}
+
+	embed, err := worlds.Embed("Worlds", "https://example.com/thumbnail.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := embed.Title, "Worlds"; got != want {
+		t.Errorf("embed.Title = %#v; want %#v", got, want)
+	}
+	if embed.Thumbnail == nil || embed.Thumbnail.URL != "https://example.com/thumbnail.png" {
+		t.Errorf("embed.Thumbnail = %#v", embed.Thumbnail)
+	}
+
+	// Data centers are in the same order as in responses and the status
+	// board, and worlds are rendered with the default templates.
+	online := DefaultStatusIcons.Online
+	var got []string
+	for _, field := range embed.Fields {
+		got = append(got, field.Name+": "+strings.ReplaceAll(field.Value, "\n", ", "))
+	}
+	want := []string{
+		"Light: " + online + " Alpha",
+		"Crystal: " + online + " Zalera",
+		"Materia: " + online + " Bismarck, " + online + " Ravana",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("fields = %#v; want %#v", got, want)
+	}
+
+	_, err = Worlds(manyWorlds()).Embed("Worlds", "")
+	if !errors.Is(err, ErrTooManyWorlds) {
+		t.Errorf("err = %v; want %v", err, ErrTooManyWorlds)
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	if got, want := truncate("ワールド", 3), "ワー…"; got != want {
+		t.Errorf("truncate() = %#v; want %#v", got, want)
+	}
+	if got, want := truncate("Alpha", 5), "Alpha"; got != want {
+		t.Errorf("truncate() = %#v; want %#v", got, want)
+	}
+}
@@ -0,0 +1,716 @@
+package interactionsapi
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"flag"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/c032/ffxiv-world-status-discord/discordfake"
+	"github.com/c032/ffxiv-world-status-discord/ffxivapi"
+	"github.com/c032/ffxiv-world-status-discord/simulator"
+)
+
+var updateGolden = flag.Bool("update", false, "update the golden files in testdata")
+
+// fakeAPIClient is an `ffxivapi.Client` whose responses can be changed by
+// tests.
+type fakeAPIClient struct {
+	mu     sync.Mutex
+	worlds []ffxivapi.World
+	err    error
+}
+
+func (c *fakeAPIClient) set(worlds []ffxivapi.World, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.worlds = worlds
+	c.err = err
+}
+
+func (c *fakeAPIClient) Worlds(ctx context.Context) (*ffxivapi.WorldsResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.err != nil {
+		return nil, c.err
+	}
+
+	return &ffxivapi.WorldsResponse{
+		Worlds: append([]ffxivapi.World(nil), c.worlds...),
+	}, nil
+}
+
+// e2eHarness runs a `Server` on an `httptest.Server`, with fakes of Discord
+// and the FFXIV API, and sends signed interactions to it.
+type e2eHarness struct {
+	t *testing.T
+
+	server  *Server
+	api     *fakeAPIClient
+	discord *discordfake.Server
+	client  *simulator.Client
+}
+
+func newE2EHarness(t *testing.T, worlds []ffxivapi.World) *e2eHarness {
+	t.Helper()
+
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	h := &e2eHarness{
+		t:       t,
+		api:     &fakeAPIClient{worlds: worlds},
+		discord: discordfake.NewServer(),
+	}
+	t.Cleanup(h.discord.Close)
+
+	h.server = &Server{
+		API:                 h.api,
+		GuildSettings:       NewMemoryGuildSettingsStore(),
+		DiscordThumbnailURL: "https://example.com/thumbnail.png",
+		Applications: []*Application{
+			{
+				Name:       "default",
+				ID:         simulator.DefaultContext.ApplicationID,
+				Token:      "token",
+				PublicKeys: NewPublicKeyRing(publicKey),
+			},
+		},
+		DiscordAPIURL: h.discord.URL,
+	}
+
+	err = h.server.Initialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		h.server.Cleanup()
+	})
+
+	err = h.server.RunCommandSync(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ts := httptest.NewServer(h.server)
+	t.Cleanup(ts.Close)
+
+	h.client = &simulator.Client{
+		URL: ts.URL + "/interactions",
+		Key: privateKey,
+	}
+
+	return h
+}
+
+// send sends `interaction` and returns the decoded response, failing the test
+// if the server doesn't respond with 200. The response is also recorded by
+// the fake Discord API, like Discord does.
+func (h *e2eHarness) send(interaction *discordgo.Interaction) *discordgo.InteractionResponse {
+	h.t.Helper()
+
+	resp, err := h.client.Send(context.Background(), interaction)
+	if err != nil {
+		h.t.Fatal(err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		h.t.Fatalf("resp.StatusCode = %d; want %d; body: %s", resp.StatusCode, http.StatusOK, resp.Pretty())
+	}
+
+	ir, err := resp.InteractionResponse()
+	if err != nil {
+		h.t.Fatal(err)
+	}
+
+	h.discord.Acknowledge(interaction, ir)
+
+	return ir
+}
+
+// sendDeferred sends `interaction`, expecting a deferred response, and
+// returns the original response once the server has edited it.
+func (h *e2eHarness) sendDeferred(interaction *discordgo.Interaction) *discordgo.Message {
+	h.t.Helper()
+
+	ir := h.send(interaction)
+	if ir.Type != discordgo.InteractionResponseDeferredChannelMessageWithSource {
+		h.t.Fatalf("ir.Type = %d; want %d", ir.Type, discordgo.InteractionResponseDeferredChannelMessageWithSource)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		msg := h.discord.OriginalResponse(interaction.Token)
+		if msg != nil && len(msg.Attachments) > 0 {
+			return msg
+		}
+
+		if time.Now().After(deadline) {
+			h.t.Fatalf("original response = %#v; want it to be edited", msg)
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// assertGolden compares `v`, encoded as indented JSON, with the file `name`
+// in `testdata/golden`. With `-update`, the file is replaced instead.
+func assertGolden(t *testing.T, name string, v any) {
+	t.Helper()
+
+	got, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got = append(got, '\n')
+
+	path := filepath.Join("testdata", "golden", name+".json")
+
+	if *updateGolden {
+		err = os.MkdirAll(filepath.Dir(path), 0o755)
+		if err == nil {
+			err = os.WriteFile(path, got, 0o644)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("could not read golden file (run with -update to create it): %s", err)
+	}
+
+	if !bytes.Equal(got, want) {
+		t.Fatalf("%s differs from the golden file (run with -update to replace it)\ngot:\n%s\nwant:\n%s", path, got, want)
+	}
+}
+
+var e2eWorlds = []ffxivapi.World{
+	{Group: "Light", Name: "Lich", IsOnline: true, CanCreateNewCharacters: false, IsCongested: true},
+	{Group: "Light", Name: "Odin", IsOnline: true, CanCreateNewCharacters: false, IsCongested: true},
+	{Group: "Light", Name: "Alpha", IsOnline: true, CanCreateNewCharacters: true, IsNew: true},
+	{Group: "Chaos", Name: "Omega", IsMaintenance: true, CanCreateNewCharacters: true},
+	{Group: "Aether", Name: "Gilgamesh", IsMaintenance: true, CanCreateNewCharacters: false},
+	{Group: "Elemental", Name: "Tonberry", IsOnline: true, CanCreateNewCharacters: true},
+}
+
+func TestE2E_Ping(t *testing.T) {
+	h := newE2EHarness(t, nil)
+
+	ir := h.send(simulator.DefaultContext.Ping())
+	if ir.Type != discordgo.InteractionResponsePong {
+		t.Fatalf("ir.Type = %d; want %d", ir.Type, discordgo.InteractionResponsePong)
+	}
+}
+
+func TestE2E_RegistersCommands(t *testing.T) {
+	h := newE2EHarness(t, nil)
+
+	h.discord.AssertCommands(t, simulator.DefaultContext.ApplicationID, "", CmdCharacters, CmdDataCenter, CmdPing, CmdSettings)
+}
+
+func TestE2E_KeepsUpToDateCommands(t *testing.T) {
+	h := newE2EHarness(t, nil)
+
+	// Initializing another server with the same commands doesn't change
+	// them.
+	s := &Server{
+		DiscordAPIURL: h.discord.URL,
+		Applications: []*Application{
+			{
+				Name:                  "default",
+				ID:                    simulator.DefaultContext.ApplicationID,
+				Token:                 "token",
+				SkipRequestValidation: true,
+			},
+		},
+	}
+
+	err := s.Initialize()
+	if err == nil {
+		defer s.Cleanup()
+
+		err = s.RunCommandSync(context.Background())
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var overwrites int
+	for _, req := range h.discord.Requests() {
+		if req.Method == http.MethodPut {
+			overwrites++
+		}
+	}
+	if overwrites != 1 {
+		t.Fatalf("commands were overwritten %d times; want 1", overwrites)
+	}
+}
+
+func TestE2E_ReadyAfterCommandSync(t *testing.T) {
+	discord := discordfake.NewServer()
+	t.Cleanup(discord.Close)
+
+	s := &Server{
+		API:           &fakeAPIClient{},
+		DiscordAPIURL: discord.URL,
+		Applications: []*Application{
+			{
+				Name:                  "default",
+				ID:                    simulator.DefaultContext.ApplicationID,
+				Token:                 "token",
+				SkipRequestValidation: true,
+			},
+		},
+	}
+
+	err := s.Initialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Cleanup()
+
+	ts := httptest.NewServer(s)
+	t.Cleanup(ts.Close)
+
+	readyz := func() int {
+		resp, err := http.Get(ts.URL + "/readyz")
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+
+		return resp.StatusCode
+	}
+
+	if got, want := readyz(), http.StatusServiceUnavailable; got != want {
+		t.Fatalf("status before synchronizing commands = %d; want %d", got, want)
+	}
+
+	// The first attempt fails, and the next one succeeds.
+	discord.FailNext(http.StatusInternalServerError)
+
+	err = s.RunCommandSync(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := readyz(), http.StatusOK; got != want {
+		t.Fatalf("status after synchronizing commands = %d; want %d", got, want)
+	}
+	discord.AssertCommands(t, simulator.DefaultContext.ApplicationID, "", CmdCharacters, CmdDataCenter, CmdPing, CmdSettings)
+}
+
+func TestE2E_CommandSyncDryRun(t *testing.T) {
+	discord := discordfake.NewServer()
+	t.Cleanup(discord.Close)
+
+	s := &Server{
+		API:               &fakeAPIClient{},
+		DiscordAPIURL:     discord.URL,
+		CommandSyncDryRun: true,
+		Applications: []*Application{
+			{
+				Name:                  "default",
+				ID:                    simulator.DefaultContext.ApplicationID,
+				Token:                 "token",
+				SkipRequestValidation: true,
+			},
+		},
+	}
+
+	err := s.Initialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Cleanup()
+
+	err = s.RunCommandSync(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ts := httptest.NewServer(s)
+	t.Cleanup(ts.Close)
+
+	resp, err := http.Get(ts.URL + "/readyz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	var health healthResponse
+	err = json.NewDecoder(resp.Body).Decode(&health)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := resp.StatusCode, http.StatusOK; got != want {
+		t.Fatalf("status after a dry run = %d; want %d", got, want)
+	}
+	if got, want := health.Checks["commands"].Status, healthStatusDryRun; got != want {
+		t.Fatalf("commands check = %#v; want %#v", got, want)
+	}
+
+	discord.AssertNotRequested(t, http.MethodPut)
+	discord.AssertCommands(t, simulator.DefaultContext.ApplicationID, "")
+}
+
+func TestE2E_Characters(t *testing.T) {
+	tests := []struct {
+		name      string
+		worlds    []ffxivapi.World
+		locale    discordgo.Locale
+		plainText bool
+	}{
+		{
+			name:   "characters",
+			worlds: e2eWorlds,
+			locale: discordgo.EnglishUS,
+		},
+		{
+			name:   "characters_ja",
+			worlds: e2eWorlds,
+			locale: discordgo.Japanese,
+		},
+		{
+			name:      "characters_plain_text",
+			worlds:    e2eWorlds,
+			locale:    discordgo.EnglishUS,
+			plainText: true,
+		},
+		{
+			name: "characters_everything_good",
+			worlds: []ffxivapi.World{
+				{Group: "Light", Name: "Alpha", IsOnline: true, CanCreateNewCharacters: true},
+			},
+			locale: discordgo.EnglishUS,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newE2EHarness(t, tt.worlds)
+
+			ic := simulator.DefaultContext
+			ic.Locale = tt.locale
+
+			err := h.server.GuildSettings.SetGuildSettings(ic.GuildID, GuildSettings{
+				PlainText: tt.plainText,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			ir := h.send(ic.Command(CmdCharacters))
+			if ir.Type != discordgo.InteractionResponseChannelMessageWithSource {
+				t.Fatalf("ir.Type = %d; want %d", ir.Type, discordgo.InteractionResponseChannelMessageWithSource)
+			}
+
+			assertGolden(t, tt.name, ir)
+		})
+	}
+}
+
+func TestE2E_CharactersPages(t *testing.T) {
+	h := newE2EHarness(t, manyWorlds())
+
+	ic := simulator.DefaultContext
+
+	ir := h.send(ic.Command(CmdCharacters))
+
+	components := ir.Data.Components
+	if len(components) != 1 {
+		t.Fatalf("len(components) = %d; want 1", len(components))
+	}
+
+	buttons := components[0].(*discordgo.ActionsRow).Components
+	previous := buttons[0].(*discordgo.Button)
+	next := buttons[1].(*discordgo.Button)
+	if !previous.Disabled || next.Disabled {
+		t.Fatalf("previous.Disabled = %t, next.Disabled = %t on the first page", previous.Disabled, next.Disabled)
+	}
+
+	ir = h.send(ic.Component(next.CustomID))
+	if ir.Type != discordgo.InteractionResponseUpdateMessage {
+		t.Fatalf("ir.Type = %d; want %d", ir.Type, discordgo.InteractionResponseUpdateMessage)
+	}
+
+	embeds := ir.Data.Embeds
+	footer := embeds[len(embeds)-1].Footer
+	if footer == nil || !strings.Contains(footer.Text, "Page 2 of ") {
+		t.Fatalf("footer = %#v; want page 2", footer)
+	}
+
+	// Pages past the end show the last page.
+	ir = h.send(ic.Component(componentCustomID(CmdCharacters, componentArgPage, "1000")))
+	buttons = ir.Data.Components[0].(*discordgo.ActionsRow).Components
+	if !buttons[1].(*discordgo.Button).Disabled {
+		t.Fatal("next button is enabled on the last page")
+	}
+
+	// Once everything is good, the buttons are removed.
+	h.api.set(e2eWorlds[:0], nil)
+
+	ir = h.send(ic.Component(next.CustomID))
+	if ir.Data.Components == nil || len(ir.Data.Components) != 0 || len(ir.Data.Embeds) != 0 {
+		t.Fatalf("ir.Data = %#v; want no embeds and no components", ir.Data)
+	}
+}
+
+func TestE2E_CharactersUpstreamError(t *testing.T) {
+	h := newE2EHarness(t, nil)
+	h.api.set(nil, errors.New("upstream is down"))
+
+	ir := h.send(simulator.DefaultContext.Command(CmdCharacters))
+
+	if ir.Data == nil || ir.Data.Flags&discordgo.MessageFlagsEphemeral == 0 {
+		t.Fatalf("response to an upstream error is not ephemeral: %#v", ir.Data)
+	}
+	if got, want := ir.Data.Content, localize(discordgo.EnglishUS, msgCouldNotCheckAvailability); got != want {
+		t.Fatalf("ir.Data.Content = %#v; want %#v", got, want)
+	}
+}
+
+// assertBoardFile fails the test unless `msg` has the status board attached.
+func (h *e2eHarness) assertBoardFile(msg *discordgo.Message) {
+	h.t.Helper()
+
+	files := h.discord.Files(msg.ID)
+	if len(files) != 1 {
+		h.t.Fatalf("files = %#v; want a single file", files)
+	}
+
+	f := files[0]
+	if f.Name != boardFileName || f.ContentType != "image/png" {
+		h.t.Fatalf("file is %#v (%#v); want %#v (%#v)", f.Name, f.ContentType, boardFileName, "image/png")
+	}
+
+	_, err := png.DecodeConfig(bytes.NewReader(f.Data))
+	if err != nil {
+		h.t.Fatalf("attached file is not a PNG: %s", err)
+	}
+}
+
+func TestE2E_CharactersImage(t *testing.T) {
+	h := newE2EHarness(t, e2eWorlds)
+
+	// Files can't be attached to the response to the HTTP request, so the
+	// response is deferred and edited afterwards.
+	msg := h.sendDeferred(simulator.DefaultContext.Command(CmdCharacters, simulator.Option(OptImage, true)))
+
+	h.assertBoardFile(msg)
+
+	// The embeds are the same as without the image.
+	assertGolden(t, "characters", &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds:     msg.Embeds,
+			Components: msg.Components,
+		},
+	})
+}
+
+func TestE2E_DataCenter(t *testing.T) {
+	h := newE2EHarness(t, e2eWorlds)
+
+	ic := simulator.DefaultContext
+
+	ir := h.send(ic.Command(CmdDataCenter, simulator.Option(OptName, "light")))
+	if ir.Data != nil && len(ir.Data.Files) != 0 {
+		t.Fatal("image was attached without the image option")
+	}
+
+	assertGolden(t, "datacenter", ir)
+
+	msg := h.sendDeferred(ic.Command(CmdDataCenter, simulator.Option(OptName, "Light"), simulator.Option(OptImage, true)))
+	h.assertBoardFile(msg)
+
+	ir = h.send(ic.Command(CmdDataCenter, simulator.Option(OptName, "Nowhere")))
+	if got, want := ir.Data.Content, "Unknown data center: Nowhere"; got != want {
+		t.Fatalf("ir.Data.Content = %#v; want %#v", got, want)
+	}
+	if ir.Data.Flags&discordgo.MessageFlagsEphemeral == 0 {
+		t.Fatal("response to an unknown data center is not ephemeral")
+	}
+}
+
+func TestE2E_DataCenterAutocomplete(t *testing.T) {
+	h := newE2EHarness(t, e2eWorlds)
+
+	ir := h.send(simulator.DefaultContext.Autocomplete(CmdDataCenter, simulator.Focused(simulator.Option(OptName, "L"))))
+	if ir.Type != discordgo.InteractionApplicationCommandAutocompleteResult {
+		t.Fatalf("ir.Type = %d; want %d", ir.Type, discordgo.InteractionApplicationCommandAutocompleteResult)
+	}
+
+	assertGolden(t, "datacenter_autocomplete", ir)
+}
+
+func TestE2E_ANSI(t *testing.T) {
+	h := newE2EHarness(t, e2eWorlds)
+
+	ic := simulator.DefaultContext
+
+	ir := h.send(ic.Command(CmdCharacters, simulator.Option(OptFormat, FormatANSI)))
+	if len(ir.Data.Embeds) != 0 {
+		t.Fatalf("len(ir.Data.Embeds) = %d; want 0", len(ir.Data.Embeds))
+	}
+
+	assertGolden(t, "characters_ansi", ir)
+
+	ir = h.send(ic.Command(CmdDataCenter, simulator.Option(OptName, "Light"), simulator.Option(OptFormat, FormatANSI)))
+
+	assertGolden(t, "datacenter_ansi", ir)
+}
+
+func TestE2E_Features(t *testing.T) {
+	h := newE2EHarness(t, e2eWorlds)
+
+	ic := simulator.DefaultContext
+
+	settings := h.server.settings()
+	settings.Features = Features{
+		DisableImages: true,
+		DisableANSI:   true,
+	}
+	h.server.Reload(settings)
+
+	ir := h.send(ic.Command(CmdCharacters, simulator.Option(OptFormat, FormatANSI), simulator.Option(OptImage, true)))
+	if ir.Type != discordgo.InteractionResponseChannelMessageWithSource {
+		t.Fatalf("ir.Type = %d; want the response without the image", ir.Type)
+	}
+
+	// Without ANSI, the embeds are the same as with the default format.
+	assertGolden(t, "characters", ir)
+
+	// Features can be enabled again while running.
+	h.server.Reload(ReloadableSettings{})
+
+	msg := h.sendDeferred(ic.Command(CmdCharacters, simulator.Option(OptImage, true)))
+	h.assertBoardFile(msg)
+}
+
+func TestE2E_ANSIPages(t *testing.T) {
+	h := newE2EHarness(t, manyWorlds())
+
+	ic := simulator.DefaultContext
+
+	ir := h.send(ic.Command(CmdCharacters, simulator.Option(OptFormat, FormatANSI)))
+	if !strings.HasPrefix(ir.Data.Content, ansiCodeBlockStart) {
+		t.Fatalf("ir.Data.Content = %#v; want an ansi code block", ir.Data.Content)
+	}
+	if n := countCharacters(ir.Data.Content); n > messageContentLimit {
+		t.Fatalf("content has %d characters; want at most %d", n, messageContentLimit)
+	}
+
+	buttons := ir.Data.Components[0].(*discordgo.ActionsRow).Components
+	next := buttons[1].(*discordgo.Button)
+	if !strings.HasSuffix(next.CustomID, ":"+FormatANSI) {
+		t.Fatalf("next.CustomID = %#v; want the format to be kept", next.CustomID)
+	}
+
+	ir = h.send(ic.Component(next.CustomID))
+	if !strings.HasPrefix(ir.Data.Content, ansiCodeBlockStart) || len(ir.Data.Embeds) != 0 {
+		t.Fatalf("ir.Data = %#v; want the second page as an ansi code block", ir.Data)
+	}
+	if !strings.Contains(ir.Data.Content, "Page 2 of ") {
+		t.Fatalf("ir.Data.Content = %#v; want page 2", ir.Data.Content)
+	}
+}
+
+func TestE2E_DataCenterANSIPages(t *testing.T) {
+	h := newE2EHarness(t, manyWorlds())
+
+	ic := simulator.DefaultContext
+
+	ir := h.send(ic.Command(CmdDataCenter, simulator.Option(OptName, "Group 3"), simulator.Option(OptFormat, FormatANSI)))
+	if !strings.HasPrefix(ir.Data.Content, ansiCodeBlockStart) {
+		t.Fatalf("ir.Data.Content = %#v; want an ansi code block", ir.Data.Content)
+	}
+	if n := countCharacters(ir.Data.Content); n > messageContentLimit {
+		t.Fatalf("content has %d characters; want at most %d", n, messageContentLimit)
+	}
+	if len(ir.Data.Components) == 0 {
+		t.Fatal("response has no buttons to change pages")
+	}
+
+	buttons := ir.Data.Components[0].(*discordgo.ActionsRow).Components
+	next := buttons[1].(*discordgo.Button)
+
+	ir = h.send(ic.Component(next.CustomID))
+	if ir.Type != discordgo.InteractionResponseUpdateMessage {
+		t.Fatalf("ir.Type = %d; want %d", ir.Type, discordgo.InteractionResponseUpdateMessage)
+	}
+	if !strings.HasPrefix(ir.Data.Content, ansiCodeBlockStart) || !strings.Contains(ir.Data.Content, "Page 2 of ") {
+		t.Fatalf("ir.Data.Content = %#v; want page 2 as an ansi code block", ir.Data.Content)
+	}
+	if strings.Contains(ir.Data.Content, "World 00") {
+		t.Fatalf("ir.Data.Content = %#v; want other worlds than the first page", ir.Data.Content)
+	}
+}
+
+func TestE2E_SettingsFormat(t *testing.T) {
+	h := newE2EHarness(t, e2eWorlds)
+
+	ic := simulator.DefaultContext
+
+	h.send(ic.Command(CmdSettings, simulator.Option(OptFormat, FormatANSI)))
+
+	ir := h.send(ic.Command(CmdCharacters))
+	if !strings.HasPrefix(ir.Data.Content, ansiCodeBlockStart) {
+		t.Fatalf("ir.Data.Content = %#v; want an ansi code block", ir.Data.Content)
+	}
+
+	// The option of the command takes precedence over the guild.
+	ir = h.send(ic.Command(CmdCharacters, simulator.Option(OptFormat, FormatEmbed)))
+	if len(ir.Data.Embeds) == 0 {
+		t.Fatal("response has no embeds with the embed format")
+	}
+}
+
+func TestE2E_SettingsEphemeral(t *testing.T) {
+	h := newE2EHarness(t, e2eWorlds)
+
+	ic := simulator.DefaultContext
+
+	ir := h.send(ic.Command(CmdCharacters))
+	if ir.Data.Flags&discordgo.MessageFlagsEphemeral != 0 {
+		t.Fatal("response is ephemeral before changing settings")
+	}
+
+	h.send(ic.Command(CmdSettings, simulator.Option(OptEphemeral, true)))
+
+	ir = h.send(ic.Command(CmdCharacters))
+	if ir.Data.Flags&discordgo.MessageFlagsEphemeral == 0 {
+		t.Fatal("response is not ephemeral after changing settings")
+	}
+
+	// Settings can only be changed by members that can manage the guild.
+	ic.Permissions = 0
+
+	ir = h.send(ic.Command(CmdSettings, simulator.Option(OptEphemeral, false)))
+	if got, want := ir.Data.Content, localize(discordgo.EnglishUS, msgMissingPermissions); got != want {
+		t.Fatalf("ir.Data.Content = %#v; want %#v", got, want)
+	}
+}
@@ -1,10 +1,24 @@
 package interactionsapi
 
+import (
+	"net/http"
+)
+
 const (
 	errTypePrefix = "https://ffxiv.c032.dev/discord#error/"
 
-	ErrTypeInternalServerError = errTypePrefix + "internal-server-error"
-	ErrTypeUnknownCommand      = errTypePrefix + "unknown-command"
+	ErrTypeInternalServerError        = errTypePrefix + "internal-server-error"
+	ErrTypeUnknownCommand             = errTypePrefix + "unknown-command"
+	ErrTypeMalformedPayload           = errTypePrefix + "malformed-payload"
+	ErrTypeUnsupportedInteractionType = errTypePrefix + "unsupported-interaction-type"
+	ErrTypeUpstreamFailure            = errTypePrefix + "upstream-failure"
+	ErrTypeInvalidSignature           = errTypePrefix + "invalid-signature"
+	ErrTypeInvalidTimestamp           = errTypePrefix + "invalid-timestamp"
+	ErrTypeReplayedInteraction        = errTypePrefix + "replayed-interaction"
+	ErrTypeRequestTooLarge            = errTypePrefix + "request-too-large"
+	ErrTypeUnsupportedMediaType       = errTypePrefix + "unsupported-media-type"
+	ErrTypeUnknownApplication         = errTypePrefix + "unknown-application"
+	ErrTypeShuttingDown               = errTypePrefix + "shutting-down"
 )
 
 // ErrorResponse is an object as defined by RFC 7807.
@@ -15,3 +29,98 @@ type ErrorResponse struct {
 	Detail   string `json:"detail,omitempty"`
 	Instance string `json:"instance,omitempty"`
 }
+
+// errorCatalogue contains the title and status of every known error type.
+var errorCatalogue = map[string]ErrorResponse{
+	ErrTypeInternalServerError: {
+		Title:  "Internal server error.",
+		Status: http.StatusInternalServerError,
+	},
+	ErrTypeUnknownCommand: {
+		Title:  "Unknown command.",
+		Status: http.StatusBadRequest,
+	},
+	ErrTypeMalformedPayload: {
+		Title:  "Malformed interaction payload.",
+		Status: http.StatusBadRequest,
+	},
+	ErrTypeUnsupportedInteractionType: {
+		Title:  "Unsupported interaction type.",
+		Status: http.StatusBadRequest,
+	},
+	ErrTypeUpstreamFailure: {
+		Title:  "Upstream API failure.",
+		Status: http.StatusBadGateway,
+	},
+	ErrTypeInvalidSignature: {
+		Title:  "Invalid request signature.",
+		Status: http.StatusUnauthorized,
+	},
+	ErrTypeInvalidTimestamp: {
+		Title:  "Request timestamp is invalid or outside the allowed skew.",
+		Status: http.StatusUnauthorized,
+	},
+	ErrTypeReplayedInteraction: {
+		Title:  "Interaction was already received.",
+		Status: http.StatusConflict,
+	},
+	ErrTypeRequestTooLarge: {
+		Title:  "Request body is too large.",
+		Status: http.StatusRequestEntityTooLarge,
+	},
+	ErrTypeUnsupportedMediaType: {
+		Title:  "Request body must be JSON.",
+		Status: http.StatusUnsupportedMediaType,
+	},
+	ErrTypeUnknownApplication: {
+		Title:  "Unknown application.",
+		Status: http.StatusNotFound,
+	},
+	ErrTypeShuttingDown: {
+		Title:  "Server is shutting down.",
+		Status: http.StatusServiceUnavailable,
+	},
+}
+
+// newErrorResponse returns an `ErrorResponse` with the title and status of
+// `errType` as defined in `errorCatalogue`.
+func newErrorResponse(errType string, detail string) ErrorResponse {
+	errorResponse := errorCatalogue[errType]
+	errorResponse.Type = errType
+	errorResponse.Detail = detail
+
+	return errorResponse
+}
+
+// InteractionError is an error that is shown to the user as a message,
+// instead of failing the interaction.
+type InteractionError struct {
+	// Problem describes the error for logs.
+	Problem ErrorResponse
+
+	// Message is the key of the message shown to the user.
+	Message messageKey
+
+	Err error
+}
+
+func (err *InteractionError) Error() string {
+	if err.Err == nil {
+		return err.Problem.Title
+	}
+
+	return err.Problem.Title + " " + err.Err.Error()
+}
+
+func (err *InteractionError) Unwrap() error {
+	return err.Err
+}
+
+// upstreamError returns an `InteractionError` for failures of the FFXIV API.
+func upstreamError(err error) *InteractionError {
+	return &InteractionError{
+		Problem: newErrorResponse(ErrTypeUpstreamFailure, err.Error()),
+		Message: msgCouldNotCheckAvailability,
+		Err:     err,
+	}
+}
@@ -0,0 +1,208 @@
+package interactionsapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// GuildSettings contains the preferences of a single guild.
+type GuildSettings struct {
+	// Ephemeral makes responses to informational commands visible only to
+	// the user that used the command, unless the command overrides it.
+	Ephemeral bool `json:"ephemeral"`
+
+	// PlainText makes world lists use the names of states instead of
+	// icons, e.g. for screen readers.
+	PlainText bool `json:"plain_text"`
+
+	// Format is the format of world lists (e.g. `FormatANSI`), unless the
+	// command overrides it. Empty means `FormatEmbed`.
+	Format string `json:"format"`
+}
+
+// GuildSettingsStore persists the settings of every guild.
+//
+// Guilds without stored settings have the zero value of `GuildSettings`.
+type GuildSettingsStore interface {
+	GuildSettings(guildID string) (GuildSettings, error)
+	SetGuildSettings(guildID string, settings GuildSettings) error
+}
+
+var (
+	_ GuildSettingsStore = (*memoryGuildSettingsStore)(nil)
+	_ GuildSettingsStore = (*fileGuildSettingsStore)(nil)
+)
+
+var ErrGuildSettingsStoreClosed = errors.New("guild settings store is closed")
+
+// NewMemoryGuildSettingsStore returns a `GuildSettingsStore` that doesn't
+// persist anything across restarts.
+func NewMemoryGuildSettingsStore() GuildSettingsStore {
+	return &memoryGuildSettingsStore{
+		settings: map[string]GuildSettings{},
+	}
+}
+
+type memoryGuildSettingsStore struct {
+	mu       sync.Mutex
+	settings map[string]GuildSettings
+}
+
+func (store *memoryGuildSettingsStore) GuildSettings(guildID string) (GuildSettings, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	return store.settings[guildID], nil
+}
+
+func (store *memoryGuildSettingsStore) SetGuildSettings(guildID string, settings GuildSettings) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	store.settings[guildID] = settings
+
+	return nil
+}
+
+// NewFileGuildSettingsStore returns a `GuildSettingsStore` that persists the
+// settings of every guild as a JSON object in the file at `path`.
+//
+// The file doesn't need to exist, and it's created on the first write. Its
+// directory must be writable, which is checked here so a misconfigured
+// volume fails on start instead of on the first change of settings.
+func NewFileGuildSettingsStore(path string) (GuildSettingsStore, error) {
+	store := &fileGuildSettingsStore{
+		path:     path,
+		settings: map[string]GuildSettings{},
+	}
+
+	err := checkWritableDir(filepath.Dir(path))
+	if err != nil {
+		return nil, err
+	}
+
+	rawSettings, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return store, nil
+		}
+
+		return nil, fmt.Errorf("could not read guild settings file: %w", err)
+	}
+
+	err = json.Unmarshal(rawSettings, &store.settings)
+	if err != nil {
+		return nil, fmt.Errorf("could not decode guild settings file: %w", err)
+	}
+
+	return store, nil
+}
+
+// checkWritableDir returns an error unless files can be created in `dir`,
+// the same way `fileGuildSettingsStore.write` does.
+func checkWritableDir(dir string) error {
+	tmpFile, err := os.CreateTemp(dir, ".write-check.*.tmp")
+	if err != nil {
+		return fmt.Errorf("could not write to guild settings directory: %w", err)
+	}
+
+	tmpFile.Close()
+
+	err = os.Remove(tmpFile.Name())
+	if err != nil {
+		return fmt.Errorf("could not write to guild settings directory: %w", err)
+	}
+
+	return nil
+}
+
+type fileGuildSettingsStore struct {
+	mu       sync.Mutex
+	path     string
+	settings map[string]GuildSettings
+	closed   bool
+}
+
+func (store *fileGuildSettingsStore) GuildSettings(guildID string) (GuildSettings, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	return store.settings[guildID], nil
+}
+
+func (store *fileGuildSettingsStore) SetGuildSettings(guildID string, settings GuildSettings) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	if store.closed {
+		return ErrGuildSettingsStoreClosed
+	}
+
+	previousSettings, hadPreviousSettings := store.settings[guildID]
+
+	store.settings[guildID] = settings
+
+	err := store.write()
+	if err != nil {
+		if hadPreviousSettings {
+			store.settings[guildID] = previousSettings
+		} else {
+			delete(store.settings, guildID)
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// Close waits until the file is written, if a write is in progress, and makes
+// later changes fail with `ErrGuildSettingsStoreClosed`.
+func (store *fileGuildSettingsStore) Close() error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	store.closed = true
+
+	return nil
+}
+
+// write replaces the contents of the file with the current settings.
+//
+// The caller must hold `store.mu`.
+func (store *fileGuildSettingsStore) write() error {
+	rawSettings, err := json.MarshalIndent(store.settings, "", "\t")
+	if err != nil {
+		return fmt.Errorf("could not encode guild settings: %w", err)
+	}
+
+	tmpFile, err := os.CreateTemp(filepath.Dir(store.path), filepath.Base(store.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("could not create temporary guild settings file: %w", err)
+	}
+	defer os.Remove(tmpFile.Name())
+
+	_, err = tmpFile.Write(append(rawSettings, '\n'))
+	if err != nil {
+		tmpFile.Close()
+
+		return fmt.Errorf("could not write temporary guild settings file: %w", err)
+	}
+
+	err = tmpFile.Close()
+	if err != nil {
+		return fmt.Errorf("could not close temporary guild settings file: %w", err)
+	}
+
+	err = os.Rename(tmpFile.Name(), store.path)
+	if err != nil {
+		return fmt.Errorf("could not replace guild settings file: %w", err)
+	}
+
+	return nil
+}
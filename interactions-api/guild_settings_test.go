@@ -0,0 +1,44 @@
+package interactionsapi
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestFileGuildSettingsStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "guild-settings.json")
+
+	store, err := NewFileGuildSettingsStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := GuildSettings{Ephemeral: true, Format: FormatANSI}
+
+	err = store.SetGuildSettings("1", want)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	store, err = NewFileGuildSettingsStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := store.GuildSettings("1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != want {
+		t.Fatalf("GuildSettings() = %#v; want %#v", got, want)
+	}
+}
+
+func TestFileGuildSettingsStore_NotWritable(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "missing", "guild-settings.json")
+
+	_, err := NewFileGuildSettingsStore(path)
+	if err == nil {
+		t.Fatal("expected an error for a directory that can't be written")
+	}
+}
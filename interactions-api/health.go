@@ -0,0 +1,119 @@
+package interactionsapi
+
+import (
+	"net/http"
+	"runtime/debug"
+
+	"github.com/c032/ffxiv-world-status-discord/ffxivapi"
+)
+
+const (
+	healthStatusOK          = "ok"
+	healthStatusUnavailable = "unavailable"
+
+	// healthStatusDryRun is the status of the commands check when they were
+	// only compared with a dry run.
+	healthStatusDryRun = "dry_run"
+)
+
+type healthResponse struct {
+	Status string                 `json:"status"`
+	Checks map[string]healthCheck `json:"checks,omitempty"`
+}
+
+type healthCheck struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+func newHealthCheck(err error) healthCheck {
+	if err != nil {
+		return healthCheck{
+			Status: healthStatusUnavailable,
+			Error:  err.Error(),
+		}
+	}
+
+	return healthCheck{
+		Status: healthStatusOK,
+	}
+}
+
+// handleHealthz responds successfully as long as the server is able to handle
+// requests.
+func (s *Server) handleHealthz(w http.ResponseWriter, req *http.Request) {
+	s.respondJSON(http.StatusOK, w, healthResponse{
+		Status: healthStatusOK,
+	})
+}
+
+// handleReadyz responds successfully if commands were synchronized, the
+// upstream API is usable, and the server is not shutting down.
+func (s *Server) handleReadyz(w http.ResponseWriter, req *http.Request) {
+	checks := map[string]healthCheck{
+		"commands": s.commandSyncHealthCheck(),
+		"shutdown": newHealthCheck(s.checkShutdown()),
+	}
+
+	if hc, ok := s.API.(ffxivapi.HealthChecker); ok {
+		checks["upstream"] = newHealthCheck(hc.CheckHealth())
+	}
+
+	resp := healthResponse{
+		Status: healthStatusOK,
+		Checks: checks,
+	}
+	statusCode := http.StatusOK
+
+	for _, check := range checks {
+		if check.Status == healthStatusUnavailable {
+			resp.Status = healthStatusUnavailable
+			statusCode = http.StatusServiceUnavailable
+
+			break
+		}
+	}
+
+	s.respondJSON(statusCode, w, resp)
+}
+
+// VersionInfo describes how the binary was built.
+type VersionInfo struct {
+	Path        string `json:"path,omitempty"`
+	Version     string `json:"version,omitempty"`
+	GoVersion   string `json:"go_version,omitempty"`
+	VCSRevision string `json:"vcs_revision,omitempty"`
+	VCSTime     string `json:"vcs_time,omitempty"`
+	VCSModified bool   `json:"vcs_modified,omitempty"`
+}
+
+// BuildVersion returns information about how the binary was built.
+func BuildVersion() VersionInfo {
+	var resp VersionInfo
+
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return resp
+	}
+
+	resp.Path = info.Main.Path
+	resp.Version = info.Main.Version
+	resp.GoVersion = info.GoVersion
+
+	for _, setting := range info.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			resp.VCSRevision = setting.Value
+		case "vcs.time":
+			resp.VCSTime = setting.Value
+		case "vcs.modified":
+			resp.VCSModified = setting.Value == "true"
+		}
+	}
+
+	return resp
+}
+
+func (s *Server) handleVersion(w http.ResponseWriter, req *http.Request) {
+	s.respondJSON(http.StatusOK, w, BuildVersion())
+}
@@ -0,0 +1,97 @@
+package interactionsapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/c032/ffxiv-world-status-discord/ffxivapi"
+)
+
+type unhealthyAPIClient struct{}
+
+func (unhealthyAPIClient) Worlds(ctx context.Context) (*ffxivapi.WorldsResponse, error) {
+	return nil, ffxivapi.ErrCircuitOpen
+}
+
+func (unhealthyAPIClient) CheckHealth() error {
+	return ffxivapi.ErrCircuitOpen
+}
+
+func newHealthTestServer(t *testing.T) *Server {
+	t.Helper()
+
+	s := &Server{
+		Applications: []*Application{
+			{Name: "default"},
+		},
+	}
+
+	err := s.initializeRouter()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return s
+}
+
+func getHealth(t *testing.T, s *Server, path string) (int, healthResponse) {
+	t.Helper()
+
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+
+	var resp healthResponse
+	err := json.Unmarshal(w.Body.Bytes(), &resp)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return w.Code, resp
+}
+
+func TestServer_Healthz(t *testing.T) {
+	s := newHealthTestServer(t)
+
+	if code, _ := getHealth(t, s, "/healthz"); code != http.StatusOK {
+		t.Errorf("status = %d; want %d", code, http.StatusOK)
+	}
+}
+
+func TestServer_Readyz(t *testing.T) {
+	s := newHealthTestServer(t)
+
+	code, resp := getHealth(t, s, "/readyz")
+	if code != http.StatusServiceUnavailable {
+		t.Errorf("status before command sync = %d; want %d", code, http.StatusServiceUnavailable)
+	}
+	if got, want := resp.Checks["commands"].Status, healthStatusUnavailable; got != want {
+		t.Errorf("commands check = %#v; want %#v", got, want)
+	}
+
+	s.setCommandSyncStatus(false, nil)
+
+	if code, resp := getHealth(t, s, "/readyz"); code != http.StatusOK {
+		t.Errorf("status after command sync = %d; want %d (%#v)", code, http.StatusOK, resp)
+	}
+
+	s.setCommandSyncStatus(false, errors.New("test"))
+
+	if code, _ := getHealth(t, s, "/readyz"); code != http.StatusServiceUnavailable {
+		t.Errorf("status after failed command sync = %d; want %d", code, http.StatusServiceUnavailable)
+	}
+
+	s.setCommandSyncStatus(false, nil)
+	s.API = unhealthyAPIClient{}
+
+	code, resp = getHealth(t, s, "/readyz")
+	if code != http.StatusServiceUnavailable {
+		t.Errorf("status with unhealthy upstream = %d; want %d", code, http.StatusServiceUnavailable)
+	}
+	if got, want := resp.Checks["upstream"].Status, healthStatusUnavailable; got != want {
+		t.Errorf("upstream check = %#v; want %#v", got, want)
+	}
+}
@@ -0,0 +1,402 @@
+package interactionsapi
+
+import (
+	"github.com/bwmarrin/discordgo"
+)
+
+// defaultLocale is the locale used for the non-localized fields of commands,
+// and as fallback for any key missing from another locale.
+const defaultLocale = discordgo.EnglishUS
+
+type messageKey string
+
+const (
+	msgPong                         messageKey = "response.pong"
+	msgEverythingLooksGood          messageKey = "response.everything-looks-good"
+	msgCouldNotCheckAvailability    messageKey = "response.could-not-check-availability"
+	msgTitleMaintenance             messageKey = "embed.title.maintenance"
+	msgTitleCharacterCreationLocked messageKey = "embed.title.character-creation-unavailable"
+	msgYes                          messageKey = "response.yes"
+	msgNo                           messageKey = "response.no"
+	msgGuildOnly                    messageKey = "response.guild-only"
+	msgSettingsUpdated              messageKey = "response.settings-updated"
+	msgSettingsEphemeral            messageKey = "response.settings-ephemeral"
+	msgCouldNotLoadSettings         messageKey = "response.could-not-load-settings"
+	msgCouldNotSaveSettings         messageKey = "response.could-not-save-settings"
+	msgMissingPermissions           messageKey = "response.missing-permissions"
+	msgCooldown                     messageKey = "response.cooldown"
+	msgInternalError                messageKey = "response.internal-error"
+	msgPage                         messageKey = "embed.footer.page"
+	msgPreviousPage                 messageKey = "button.previous-page"
+	msgNextPage                     messageKey = "button.next-page"
+	msgSettingsPlainText            messageKey = "response.settings-plain-text"
+	msgStateOnline                  messageKey = "world.state.online"
+	msgStateMaintenance             messageKey = "world.state.maintenance"
+	msgStateCongested               messageKey = "world.state.congested"
+	msgStatePreferred               messageKey = "world.state.preferred"
+	msgStateNew                     messageKey = "world.state.new"
+	msgStateCharacterCreationLocked messageKey = "world.state.character-creation-locked"
+	msgUnknownDataCenter            messageKey = "response.unknown-data-center"
+	msgSettingsFormat               messageKey = "response.settings-format"
+)
+
+// commandNameKey returns the key for the localized name of the command.
+func commandNameKey(commandName string) messageKey {
+	return messageKey("command." + commandName + ".name")
+}
+
+// commandDescriptionKey returns the key for the localized description of the
+// command.
+func commandDescriptionKey(commandName string) messageKey {
+	return messageKey("command." + commandName + ".description")
+}
+
+// commandOptionNameKey returns the key for the localized name of an option of
+// the command.
+func commandOptionNameKey(commandName string, optionName string) messageKey {
+	return messageKey("command." + commandName + ".option." + optionName + ".name")
+}
+
+// commandOptionDescriptionKey returns the key for the localized description of
+// an option of the command.
+func commandOptionDescriptionKey(commandName string, optionName string) messageKey {
+	return messageKey("command." + commandName + ".option." + optionName + ".description")
+}
+
+// commandOptionChoiceNameKey returns the key for the localized name of the
+// choice with `value` of an option of the command.
+func commandOptionChoiceNameKey(commandName string, optionName string, value string) messageKey {
+	return messageKey("command." + commandName + ".option." + optionName + ".choice." + value + ".name")
+}
+
+// catalogue contains the translations for every supported locale.
+//
+// Every key present in `defaultLocale` should also be present in every other
+// locale.
+var catalogue = map[discordgo.Locale]map[messageKey]string{
+	discordgo.EnglishUS: {
+		commandNameKey(CmdPing):                                           "ping",
+		commandDescriptionKey(CmdPing):                                    "Make the bot respond with a pong.",
+		commandNameKey(CmdCharacters):                                     "characters",
+		commandDescriptionKey(CmdCharacters):                              "Print character creation availability status of all worlds.",
+		commandNameKey(CmdSettings):                                       "settings",
+		commandDescriptionKey(CmdSettings):                                "View or change the settings of the bot for this server.",
+		commandOptionNameKey(CmdCharacters, OptEphemeral):                 "ephemeral",
+		commandOptionDescriptionKey(CmdCharacters, OptEphemeral):          "Show the response only to you.",
+		commandOptionNameKey(CmdSettings, OptEphemeral):                   "ephemeral",
+		commandOptionDescriptionKey(CmdSettings, OptEphemeral):            "Show responses only to the user that used the command, by default.",
+		commandOptionNameKey(CmdSettings, OptPlainText):                   "plain_text",
+		commandOptionDescriptionKey(CmdSettings, OptPlainText):            "Use words instead of icons in world lists, e.g. for screen readers.",
+		commandNameKey(CmdDataCenter):                                     "datacenter",
+		commandDescriptionKey(CmdDataCenter):                              "Print the status of every world of a data center.",
+		commandOptionNameKey(CmdDataCenter, OptName):                      "name",
+		commandOptionDescriptionKey(CmdDataCenter, OptName):               "Name of the data center.",
+		commandOptionNameKey(CmdDataCenter, OptEphemeral):                 "ephemeral",
+		commandOptionDescriptionKey(CmdDataCenter, OptEphemeral):          "Show the response only to you.",
+		commandOptionNameKey(CmdDataCenter, OptImage):                     "image",
+		commandOptionDescriptionKey(CmdDataCenter, OptImage):              "Attach an image with the status of the worlds.",
+		commandOptionNameKey(CmdCharacters, OptImage):                     "image",
+		commandOptionDescriptionKey(CmdCharacters, OptImage):              "Attach an image with the status of every world.",
+		commandOptionNameKey(CmdCharacters, OptFormat):                    "format",
+		commandOptionDescriptionKey(CmdCharacters, OptFormat):             "Format of the list of worlds.",
+		commandOptionChoiceNameKey(CmdCharacters, OptFormat, FormatEmbed): "Embed",
+		commandOptionChoiceNameKey(CmdCharacters, OptFormat, FormatANSI):  "ANSI code block",
+		commandOptionNameKey(CmdDataCenter, OptFormat):                    "format",
+		commandOptionDescriptionKey(CmdDataCenter, OptFormat):             "Format of the list of worlds.",
+		commandOptionChoiceNameKey(CmdDataCenter, OptFormat, FormatEmbed): "Embed",
+		commandOptionChoiceNameKey(CmdDataCenter, OptFormat, FormatANSI):  "ANSI code block",
+		commandOptionNameKey(CmdSettings, OptFormat):                      "format",
+		commandOptionDescriptionKey(CmdSettings, OptFormat):               "Format of world lists, by default.",
+		commandOptionChoiceNameKey(CmdSettings, OptFormat, FormatEmbed):   "Embed",
+		commandOptionChoiceNameKey(CmdSettings, OptFormat, FormatANSI):    "ANSI code block",
+
+		msgPong:                         "Pong.",
+		msgEverythingLooksGood:          "Everything looks good.",
+		msgCouldNotCheckAvailability:    "Could not check availability.",
+		msgTitleMaintenance:             "Maintenance",
+		msgTitleCharacterCreationLocked: "Character creation unavailable",
+		msgYes:                          "Yes",
+		msgNo:                           "No",
+		msgGuildOnly:                    "This command can only be used in a server.",
+		msgSettingsUpdated:              "Settings updated.",
+		msgSettingsEphemeral:            "Ephemeral responses by default: %s",
+		msgCouldNotLoadSettings:         "Could not load settings.",
+		msgCouldNotSaveSettings:         "Could not save settings.",
+		msgMissingPermissions:           "You don't have permission to use this command.",
+		msgCooldown:                     "Please wait %s before using this command again.",
+		msgInternalError:                "Something went wrong. Please try again later.",
+		msgPage:                         "Page %d of %d",
+		msgPreviousPage:                 "Previous",
+		msgNextPage:                     "Next",
+		msgSettingsPlainText:            "Plain text world lists: %s",
+		msgStateOnline:                  "Online",
+		msgStateMaintenance:             "Maintenance",
+		msgStateCongested:               "Congested",
+		msgStatePreferred:               "Preferred",
+		msgStateNew:                     "New",
+		msgStateCharacterCreationLocked: "Creation locked",
+		msgSettingsFormat:               "World list format: %s",
+		msgUnknownDataCenter:            "Unknown data center: %s",
+	},
+	discordgo.Japanese: {
+		commandNameKey(CmdPing):                                           "ping",
+		commandDescriptionKey(CmdPing):                                    "ボットがポンと応答します。",
+		commandNameKey(CmdCharacters):                                     "キャラクター",
+		commandDescriptionKey(CmdCharacters):                              "全ワールドのキャラクター作成の可否を表示します。",
+		commandNameKey(CmdSettings):                                       "設定",
+		commandDescriptionKey(CmdSettings):                                "このサーバーでのボットの設定を表示または変更します。",
+		commandOptionNameKey(CmdCharacters, OptEphemeral):                 "非公開",
+		commandOptionDescriptionKey(CmdCharacters, OptEphemeral):          "応答を自分だけに表示します。",
+		commandOptionNameKey(CmdSettings, OptEphemeral):                   "非公開",
+		commandOptionDescriptionKey(CmdSettings, OptEphemeral):            "デフォルトで、応答をコマンドを使用したユーザーだけに表示します。",
+		commandOptionNameKey(CmdSettings, OptPlainText):                   "テキスト表示",
+		commandOptionDescriptionKey(CmdSettings, OptPlainText):            "ワールド一覧でアイコンの代わりに文字を使用します（スクリーンリーダー向けなど）。",
+		commandNameKey(CmdDataCenter):                                     "データセンター",
+		commandDescriptionKey(CmdDataCenter):                              "データセンターの全ワールドの状況を表示します。",
+		commandOptionNameKey(CmdDataCenter, OptName):                      "名前",
+		commandOptionDescriptionKey(CmdDataCenter, OptName):               "データセンターの名前。",
+		commandOptionNameKey(CmdDataCenter, OptEphemeral):                 "非公開",
+		commandOptionDescriptionKey(CmdDataCenter, OptEphemeral):          "応答を自分だけに表示します。",
+		commandOptionNameKey(CmdDataCenter, OptImage):                     "画像",
+		commandOptionDescriptionKey(CmdDataCenter, OptImage):              "ワールドの状況を示す画像を添付します。",
+		commandOptionNameKey(CmdCharacters, OptImage):                     "画像",
+		commandOptionDescriptionKey(CmdCharacters, OptImage):              "全ワールドの状況を示す画像を添付します。",
+		commandOptionNameKey(CmdCharacters, OptFormat):                    "形式",
+		commandOptionDescriptionKey(CmdCharacters, OptFormat):             "ワールド一覧の形式。",
+		commandOptionChoiceNameKey(CmdCharacters, OptFormat, FormatEmbed): "埋め込み",
+		commandOptionChoiceNameKey(CmdCharacters, OptFormat, FormatANSI):  "ANSIコードブロック",
+		commandOptionNameKey(CmdDataCenter, OptFormat):                    "形式",
+		commandOptionDescriptionKey(CmdDataCenter, OptFormat):             "ワールド一覧の形式。",
+		commandOptionChoiceNameKey(CmdDataCenter, OptFormat, FormatEmbed): "埋め込み",
+		commandOptionChoiceNameKey(CmdDataCenter, OptFormat, FormatANSI):  "ANSIコードブロック",
+		commandOptionNameKey(CmdSettings, OptFormat):                      "形式",
+		commandOptionDescriptionKey(CmdSettings, OptFormat):               "デフォルトのワールド一覧の形式。",
+		commandOptionChoiceNameKey(CmdSettings, OptFormat, FormatEmbed):   "埋め込み",
+		commandOptionChoiceNameKey(CmdSettings, OptFormat, FormatANSI):    "ANSIコードブロック",
+
+		msgPong:                         "ポン。",
+		msgEverythingLooksGood:          "すべて正常です。",
+		msgCouldNotCheckAvailability:    "状況を確認できませんでした。",
+		msgTitleMaintenance:             "メンテナンス中",
+		msgTitleCharacterCreationLocked: "キャラクター作成不可",
+		msgYes:                          "はい",
+		msgNo:                           "いいえ",
+		msgGuildOnly:                    "このコマンドはサーバー内でのみ使用できます。",
+		msgSettingsUpdated:              "設定を更新しました。",
+		msgSettingsEphemeral:            "デフォルトで非公開の応答: %s",
+		msgCouldNotLoadSettings:         "設定を読み込めませんでした。",
+		msgCouldNotSaveSettings:         "設定を保存できませんでした。",
+		msgMissingPermissions:           "このコマンドを使用する権限がありません。",
+		msgCooldown:                     "このコマンドを再度使用するには %s お待ちください。",
+		msgInternalError:                "問題が発生しました。しばらくしてからもう一度お試しください。",
+		msgPage:                         "%d / %d ページ",
+		msgPreviousPage:                 "前へ",
+		msgNextPage:                     "次へ",
+		msgSettingsPlainText:            "ワールド一覧のテキスト表示: %s",
+		msgStateOnline:                  "オンライン",
+		msgStateMaintenance:             "メンテナンス",
+		msgStateCongested:               "混雑",
+		msgStatePreferred:               "優遇",
+		msgStateNew:                     "新規",
+		msgStateCharacterCreationLocked: "作成不可",
+		msgSettingsFormat:               "ワールド一覧の形式: %s",
+		msgUnknownDataCenter:            "不明なデータセンターです: %s",
+	},
+	discordgo.German: {
+		commandNameKey(CmdPing):                                           "ping",
+		commandDescriptionKey(CmdPing):                                    "Lässt den Bot mit einem Pong antworten.",
+		commandNameKey(CmdCharacters):                                     "charaktere",
+		commandDescriptionKey(CmdCharacters):                              "Zeigt für alle Welten an, ob neue Charaktere erstellt werden können.",
+		commandNameKey(CmdSettings):                                       "einstellungen",
+		commandDescriptionKey(CmdSettings):                                "Zeigt oder ändert die Einstellungen des Bots für diesen Server.",
+		commandOptionNameKey(CmdCharacters, OptEphemeral):                 "privat",
+		commandOptionDescriptionKey(CmdCharacters, OptEphemeral):          "Zeigt die Antwort nur dir an.",
+		commandOptionNameKey(CmdSettings, OptEphemeral):                   "privat",
+		commandOptionDescriptionKey(CmdSettings, OptEphemeral):            "Zeigt Antworten standardmäßig nur dem Benutzer an, der den Befehl verwendet hat.",
+		commandOptionNameKey(CmdSettings, OptPlainText):                   "klartext",
+		commandOptionDescriptionKey(CmdSettings, OptPlainText):            "Verwendet in Weltenlisten Wörter statt Symbole, z. B. für Screenreader.",
+		commandNameKey(CmdDataCenter):                                     "datenzentrum",
+		commandDescriptionKey(CmdDataCenter):                              "Zeigt den Status aller Welten eines Datenzentrums an.",
+		commandOptionNameKey(CmdDataCenter, OptName):                      "name",
+		commandOptionDescriptionKey(CmdDataCenter, OptName):               "Name des Datenzentrums.",
+		commandOptionNameKey(CmdDataCenter, OptEphemeral):                 "privat",
+		commandOptionDescriptionKey(CmdDataCenter, OptEphemeral):          "Zeigt die Antwort nur dir an.",
+		commandOptionNameKey(CmdDataCenter, OptImage):                     "bild",
+		commandOptionDescriptionKey(CmdDataCenter, OptImage):              "Hängt ein Bild mit dem Status der Welten an.",
+		commandOptionNameKey(CmdCharacters, OptImage):                     "bild",
+		commandOptionDescriptionKey(CmdCharacters, OptImage):              "Hängt ein Bild mit dem Status aller Welten an.",
+		commandOptionNameKey(CmdCharacters, OptFormat):                    "format",
+		commandOptionDescriptionKey(CmdCharacters, OptFormat):             "Format der Weltenliste.",
+		commandOptionChoiceNameKey(CmdCharacters, OptFormat, FormatEmbed): "Einbettung",
+		commandOptionChoiceNameKey(CmdCharacters, OptFormat, FormatANSI):  "ANSI-Codeblock",
+		commandOptionNameKey(CmdDataCenter, OptFormat):                    "format",
+		commandOptionDescriptionKey(CmdDataCenter, OptFormat):             "Format der Weltenliste.",
+		commandOptionChoiceNameKey(CmdDataCenter, OptFormat, FormatEmbed): "Einbettung",
+		commandOptionChoiceNameKey(CmdDataCenter, OptFormat, FormatANSI):  "ANSI-Codeblock",
+		commandOptionNameKey(CmdSettings, OptFormat):                      "format",
+		commandOptionDescriptionKey(CmdSettings, OptFormat):               "Standardformat der Weltenlisten.",
+		commandOptionChoiceNameKey(CmdSettings, OptFormat, FormatEmbed):   "Einbettung",
+		commandOptionChoiceNameKey(CmdSettings, OptFormat, FormatANSI):    "ANSI-Codeblock",
+
+		msgPong:                         "Pong.",
+		msgEverythingLooksGood:          "Alles sieht gut aus.",
+		msgCouldNotCheckAvailability:    "Die Verfügbarkeit konnte nicht geprüft werden.",
+		msgTitleMaintenance:             "Wartungsarbeiten",
+		msgTitleCharacterCreationLocked: "Charaktererstellung nicht möglich",
+		msgYes:                          "Ja",
+		msgNo:                           "Nein",
+		msgGuildOnly:                    "Dieser Befehl kann nur auf einem Server verwendet werden.",
+		msgSettingsUpdated:              "Einstellungen aktualisiert.",
+		msgSettingsEphemeral:            "Standardmäßig private Antworten: %s",
+		msgCouldNotLoadSettings:         "Die Einstellungen konnten nicht geladen werden.",
+		msgCouldNotSaveSettings:         "Die Einstellungen konnten nicht gespeichert werden.",
+		msgMissingPermissions:           "Du hast keine Berechtigung, diesen Befehl zu verwenden.",
+		msgCooldown:                     "Bitte warte %s, bevor du diesen Befehl erneut verwendest.",
+		msgInternalError:                "Etwas ist schiefgelaufen. Bitte versuche es später erneut.",
+		msgPage:                         "Seite %d von %d",
+		msgPreviousPage:                 "Zurück",
+		msgNextPage:                     "Weiter",
+		msgSettingsPlainText:            "Weltenlisten als Klartext: %s",
+		msgStateOnline:                  "Online",
+		msgStateMaintenance:             "Wartung",
+		msgStateCongested:               "Überlastet",
+		msgStatePreferred:               "Bevorzugt",
+		msgStateNew:                     "Neu",
+		msgStateCharacterCreationLocked: "Erstellung gesperrt",
+		msgSettingsFormat:               "Format der Weltenlisten: %s",
+		msgUnknownDataCenter:            "Unbekanntes Datenzentrum: %s",
+	},
+	discordgo.French: {
+		commandNameKey(CmdPing):                                           "ping",
+		commandDescriptionKey(CmdPing):                                    "Fait répondre le bot avec un pong.",
+		commandNameKey(CmdCharacters):                                     "personnages",
+		commandDescriptionKey(CmdCharacters):                              "Affiche la disponibilité de la création de personnages sur tous les mondes.",
+		commandNameKey(CmdSettings):                                       "paramètres",
+		commandDescriptionKey(CmdSettings):                                "Affiche ou modifie les paramètres du bot pour ce serveur.",
+		commandOptionNameKey(CmdCharacters, OptEphemeral):                 "privé",
+		commandOptionDescriptionKey(CmdCharacters, OptEphemeral):          "Affiche la réponse uniquement pour vous.",
+		commandOptionNameKey(CmdSettings, OptEphemeral):                   "privé",
+		commandOptionDescriptionKey(CmdSettings, OptEphemeral):            "Par défaut, n'affiche les réponses qu'à l'utilisateur ayant utilisé la commande.",
+		commandOptionNameKey(CmdSettings, OptPlainText):                   "texte_brut",
+		commandOptionDescriptionKey(CmdSettings, OptPlainText):            "Utilise des mots au lieu d'icônes dans les listes de mondes, par ex. pour les lecteurs d'écran.",
+		commandNameKey(CmdDataCenter):                                     "centre-de-données",
+		commandDescriptionKey(CmdDataCenter):                              "Affiche l'état de tous les mondes d'un centre de données.",
+		commandOptionNameKey(CmdDataCenter, OptName):                      "nom",
+		commandOptionDescriptionKey(CmdDataCenter, OptName):               "Nom du centre de données.",
+		commandOptionNameKey(CmdDataCenter, OptEphemeral):                 "privé",
+		commandOptionDescriptionKey(CmdDataCenter, OptEphemeral):          "Affiche la réponse uniquement pour vous.",
+		commandOptionNameKey(CmdDataCenter, OptImage):                     "image",
+		commandOptionDescriptionKey(CmdDataCenter, OptImage):              "Joint une image avec l'état des mondes.",
+		commandOptionNameKey(CmdCharacters, OptImage):                     "image",
+		commandOptionDescriptionKey(CmdCharacters, OptImage):              "Joint une image avec l'état de tous les mondes.",
+		commandOptionNameKey(CmdCharacters, OptFormat):                    "format",
+		commandOptionDescriptionKey(CmdCharacters, OptFormat):             "Format de la liste des mondes.",
+		commandOptionChoiceNameKey(CmdCharacters, OptFormat, FormatEmbed): "Intégration",
+		commandOptionChoiceNameKey(CmdCharacters, OptFormat, FormatANSI):  "Bloc de code ANSI",
+		commandOptionNameKey(CmdDataCenter, OptFormat):                    "format",
+		commandOptionDescriptionKey(CmdDataCenter, OptFormat):             "Format de la liste des mondes.",
+		commandOptionChoiceNameKey(CmdDataCenter, OptFormat, FormatEmbed): "Intégration",
+		commandOptionChoiceNameKey(CmdDataCenter, OptFormat, FormatANSI):  "Bloc de code ANSI",
+		commandOptionNameKey(CmdSettings, OptFormat):                      "format",
+		commandOptionDescriptionKey(CmdSettings, OptFormat):               "Format des listes de mondes, par défaut.",
+		commandOptionChoiceNameKey(CmdSettings, OptFormat, FormatEmbed):   "Intégration",
+		commandOptionChoiceNameKey(CmdSettings, OptFormat, FormatANSI):    "Bloc de code ANSI",
+
+		msgPong:                         "Pong.",
+		msgEverythingLooksGood:          "Tout semble normal.",
+		msgCouldNotCheckAvailability:    "Impossible de vérifier la disponibilité.",
+		msgTitleMaintenance:             "Maintenance",
+		msgTitleCharacterCreationLocked: "Création de personnage indisponible",
+		msgYes:                          "Oui",
+		msgNo:                           "Non",
+		msgGuildOnly:                    "Cette commande ne peut être utilisée que sur un serveur.",
+		msgSettingsUpdated:              "Paramètres mis à jour.",
+		msgSettingsEphemeral:            "Réponses privées par défaut : %s",
+		msgCouldNotLoadSettings:         "Impossible de charger les paramètres.",
+		msgCouldNotSaveSettings:         "Impossible d'enregistrer les paramètres.",
+		msgMissingPermissions:           "Vous n'avez pas la permission d'utiliser cette commande.",
+		msgCooldown:                     "Veuillez patienter %s avant de réutiliser cette commande.",
+		msgInternalError:                "Une erreur s'est produite. Veuillez réessayer plus tard.",
+		msgPage:                         "Page %d sur %d",
+		msgPreviousPage:                 "Précédent",
+		msgNextPage:                     "Suivant",
+		msgSettingsPlainText:            "Listes de mondes en texte brut : %s",
+		msgStateOnline:                  "En ligne",
+		msgStateMaintenance:             "Maintenance",
+		msgStateCongested:               "Encombré",
+		msgStatePreferred:               "Recommandé",
+		msgStateNew:                     "Nouveau",
+		msgStateCharacterCreationLocked: "Création bloquée",
+		msgSettingsFormat:               "Format des listes de mondes : %s",
+		msgUnknownDataCenter:            "Centre de données inconnu : %s",
+	},
+}
+
+// localize returns the translation of `key` for `locale`.
+//
+// Locales that are not in the catalogue, and keys that are missing from a
+// locale, fall back to `defaultLocale`. If the key is missing from
+// `defaultLocale` too, the key itself is returned.
+func localize(locale discordgo.Locale, key messageKey) string {
+	if messages, ok := catalogue[locale]; ok {
+		if msg, ok := messages[key]; ok {
+			return msg
+		}
+	}
+
+	if msg, ok := catalogue[defaultLocale][key]; ok {
+		return msg
+	}
+
+	return string(key)
+}
+
+// localizations returns the translations of `key` for every locale except
+// `defaultLocale`, in the format expected by Discord for the
+// `*_localizations` fields.
+func localizations(key messageKey) map[discordgo.Locale]string {
+	result := map[discordgo.Locale]string{}
+	for locale, messages := range catalogue {
+		if locale == defaultLocale {
+			continue
+		}
+
+		msg, ok := messages[key]
+		if !ok {
+			continue
+		}
+
+		result[locale] = msg
+	}
+
+	return result
+}
+
+// localizeBool returns the translation of "yes" or "no" for `locale`.
+func localizeBool(locale discordgo.Locale, value bool) string {
+	if value {
+		return localize(locale, msgYes)
+	}
+
+	return localize(locale, msgNo)
+}
+
+// interactionLocale returns the locale that should be used to respond to
+// `interaction`.
+//
+// The user's locale is preferred over the guild's locale.
+func interactionLocale(interaction *discordgo.Interaction) discordgo.Locale {
+	if interaction == nil {
+		return defaultLocale
+	}
+
+	if interaction.Locale != discordgo.Unknown {
+		return interaction.Locale
+	}
+
+	if interaction.GuildLocale != nil && *interaction.GuildLocale != discordgo.Unknown {
+		return *interaction.GuildLocale
+	}
+
+	return defaultLocale
+}
@@ -0,0 +1,89 @@
+package interactionsapi
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestCatalogue_CompleteLocales(t *testing.T) {
+	defaultMessages, ok := catalogue[defaultLocale]
+	if !ok {
+		t.Fatalf("catalogue[%#v] is missing", defaultLocale)
+	}
+
+	for locale, messages := range catalogue {
+		for key := range defaultMessages {
+			if msg, ok := messages[key]; !ok || msg == "" {
+				t.Errorf("catalogue[%#v][%#v] is missing or empty", locale, key)
+			}
+		}
+
+		for key := range messages {
+			if _, ok := defaultMessages[key]; !ok {
+				t.Errorf("catalogue[%#v][%#v] is not present in the default locale", locale, key)
+			}
+		}
+	}
+}
+
+func TestCatalogue_CommandKeys(t *testing.T) {
+	for _, cmd := range desiredCommands(t) {
+		keys := []messageKey{
+			commandNameKey(cmd.Name),
+			commandDescriptionKey(cmd.Name),
+		}
+		for _, option := range cmd.Options {
+			keys = append(keys,
+				commandOptionNameKey(cmd.Name, option.Name),
+				commandOptionDescriptionKey(cmd.Name, option.Name),
+			)
+
+			for _, choice := range option.Choices {
+				keys = append(keys, commandOptionChoiceNameKey(cmd.Name, option.Name, fmt.Sprint(choice.Value)))
+			}
+		}
+
+		for _, key := range keys {
+			if _, ok := catalogue[defaultLocale][key]; !ok {
+				t.Errorf("catalogue[%#v][%#v] is missing", defaultLocale, key)
+			}
+		}
+	}
+}
+
+func TestLocalize_Fallback(t *testing.T) {
+	if got, want := localize(discordgo.Japanese, msgPong), "ポン。"; got != want {
+		t.Errorf("localize(%#v, %#v) = %#v; want %#v", discordgo.Japanese, msgPong, got, want)
+	}
+
+	if got, want := localize(discordgo.EnglishGB, msgPong), "Pong."; got != want {
+		t.Errorf("localize(%#v, %#v) = %#v; want %#v", discordgo.EnglishGB, msgPong, got, want)
+	}
+
+	const missingKey messageKey = "missing-key"
+	if got, want := localize(discordgo.German, missingKey), string(missingKey); got != want {
+		t.Errorf("localize(%#v, %#v) = %#v; want %#v", discordgo.German, missingKey, got, want)
+	}
+}
+
+func TestInteractionLocale(t *testing.T) {
+	german := discordgo.German
+
+	tests := []struct {
+		interaction *discordgo.Interaction
+		want        discordgo.Locale
+	}{
+		{nil, defaultLocale},
+		{&discordgo.Interaction{}, defaultLocale},
+		{&discordgo.Interaction{Locale: discordgo.French, GuildLocale: &german}, discordgo.French},
+		{&discordgo.Interaction{GuildLocale: &german}, discordgo.German},
+	}
+
+	for _, tt := range tests {
+		if got := interactionLocale(tt.interaction); got != tt.want {
+			t.Errorf("interactionLocale(%#v) = %#v; want %#v", tt.interaction, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package interactionsapi
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	logger "github.com/c032/go-logger"
+)
+
+// InteractionMetrics receives measurements of every interaction handled by the
+// command registry.
+type InteractionMetrics interface {
+	ObserveInteraction(interactionType discordgo.InteractionType, commandName string, duration time.Duration, err error)
+}
+
+// interactionUserID returns the ID of the user that created the interaction,
+// both for interactions in guilds and in DMs.
+func interactionUserID(interaction *discordgo.Interaction) string {
+	if interaction.Member != nil && interaction.Member.User != nil {
+		return interaction.Member.User.ID
+	}
+
+	if interaction.User != nil {
+		return interaction.User.ID
+	}
+
+	return ""
+}
+
+func (s *Server) loggingMiddleware(next InteractionHandler) InteractionHandler {
+	return InteractionHandlerFunc(func(req *InteractionRequest) (*discordgo.InteractionResponse, error) {
+		s.requestLogger(req.Context).Print("Processing interaction.")
+
+		start := time.Now()
+		resp, err := next.HandleInteraction(req)
+
+		fields := logger.Fields{
+			"latency_ms": time.Since(start).Milliseconds(),
+		}
+
+		if err != nil {
+			fields["error"] = err.Error()
+
+			s.requestLoggerWithFields(req.Context, fields).Errorf("Interaction failed: %s", err.Error())
+		} else {
+			s.requestLoggerWithFields(req.Context, fields).Print("Interaction processed.")
+		}
+
+		return resp, err
+	})
+}
+
+func (s *Server) metricsMiddleware(next InteractionHandler) InteractionHandler {
+	return InteractionHandlerFunc(func(req *InteractionRequest) (*discordgo.InteractionResponse, error) {
+		if s.Metrics == nil {
+			return next.HandleInteraction(req)
+		}
+
+		start := time.Now()
+		resp, err := next.HandleInteraction(req)
+
+		s.Metrics.ObserveInteraction(req.Interaction.Type, req.CommandName, time.Since(start), err)
+
+		return resp, err
+	})
+}
+
+// permissionsMiddleware rejects commands used outside of guilds when they
+// don't allow it, and commands used by members without the permissions
+// required by the command.
+//
+// Discord already hides these commands from users that can't use them, so
+// this is only a safeguard for stale command registrations.
+func (s *Server) permissionsMiddleware(r *CommandRegistry) InteractionMiddleware {
+	return func(next InteractionHandler) InteractionHandler {
+		return InteractionHandlerFunc(func(req *InteractionRequest) (*discordgo.InteractionResponse, error) {
+			cmd := r.ApplicationCommand(req.CommandName)
+			if cmd == nil {
+				return next.HandleInteraction(req)
+			}
+
+			interaction := req.Interaction
+
+			if cmd.DMPermission != nil && !*cmd.DMPermission && interaction.GuildID == "" {
+				return errorMessageResponse(interaction.Type, localize(req.Locale, msgGuildOnly)), nil
+			}
+
+			if cmd.DefaultMemberPermissions != nil && interaction.Member != nil {
+				required := *cmd.DefaultMemberPermissions
+				granted := interaction.Member.Permissions
+
+				isAdministrator := granted&discordgo.PermissionAdministrator != 0
+				if !isAdministrator && granted&required != required {
+					return errorMessageResponse(interaction.Type, localize(req.Locale, msgMissingPermissions)), nil
+				}
+			}
+
+			return next.HandleInteraction(req)
+		})
+	}
+}
+
+// maxCooldowns is the maximum number of cooldowns remembered at once. Once
+// reached, the oldest cooldowns end early.
+const maxCooldowns = 10000
+
+// cooldownMiddleware rejects commands used again by the same user before
+// `CommandCooldown` has passed.
+//
+// Only the execution of commands is limited. Autocompletion and component
+// interactions are not.
+func (s *Server) cooldownMiddleware(next InteractionHandler) InteractionHandler {
+	return InteractionHandlerFunc(func(req *InteractionRequest) (*discordgo.InteractionResponse, error) {
+		cooldown := s.settings().CommandCooldown
+		if cooldown <= 0 || req.Interaction.Type != discordgo.InteractionApplicationCommand {
+			return next.HandleInteraction(req)
+		}
+
+		userID := interactionUserID(req.Interaction)
+		if userID == "" {
+			return next.HandleInteraction(req)
+		}
+
+		remaining := s.takeCooldown(userID+"/"+req.CommandName, time.Now(), cooldown)
+		if remaining > 0 {
+			content := fmt.Sprintf(localize(req.Locale, msgCooldown), remaining.Round(time.Second).String())
+
+			return messageResponse(content, discordgo.MessageFlagsEphemeral), nil
+		}
+
+		return next.HandleInteraction(req)
+	})
+}
+
+// takeCooldown starts a cooldown of `cooldown` for `key`, unless one is
+// already running. It returns how much time is left of the running cooldown,
+// or zero if a new one was started.
+func (s *Server) takeCooldown(key string, now time.Time, cooldown time.Duration) time.Duration {
+	_, remaining := s.cooldowns.add(key, now, cooldown)
+
+	return remaining
+}
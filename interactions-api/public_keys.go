@@ -0,0 +1,229 @@
+package interactionsapi
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	logger "github.com/c032/go-logger"
+)
+
+// PublicKeyRing is a set of public keys that can be replaced at runtime.
+//
+// A signature is valid if it's valid for any of the keys, so a new key can be
+// added before the old one is removed.
+type PublicKeyRing struct {
+	mu   sync.RWMutex
+	keys []ed25519.PublicKey
+}
+
+func NewPublicKeyRing(keys ...ed25519.PublicKey) *PublicKeyRing {
+	ring := &PublicKeyRing{}
+	ring.Replace(keys)
+
+	return ring
+}
+
+// Keys returns a copy of the current keys.
+func (ring *PublicKeyRing) Keys() []ed25519.PublicKey {
+	ring.mu.RLock()
+	defer ring.mu.RUnlock()
+
+	return append([]ed25519.PublicKey(nil), ring.keys...)
+}
+
+// Replace replaces all the keys of the ring.
+func (ring *PublicKeyRing) Replace(keys []ed25519.PublicKey) {
+	keys = append([]ed25519.PublicKey(nil), keys...)
+
+	ring.mu.Lock()
+	defer ring.mu.Unlock()
+
+	ring.keys = keys
+}
+
+// Verify returns whether `signature` is a valid signature of `msg` for any of
+// the keys of the ring.
+func (ring *PublicKeyRing) Verify(msg []byte, signature []byte) bool {
+	if ring == nil {
+		return false
+	}
+
+	ring.mu.RLock()
+	defer ring.mu.RUnlock()
+
+	for _, key := range ring.keys {
+		if ed25519.Verify(key, msg, signature) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ParsePublicKeys parses hex-encoded public keys, one per line.
+//
+// Empty lines, and lines starting with `#`, are ignored.
+func ParsePublicKeys(data []byte) ([]ed25519.PublicKey, error) {
+	var keys []ed25519.PublicKey
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		rawKey, err := hex.DecodeString(line)
+		if err != nil {
+			return nil, fmt.Errorf("could not decode public key in line %d: %w", lineNumber, err)
+		}
+
+		if len(rawKey) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("public key in line %d has %d bytes; expected %d", lineNumber, len(rawKey), ed25519.PublicKeySize)
+		}
+
+		keys = append(keys, ed25519.PublicKey(rawKey))
+	}
+
+	err := scanner.Err()
+	if err != nil {
+		return nil, fmt.Errorf("could not read public keys: %w", err)
+	}
+
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no public keys found")
+	}
+
+	return keys, nil
+}
+
+// ReadPublicKeysFile reads the public keys in the file at `path`, in the
+// format accepted by `ParsePublicKeys`.
+func ReadPublicKeysFile(path string) ([]ed25519.PublicKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read public keys file: %w", err)
+	}
+
+	keys, err := ParsePublicKeys(data)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse public keys file: %w", err)
+	}
+
+	return keys, nil
+}
+
+// PublicKeyFileWatcher keeps the keys of a `PublicKeyRing` in sync with a
+// file.
+type PublicKeyFileWatcher struct {
+	Logger logger.Logger
+
+	Path string
+	Ring *PublicKeyRing
+
+	// Interval is how often the file is checked for changes. Defaults to
+	// `DefaultPublicKeyFileWatchInterval`.
+	Interval time.Duration
+
+	mu       sync.Mutex
+	lastHash [sha256.Size]byte
+}
+
+const DefaultPublicKeyFileWatchInterval = 30 * time.Second
+
+func (w *PublicKeyFileWatcher) logger() logger.Logger {
+	if w.Logger == nil {
+		return logger.Discard
+	}
+
+	return w.Logger
+}
+
+// Reload reads the file and replaces the keys of the ring.
+//
+// If the file can't be read or doesn't contain valid keys, the ring keeps
+// its current keys.
+func (w *PublicKeyFileWatcher) Reload() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	_, err := w.reload(true)
+
+	return err
+}
+
+// reload replaces the keys of the ring if the contents of the file changed
+// since the last reload, or if `force` is `true`. It returns whether the keys
+// were replaced.
+//
+// The caller must hold `w.mu`.
+func (w *PublicKeyFileWatcher) reload(force bool) (bool, error) {
+	data, err := os.ReadFile(w.Path)
+	if err != nil {
+		return false, fmt.Errorf("could not read public keys file: %w", err)
+	}
+
+	hash := sha256.Sum256(data)
+	if !force && hash == w.lastHash {
+		return false, nil
+	}
+
+	keys, err := ParsePublicKeys(data)
+	if err != nil {
+		return false, fmt.Errorf("could not parse public keys file: %w", err)
+	}
+
+	w.Ring.Replace(keys)
+	w.lastHash = hash
+
+	w.logger().WithFields(logger.Fields{
+		"path":      w.Path,
+		"key_count": len(keys),
+	}).Print("Public keys reloaded.")
+
+	return true, nil
+}
+
+// Run checks the file for changes every `Interval`, until `ctx` is done.
+//
+// The first check always reloads the keys, since there is no previous state
+// to compare with.
+func (w *PublicKeyFileWatcher) Run(ctx context.Context) {
+	log := w.logger()
+
+	interval := w.Interval
+	if interval <= 0 {
+		interval = DefaultPublicKeyFileWatchInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		w.mu.Lock()
+		_, err := w.reload(false)
+		w.mu.Unlock()
+
+		if err != nil {
+			log.Errorf("Could not reload public keys: %s", err.Error())
+		}
+	}
+}
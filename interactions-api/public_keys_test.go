@@ -0,0 +1,102 @@
+package interactionsapi
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestParsePublicKeys(t *testing.T) {
+	a, _ := newTestKey(t)
+	b, _ := newTestKey(t)
+
+	data := "# Current key.\n" + hex.EncodeToString(a) + "\n\n  " + hex.EncodeToString(b) + "  \n"
+
+	keys, err := ParsePublicKeys([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := len(keys), 2; got != want {
+		t.Fatalf("len(keys) = %d; want %d", got, want)
+	}
+	if !keys[0].Equal(a) || !keys[1].Equal(b) {
+		t.Errorf("keys = %x; want [%x %x]", keys, a, b)
+	}
+
+	for _, invalid := range []string{"", "# Only a comment.\n", "not hex\n", "abcd\n"} {
+		_, err = ParsePublicKeys([]byte(invalid))
+		if err == nil {
+			t.Errorf("ParsePublicKeys(%#v) returned no error", invalid)
+		}
+	}
+}
+
+func TestPublicKeyFileWatcher(t *testing.T) {
+	oldPublicKey, oldPrivateKey := newTestKey(t)
+	newPublicKey, newPrivateKey := newTestKey(t)
+
+	path := filepath.Join(t.TempDir(), "public-keys.txt")
+
+	writeKeys := func(keys ...ed25519.PublicKey) {
+		var data string
+		for _, key := range keys {
+			data += hex.EncodeToString(key) + "\n"
+		}
+
+		err := os.WriteFile(path, []byte(data), 0o600)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	writeKeys(oldPublicKey)
+
+	ring := NewPublicKeyRing(oldPublicKey)
+	w := &PublicKeyFileWatcher{
+		Path:     path,
+		Ring:     ring,
+		Interval: 10 * time.Millisecond,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go w.Run(ctx)
+
+	msg := []byte("message")
+	newSignature := ed25519.Sign(newPrivateKey, msg)
+	oldSignature := ed25519.Sign(oldPrivateKey, msg)
+
+	writeKeys(newPublicKey)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !ring.Verify(msg, newSignature) {
+		if time.Now().After(deadline) {
+			t.Fatal("ring was not reloaded after the file changed")
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if ring.Verify(msg, oldSignature) {
+		t.Errorf("ring.Verify() accepted a removed key")
+	}
+
+	err := os.WriteFile(path, []byte("invalid\n"), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = w.Reload()
+	if err == nil {
+		t.Errorf("w.Reload() with an invalid file returned no error")
+	}
+	if !ring.Verify(msg, newSignature) {
+		t.Errorf("ring lost its keys after an invalid reload")
+	}
+}
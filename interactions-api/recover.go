@@ -0,0 +1,97 @@
+package interactionsapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"runtime/debug"
+	"sync"
+
+	"github.com/bwmarrin/discordgo"
+	logger "github.com/c032/go-logger"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+type currentInteractionContextKey struct{}
+
+// currentInteraction holds the interaction being handled by a request, once
+// it's decoded, so it can be reported if the handler panics.
+type currentInteraction struct {
+	mu          sync.Mutex
+	interaction *discordgo.Interaction
+}
+
+func (ci *currentInteraction) get() *discordgo.Interaction {
+	ci.mu.Lock()
+	defer ci.mu.Unlock()
+
+	return ci.interaction
+}
+
+func (ci *currentInteraction) set(interaction *discordgo.Interaction) {
+	ci.mu.Lock()
+	defer ci.mu.Unlock()
+
+	ci.interaction = interaction
+}
+
+// setCurrentInteraction records the interaction being handled by the request
+// with context `ctx`.
+func setCurrentInteraction(ctx context.Context, interaction *discordgo.Interaction) {
+	ci, ok := ctx.Value(currentInteractionContextKey{}).(*currentInteraction)
+	if !ok {
+		return
+	}
+
+	ci.set(interaction)
+}
+
+// recoverMiddleware recovers from panics in the next handlers.
+//
+// The panic is logged with its stack trace. If the interaction was already
+// decoded, the user receives an error message as a regular interaction
+// response, so Discord doesn't show the interaction as failed. Panics while
+// autocompleting are answered with no choices.
+func (s *Server) recoverMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ci := &currentInteraction{}
+		ctx := context.WithValue(req.Context(), currentInteractionContextKey{}, ci)
+
+		ww := middleware.NewWrapResponseWriter(w, req.ProtoMajor)
+
+		defer func() {
+			rvr := recover()
+			if rvr == nil {
+				return
+			}
+
+			if rvr == http.ErrAbortHandler {
+				panic(rvr)
+			}
+
+			interaction := ci.get()
+
+			s.requestLoggerWithFields(ctx, logger.Fields{
+				"panic": fmt.Sprint(rvr),
+				"stack": string(debug.Stack()),
+			}).Errorf("Recovered from panic: %v", rvr)
+
+			if ww.Status() != 0 {
+				// Response was already (at least partially) written.
+				return
+			}
+
+			if interaction == nil || interaction.Type == discordgo.InteractionPing {
+				s.respondError(ww, newErrorResponse(ErrTypeInternalServerError, ""))
+
+				return
+			}
+
+			locale := interactionLocale(interaction)
+
+			s.respondInteraction(ww, errorMessageResponse(interaction.Type, localize(locale, msgInternalError)))
+		}()
+
+		next.ServeHTTP(ww, req.WithContext(ctx))
+	})
+}
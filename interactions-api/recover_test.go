@@ -0,0 +1,83 @@
+package interactionsapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestServer_RecoverMiddleware(t *testing.T) {
+	s := &Server{}
+
+	h := s.recoverMiddleware(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		setCurrentInteraction(req.Context(), &discordgo.Interaction{
+			ID:     "1",
+			Type:   discordgo.InteractionApplicationCommand,
+			Locale: discordgo.German,
+		})
+
+		panic("test")
+	}))
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/interactions", nil))
+
+	if got, want := w.Code, http.StatusOK; got != want {
+		t.Fatalf("w.Code = %d; want %d", got, want)
+	}
+
+	var resp discordgo.InteractionResponse
+	err := json.Unmarshal(w.Body.Bytes(), &resp)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := resp.Data.Content, localize(discordgo.German, msgInternalError); got != want {
+		t.Errorf("resp.Data.Content = %#v; want %#v", got, want)
+	}
+	if resp.Data.Flags&discordgo.MessageFlagsEphemeral == 0 {
+		t.Errorf("resp.Data.Flags = %d; want ephemeral flag", resp.Data.Flags)
+	}
+}
+
+func TestServer_RecoverMiddleware_Autocomplete(t *testing.T) {
+	s := &Server{}
+
+	h := s.recoverMiddleware(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		setCurrentInteraction(req.Context(), &discordgo.Interaction{
+			ID:   "1",
+			Type: discordgo.InteractionApplicationCommandAutocomplete,
+		})
+
+		panic("test")
+	}))
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/interactions", nil))
+
+	if got, want := strings.TrimSpace(w.Body.String()), `{"type":8,"data":{"choices":[]}}`; got != want {
+		t.Errorf("body = %s; want %s", got, want)
+	}
+}
+
+func TestServer_RecoverMiddleware_BeforeDecoding(t *testing.T) {
+	s := &Server{}
+
+	h := s.recoverMiddleware(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		panic("test")
+	}))
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/interactions", nil))
+
+	if got, want := w.Code, http.StatusInternalServerError; got != want {
+		t.Fatalf("w.Code = %d; want %d", got, want)
+	}
+	if got, want := w.Header().Get("Content-Type"), "application/problem+json"; got != want {
+		t.Errorf("Content-Type = %#v; want %#v", got, want)
+	}
+}
@@ -0,0 +1,208 @@
+package interactionsapi
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"maps"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	logger "github.com/c032/go-logger"
+	"github.com/go-chi/chi/v5/middleware"
+
+	"github.com/c032/ffxiv-world-status-discord/tracing"
+)
+
+type requestLogContextKey struct{}
+
+// requestLog holds the fields that identify a request in every log line
+// emitted while handling it. Fields are added as they become known, e.g. once
+// the interaction is decoded.
+type requestLog struct {
+	mu     sync.Mutex
+	fields logger.Fields
+}
+
+func (rl *requestLog) add(fields logger.Fields) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	maps.Copy(rl.fields, fields)
+}
+
+func (rl *requestLog) snapshot() logger.Fields {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	return maps.Clone(rl.fields)
+}
+
+// addRequestLogFields adds `fields` to every line logged with
+// `requestLogger` for the request with context `ctx`.
+func addRequestLogFields(ctx context.Context, fields logger.Fields) {
+	rl, ok := ctx.Value(requestLogContextKey{}).(*requestLog)
+	if !ok {
+		return
+	}
+
+	rl.add(fields)
+}
+
+// requestLogger returns a logger with the fields of the request with context
+// `ctx`.
+func (s *Server) requestLogger(ctx context.Context) logger.Logger {
+	return s.requestLoggerWithFields(ctx, nil)
+}
+
+// requestLoggerWithFields returns a logger with the fields of the request with
+// context `ctx`, plus `extra`.
+//
+// Loggers returned by this function must not be extended with `WithFields`,
+// because some implementations replace the existing fields instead of adding
+// to them.
+func (s *Server) requestLoggerWithFields(ctx context.Context, extra logger.Fields) logger.Logger {
+	log := s.logger()
+
+	fields := logger.Fields{}
+	if rl, ok := ctx.Value(requestLogContextKey{}).(*requestLog); ok {
+		fields = rl.snapshot()
+	}
+
+	maps.Copy(fields, extra)
+
+	if len(fields) == 0 {
+		return log
+	}
+
+	return log.WithFields(fields)
+}
+
+func (s *Server) logHashKey() []byte {
+	s.logHashKeyOnce.Do(func() {
+		if len(s.LogHashKey) > 0 {
+			s.logHashKeyValue = s.LogHashKey
+
+			return
+		}
+
+		key := make([]byte, 32)
+		_, _ = rand.Read(key)
+
+		s.logHashKeyValue = key
+	})
+
+	return s.logHashKeyValue
+}
+
+// hashForLog returns a pseudonym of `value` that can be logged instead of it.
+func (s *Server) hashForLog(value string) string {
+	mac := hmac.New(sha256.New, s.logHashKey())
+	mac.Write([]byte(value))
+
+	return hex.EncodeToString(mac.Sum(nil))[:16]
+}
+
+// interactionCommandName returns the name of the command that `interaction`
+// belongs to, or an empty string if it doesn't belong to any.
+func interactionCommandName(interaction *discordgo.Interaction) string {
+	switch interaction.Type {
+	case discordgo.InteractionApplicationCommand, discordgo.InteractionApplicationCommandAutocomplete:
+		return interaction.ApplicationCommandData().Name
+	case discordgo.InteractionMessageComponent:
+		commandName, _ := parseComponentCustomID(interaction.MessageComponentData().CustomID)
+
+		return commandName
+	}
+
+	return ""
+}
+
+// interactionLogFields returns the fields that identify `interaction` in logs.
+//
+// Users are identified by a hash of their ID, unless `LogPersonalData` is
+// set.
+func (s *Server) interactionLogFields(interaction *discordgo.Interaction) logger.Fields {
+	fields := logger.Fields{
+		"interaction_id":   interaction.ID,
+		"interaction_type": interaction.Type.String(),
+	}
+
+	if interaction.GuildID != "" {
+		fields["guild_id"] = interaction.GuildID
+	}
+
+	if commandName := interactionCommandName(interaction); commandName != "" {
+		fields["command"] = commandName
+	}
+
+	if userID := interactionUserID(interaction); userID != "" {
+		fields["user_hash"] = s.hashForLog(userID)
+
+		if s.settings().LogPersonalData {
+			fields["user_id"] = userID
+		}
+	}
+
+	return fields
+}
+
+// requestLogMiddleware makes the fields of the request available to
+// `requestLogger`, and logs a single line when the request finishes.
+func (s *Server) requestLogMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		rl := &requestLog{
+			fields: logger.Fields{
+				"method": req.Method,
+				"path":   req.URL.Path,
+			},
+		}
+		if requestID := middleware.GetReqID(req.Context()); requestID != "" {
+			rl.fields["request_id"] = requestID
+		}
+
+		ctx := context.WithValue(req.Context(), requestLogContextKey{}, rl)
+
+		ww := middleware.NewWrapResponseWriter(w, req.ProtoMajor)
+
+		start := time.Now()
+		next.ServeHTTP(ww, req.WithContext(ctx))
+
+		s.requestLoggerWithFields(ctx, logger.Fields{
+			"status":     ww.Status(),
+			"bytes":      ww.BytesWritten(),
+			"latency_ms": time.Since(start).Milliseconds(),
+		}).Print("Request handled.")
+	})
+}
+
+// tracingMiddleware records a span for every request, and adds its trace ID
+// to the logs of the request.
+func (s *Server) tracingMiddleware(next http.Handler) http.Handler {
+	if s.Tracer == nil {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ctx, span := s.Tracer.Start(req.Context(), "HTTP "+req.Method,
+			tracing.String("http.method", req.Method),
+			tracing.String("http.target", req.URL.Path),
+		)
+		span.SetKind(tracing.SpanKindServer)
+		defer span.End()
+
+		addRequestLogFields(ctx, logger.Fields{
+			"trace_id": span.TraceID().String(),
+		})
+
+		ww := middleware.NewWrapResponseWriter(w, req.ProtoMajor)
+
+		next.ServeHTTP(ww, req.WithContext(ctx))
+
+		span.SetAttributes(tracing.Int("http.status_code", ww.Status()))
+	})
+}
@@ -0,0 +1,207 @@
+package interactionsapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	logger "github.com/c032/go-logger"
+
+	"github.com/c032/ffxiv-world-status-discord/ffxivapi"
+	"github.com/c032/ffxiv-world-status-discord/tracing"
+)
+
+// syncBuffer is a `bytes.Buffer` that can be written concurrently.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (sb *syncBuffer) Write(p []byte) (int, error) {
+	sb.mu.Lock()
+	defer sb.mu.Unlock()
+
+	return sb.buf.Write(p)
+}
+
+func (sb *syncBuffer) String() string {
+	sb.mu.Lock()
+	defer sb.mu.Unlock()
+
+	return sb.buf.String()
+}
+
+func TestServer_RequestLogger(t *testing.T) {
+	var logs syncBuffer
+
+	s := &Server{
+		Logger: logger.FromSlog(slog.New(slog.NewJSONHandler(&logs, nil))),
+		Applications: []*Application{
+			{Name: "default", SkipRequestValidation: true},
+		},
+	}
+
+	var err error
+
+	app := s.Applications[0]
+	app.commands, err = s.newCommandRegistry(app)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = s.initializeRouter()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const (
+		interactionID = "1001"
+		guildID       = "2002"
+		userID        = "3003"
+	)
+
+	body := `{"id":"` + interactionID + `","type":2,"guild_id":"` + guildID + `","member":{"user":{"id":"` + userID + `"}},"data":{"name":"` + CmdPing + `"}}`
+	req := httptest.NewRequest(http.MethodPost, "/interactions", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, req)
+
+	if got, want := w.Code, http.StatusOK; got != want {
+		t.Fatalf("w.Code = %d; want %d", got, want)
+	}
+
+	lines := strings.Split(strings.TrimSpace(logs.String()), "\n")
+
+	var interactionLines int
+	for _, line := range lines {
+		if strings.Contains(line, userID) {
+			t.Errorf("log line contains user ID: %s", line)
+		}
+
+		var entry map[string]any
+		err := json.Unmarshal([]byte(line), &entry)
+		if err != nil {
+			t.Fatalf("could not decode log line %#v: %s", line, err)
+		}
+
+		if entry["interaction_id"] == nil {
+			continue
+		}
+
+		interactionLines++
+
+		if got, want := entry["interaction_id"], interactionID; got != want {
+			t.Errorf("interaction_id = %#v; want %#v", got, want)
+		}
+		if got, want := entry["guild_id"], guildID; got != want {
+			t.Errorf("guild_id = %#v; want %#v", got, want)
+		}
+		if got, want := entry["command"], CmdPing; got != want {
+			t.Errorf("command = %#v; want %#v", got, want)
+		}
+		if got, want := entry["user_hash"], s.hashForLog(userID); got != want {
+			t.Errorf("user_hash = %#v; want %#v", got, want)
+		}
+	}
+
+	// "Processing interaction.", "Interaction processed." and "Request
+	// handled.".
+	if got, want := interactionLines, 3; got != want {
+		t.Errorf("lines with interaction_id = %d; want %d\n%s", got, want, logs.String())
+	}
+}
+
+type staticAPIClient struct {
+	worlds *ffxivapi.WorldsResponse
+}
+
+func (c staticAPIClient) Worlds(ctx context.Context) (*ffxivapi.WorldsResponse, error) {
+	return c.worlds, nil
+}
+
+func TestServer_Tracing(t *testing.T) {
+	exporter := &tracing.InMemoryExporter{}
+	tracer := tracing.NewTracer(exporter, tracing.TracerOptions{})
+
+	s := &Server{
+		API: staticAPIClient{
+			worlds: &ffxivapi.WorldsResponse{
+				Worlds: []ffxivapi.World{
+					{Group: "Light", Name: "Lich", IsMaintenance: true},
+				},
+			},
+		},
+		GuildSettings: NewMemoryGuildSettingsStore(),
+		Tracer:        tracer,
+		Applications: []*Application{
+			{Name: "default", SkipRequestValidation: true},
+		},
+	}
+
+	var err error
+
+	app := s.Applications[0]
+	app.commands, err = s.newCommandRegistry(app)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = s.initializeRouter()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	body := `{"id":"1","type":2,"data":{"name":"` + CmdCharacters + `"}}`
+	req := httptest.NewRequest(http.MethodPost, "/interactions", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, req)
+
+	if got, want := w.Code, http.StatusOK; got != want {
+		t.Fatalf("w.Code = %d; want %d", got, want)
+	}
+
+	err = tracer.Flush(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	spansByName := map[string]tracing.SpanData{}
+	for _, span := range exporter.Spans() {
+		spansByName[span.Name] = span
+	}
+
+	root, ok := spansByName["HTTP POST"]
+	if !ok {
+		t.Fatalf("missing root span; got %#v", spansByName)
+	}
+
+	parents := map[string]string{
+		"validate request":     "HTTP POST",
+		"dispatch interaction": "HTTP POST",
+		"render embeds":        "dispatch interaction",
+	}
+	for name, parentName := range parents {
+		span, ok := spansByName[name]
+		if !ok {
+			t.Errorf("missing span %#v", name)
+
+			continue
+		}
+
+		if got, want := span.TraceID, root.TraceID; got != want {
+			t.Errorf("trace ID of %#v = %s; want %s", name, got, want)
+		}
+		if got, want := span.ParentSpanID, spansByName[parentName].SpanID; got != want {
+			t.Errorf("parent of %#v = %s; want %#v (%s)", name, got, parentName, want)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package interactionsapi
 import (
 	"net/http"
 
+	logger "github.com/c032/go-logger"
 	chi "github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 )
@@ -10,24 +11,55 @@ import (
 func createRouter(s *Server) (*chi.Mux, error) {
 	r := chi.NewRouter()
 
-	r.Use(middleware.RequestLogger(&middleware.DefaultLogFormatter{
-		Logger:  s.Logger,
-		NoColor: true,
-	}))
+	r.Use(middleware.RequestID)
+	r.Use(s.requestLogMiddleware)
+	r.Use(s.tracingMiddleware)
 
-	r.Use(func(next http.Handler) http.Handler {
-		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
-			if !s.isAuthorizedRequest(req) {
-				http.Error(w, "", http.StatusUnauthorized)
+	r.Use(s.recoverMiddleware)
 
-				return
-			}
+	// Probes are not signed by Discord, so they don't go through
+	// `validationMiddleware`.
+	r.Get("/healthz", s.handleHealthz)
+	r.Get("/readyz", s.handleReadyz)
+	r.Get("/version", s.handleVersion)
 
-			next.ServeHTTP(w, req)
+	r.With(s.inFlightMiddleware, s.defaultApplicationMiddleware, s.validationMiddleware).Post("/interactions", s.handleInteractionRequest)
+	r.With(s.inFlightMiddleware, s.applicationMiddleware, s.validationMiddleware).Post("/interactions/{application}", s.handleInteractionRequest)
+
+	return r, nil
+}
+
+// defaultApplicationMiddleware makes the request be handled by the first
+// application of the server.
+func (s *Server) defaultApplicationMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		app := s.Applications[0]
+
+		addRequestLogFields(req.Context(), logger.Fields{
+			"application": app.Name,
 		})
+
+		next.ServeHTTP(w, req.WithContext(withApplication(req.Context(), app)))
 	})
+}
 
-	r.Post("/interactions", s.handleInteractionRequest)
+// applicationMiddleware makes the request be handled by the application
+// named in the route.
+func (s *Server) applicationMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		name := chi.URLParam(req, "application")
 
-	return r, nil
+		app := s.application(name)
+		if app == nil {
+			s.respondError(w, newErrorResponse(ErrTypeUnknownApplication, name))
+
+			return
+		}
+
+		addRequestLogFields(req.Context(), logger.Fields{
+			"application": app.Name,
+		})
+
+		next.ServeHTTP(w, req.WithContext(withApplication(req.Context(), app)))
+	})
 }
@@ -1,36 +1,113 @@
 package interactionsapi
 
 import (
-	"crypto/ed25519"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"sync"
+	"time"
 
 	"github.com/bwmarrin/discordgo"
 	logger "github.com/c032/go-logger"
 	chi "github.com/go-chi/chi/v5"
 
 	"github.com/c032/ffxiv-world-status-discord/ffxivapi"
+	"github.com/c032/ffxiv-world-status-discord/tracing"
 )
 
 type Server struct {
 	loggerMutex sync.Mutex
 	Logger      logger.Logger
 
+	// API is shared by every application. It should be cached, e.g. with
+	// `ffxivapi.CachedClient`.
 	API ffxivapi.Client
 
+	// Applications contains every Discord application hosted by the server.
+	Applications []*Application
+
+	// Metrics receives measurements of the handled interactions. It's
+	// optional.
+	Metrics InteractionMetrics
+
+	// Tracer records spans of the handling of every request. It's optional.
+	Tracer *tracing.Tracer
+
+	// settingsMutex protects the fields that can be changed with `Reload`
+	// while the server is running.
+	settingsMutex sync.RWMutex
+
+	// CommandCooldown is the minimum time between two uses of the same
+	// command by the same user. Zero disables it.
+	CommandCooldown time.Duration
+
+	// GuildSettings stores the preferences of every guild. If it's `nil`,
+	// settings are kept in memory and lost on restart.
+	GuildSettings GuildSettingsStore
+
+	DiscordThumbnailURL string
+
+	// Templates renders the parts of responses that can be customized.
+	// Defaults to the default templates.
+	Templates *ResponseTemplates
+
+	// StatusIcons are shown next to the names of worlds. Empty icons are
+	// replaced by the ones in `DefaultStatusIcons`.
+	StatusIcons StatusIcons
+
+	// Features turns off optional features of the commands.
+	Features Features
+
+	// MaxRequestBodySize is the maximum size, in bytes, of the body of
+	// requests. Defaults to `DefaultMaxRequestBodySize`.
+	MaxRequestBodySize int64
+
+	// MaxTimestampSkew is the maximum difference between the signature
+	// timestamp of requests and the server time. Defaults to
+	// `DefaultMaxTimestampSkew`.
+	MaxTimestampSkew time.Duration
+
+	// ReplayWindow is how long interaction IDs are remembered to reject
+	// replayed requests. Defaults to `DefaultReplayWindow`.
+	ReplayWindow time.Duration
+
+	// LogPersonalData makes logs include personal data, e.g. user IDs. By
+	// default, users are only identified by a keyed hash of their ID.
+	LogPersonalData bool
+
+	// LogHashKey is the key used to hash personal data in logs. If it's
+	// empty, a random key is used, so hashes can only be correlated within
+	// the same process.
+	LogHashKey []byte
+
+	// DiscordAPIURL is the base URL of the Discord REST API, e.g. to use a
+	// local fake. Defaults to `discordgo.EndpointAPI`.
+	DiscordAPIURL string
+
+	// CommandSyncDryRun makes `RunCommandSync` only log the differences
+	// between the registered commands and the desired ones, without
+	// changing them.
+	CommandSyncDryRun bool
+
+	// SkipCommandSync makes `RunCommandSync` not synchronize commands at
+	// all, e.g. because they are managed separately with `SyncCommands`.
+	// The server is ready without waiting for them.
+	SkipCommandSync bool
+
 	chiRouter *chi.Mux
 
-	DiscordApplicationID string
-	DiscordPublicKey     ed25519.PublicKey
-	DiscordThumbnailURL  string
-	DiscordToken         string
+	cooldowns        *ttlSet
+	seenInteractions *ttlSet
+
+	commandSync commandSyncStatus
+
+	inFlight inFlightGroup
 
-	SkipDiscordRequestValidation bool
+	logHashKeyOnce  sync.Once
+	logHashKeyValue []byte
 
-	discordSession            *discordgo.Session
-	discordRegisteredCommands []*discordgo.ApplicationCommand
+	// clock replaces `time.Now` in tests.
+	clock func() time.Time
 }
 
 func (s *Server) logger() logger.Logger {
@@ -45,174 +122,151 @@ func (s *Server) logger() logger.Logger {
 	return l
 }
 
-func (s *Server) ServeHTTP(w http.ResponseWriter, req *http.Request) {
-	s.chiRouter.ServeHTTP(w, req)
-}
+// Features turns off optional features of the commands. The options of the
+// commands are still registered, but they are ignored.
+type Features struct {
+	// DisableImages ignores the `image` option, so responses don't include
+	// the status board.
+	DisableImages bool
 
-func (s *Server) Initialize() error {
-	log := s.logger()
-	log.Print("Initializing server.")
+	// DisableANSI makes world lists use `FormatEmbed`, even if
+	// `FormatANSI` is requested.
+	DisableANSI bool
+}
 
-	var err error
+// ReloadableSettings are the settings of the server that can be changed while
+// it's running.
+type ReloadableSettings struct {
+	DiscordThumbnailURL string
+	StatusIcons         StatusIcons
+	CommandCooldown     time.Duration
+	LogPersonalData     bool
+	Features            Features
+}
 
-	err = s.initializeRouter()
-	if err != nil {
-		return fmt.Errorf("could not initialize router: %w", err)
-	}
+// Reload replaces the settings of the server that can be changed while it's
+// running.
+func (s *Server) Reload(settings ReloadableSettings) {
+	s.settingsMutex.Lock()
+	defer s.settingsMutex.Unlock()
+
+	s.DiscordThumbnailURL = settings.DiscordThumbnailURL
+	s.StatusIcons = settings.StatusIcons
+	s.CommandCooldown = settings.CommandCooldown
+	s.LogPersonalData = settings.LogPersonalData
+	s.Features = settings.Features
+}
 
-	err = s.initializeDiscordSession()
-	if err != nil {
-		return fmt.Errorf("could not initialize Discord session: %w", err)
-	}
+func (s *Server) settings() ReloadableSettings {
+	s.settingsMutex.RLock()
+	defer s.settingsMutex.RUnlock()
 
-	err = s.initializeCommands()
-	if err != nil {
-		return fmt.Errorf("could not initialize Discord commands: %w", err)
+	return ReloadableSettings{
+		DiscordThumbnailURL: s.DiscordThumbnailURL,
+		StatusIcons:         s.StatusIcons,
+		CommandCooldown:     s.CommandCooldown,
+		LogPersonalData:     s.LogPersonalData,
+		Features:            s.Features,
 	}
+}
 
-	log.Print("Server initializations finished.")
-
-	return nil
+func (s *Server) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	s.chiRouter.ServeHTTP(w, req)
 }
 
-func (s *Server) Cleanup() error {
+func (s *Server) Initialize() error {
 	log := s.logger()
-	log.Print("Cleaning up.")
+	log.Print("Initializing server.")
 
 	var err error
 
-	err = s.cleanupCommands()
-	if err != nil {
-		return fmt.Errorf("could not cleanup Discord commands: %w", err)
+	if s.GuildSettings == nil {
+		s.GuildSettings = NewMemoryGuildSettingsStore()
 	}
 
-	err = s.cleanupDiscordSession()
+	err = s.checkReplayWindow()
 	if err != nil {
-		return fmt.Errorf("could not cleanup Discord session: %w", err)
+		return err
 	}
 
-	log.Print("Server cleanup finished.")
-
-	return nil
-}
-
-func (s *Server) initializeRouter() error {
-	log := s.logger()
-	log.Print("Initializing router.")
+	if s.Templates == nil {
+		s.Templates, err = NewResponseTemplates(TemplateOverrides{})
+		if err != nil {
+			return fmt.Errorf("could not parse default templates: %w", err)
+		}
+	}
 
-	r, err := createRouter(s)
+	err = s.initializeApplications(true)
 	if err != nil {
-		return fmt.Errorf("could not create router: %w", err)
+		return fmt.Errorf("could not initialize applications: %w", err)
 	}
 
-	s.chiRouter = r
-
-	return nil
-}
-
-func (s *Server) initializeDiscordSession() error {
-	log := s.logger()
-	log.Print("Initializing Discord session.")
-
-	ds, err := discordgo.New("Bot " + s.DiscordToken)
+	err = s.initializeRouter()
 	if err != nil {
-		return fmt.Errorf("could not initialize Discord session: %w", err)
+		return fmt.Errorf("could not initialize router: %w", err)
 	}
 
-	s.discordSession = ds
-
-	return nil
-}
+	// Commands are synchronized by `RunCommandSync` once the server is
+	// listening, so `/readyz` reports it.
+	if s.SkipCommandSync {
+		log.Print("Not synchronizing commands.")
 
-func (s *Server) cleanupDiscordSession() error {
-	log := s.logger()
-	log.Print("Closing Discord session.")
-
-	err := s.discordSession.Close()
-	if err != nil {
-		return fmt.Errorf("could not close Discord session: %w", err)
+		s.setCommandSyncStatus(false, nil)
 	}
 
-	log.Print("Discord session closed successfully.")
-
-	s.discordSession = nil
+	log.Print("Server initializations finished.")
 
 	return nil
 }
 
-func (s *Server) initializeCommands() error {
-	log := s.logger()
-	log.Print("Initializing commands.")
-
-	ds := s.discordSession
-
-	registeredCommands := make([]*discordgo.ApplicationCommand, 0, len(Commands))
-	for _, discordApplicationCommand := range Commands {
-		if discordApplicationCommand == nil {
-			continue
-		}
-
-		log.WithFields(logger.Fields{
-			"command_name": discordApplicationCommand.Name,
-		}).Print("Creating command.")
-
-		const guildID = ""
-
-		registeredCommand, err := ds.ApplicationCommandCreate(s.DiscordApplicationID, guildID, discordApplicationCommand)
-		if err != nil {
-			log.WithFields(logger.Fields{
-				"error":        err.Error(),
-				"command_name": discordApplicationCommand.Name,
-			}).Errorf("Could not create command: %s", err.Error())
-
-			continue
-		}
-
-		log.WithFields(logger.Fields{
-			"command_id":   registeredCommand.ID,
-			"command_name": registeredCommand.Name,
-		}).Print("Command created.")
-
-		registeredCommands = append(registeredCommands, registeredCommand)
+// InitializeCommands prepares the server to manage the commands of its
+// applications with `SyncCommands`, `ListCommands` and `DeleteCommands`,
+// without serving interactions. Public keys are not required.
+//
+// `Cleanup` must be called afterwards.
+func (s *Server) InitializeCommands() error {
+	err := s.initializeApplications(false)
+	if err != nil {
+		return fmt.Errorf("could not initialize applications: %w", err)
 	}
 
-	s.discordRegisteredCommands = registeredCommands
-
 	return nil
 }
 
-func (s *Server) cleanupCommands() error {
+func (s *Server) Cleanup() error {
 	log := s.logger()
-	log.Print("Cleaning up commands.")
-
-	ds := s.discordSession
+	log.Print("Cleaning up.")
 
-	for _, cmd := range s.discordRegisteredCommands {
-		log.WithFields(logger.Fields{
-			"command_id":   cmd.ID,
-			"command_name": cmd.Name,
-		}).Print("Deleting command.")
+	var err error
 
-		const guildID = ""
+	// Commands are intentionally not deleted here. Other replicas, or the
+	// next deploy, keep serving them.
 
-		ds.ApplicationCommandDelete(s.DiscordApplicationID, guildID, cmd.ID)
+	err = s.cleanupApplications()
+	if err != nil {
+		return fmt.Errorf("could not cleanup applications: %w", err)
 	}
 
+	log.Print("Server cleanup finished.")
+
 	return nil
 }
 
-func (s *Server) isAuthorizedRequest(req *http.Request) bool {
+func (s *Server) initializeRouter() error {
 	log := s.logger()
+	log.Print("Initializing router.")
 
-	if s.SkipDiscordRequestValidation {
-		log.Print("Skipping request validation.")
+	s.cooldowns = newTTLSet(maxCooldowns)
+	s.seenInteractions = newTTLSet(maxSeenInteractions)
 
-		return true
+	r, err := createRouter(s)
+	if err != nil {
+		return fmt.Errorf("could not create router: %w", err)
 	}
 
-	log.Print("Validating request.")
+	s.chiRouter = r
 
-	return discordgo.VerifyInteraction(req, s.DiscordPublicKey)
+	return nil
 }
 
 func (s *Server) respondError(w http.ResponseWriter, errorResponse ErrorResponse) {
@@ -226,6 +280,10 @@ func (s *Server) respondError(w http.ResponseWriter, errorResponse ErrorResponse
 		errorResponse.Status = defaultStatus
 	}
 
+	if errorResponse.Title == "" {
+		errorResponse.Title = errorCatalogue[errorResponse.Type].Title
+	}
+
 	if errorResponse.Type == "" {
 		const defaultErrorType = "unknown"
 
@@ -245,6 +303,41 @@ func (s *Server) respondError(w http.ResponseWriter, errorResponse ErrorResponse
 	}
 }
 
+// respondInteraction writes `resp` as JSON. Files can't be attached to it;
+// see `deferFiles`.
+func (s *Server) respondInteraction(w http.ResponseWriter, resp *discordgo.InteractionResponse) {
+	if resp.Type == discordgo.InteractionApplicationCommandAutocompleteResult {
+		s.respondJSON(200, w, newAutocompleteResult(resp))
+
+		return
+	}
+
+	s.respondJSON(200, w, resp)
+}
+
+// autocompleteResult is the body of an
+// `InteractionApplicationCommandAutocompleteResult`. Discord requires
+// `choices` even when there are none, but `discordgo` omits empty choices.
+type autocompleteResult struct {
+	Type discordgo.InteractionResponseType `json:"type"`
+	Data struct {
+		Choices []*discordgo.ApplicationCommandOptionChoice `json:"choices"`
+	} `json:"data"`
+}
+
+func newAutocompleteResult(resp *discordgo.InteractionResponse) autocompleteResult {
+	result := autocompleteResult{
+		Type: resp.Type,
+	}
+
+	result.Data.Choices = []*discordgo.ApplicationCommandOptionChoice{}
+	if resp.Data != nil && resp.Data.Choices != nil {
+		result.Data.Choices = resp.Data.Choices
+	}
+
+	return result
+}
+
 func (s *Server) respondJSON(statusCode int, w http.ResponseWriter, v any) {
 	log := s.logger()
 
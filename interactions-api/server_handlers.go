@@ -2,12 +2,13 @@ package interactionsapi
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 
 	"github.com/bwmarrin/discordgo"
 	logger "github.com/c032/go-logger"
 
-	"github.com/c032/ffxiv-world-status-discord/ffxivapi"
+	"github.com/c032/ffxiv-world-status-discord/tracing"
 )
 
 func (s *Server) handleInteractionPing(interaction *discordgo.Interaction, w http.ResponseWriter, req *http.Request) {
@@ -18,149 +19,96 @@ func (s *Server) handleInteractionPing(interaction *discordgo.Interaction, w htt
 	s.respondJSON(200, w, resp)
 }
 
-func (s *Server) handleCommandPing(data discordgo.ApplicationCommandInteractionData, w http.ResponseWriter) {
-	resp := &discordgo.InteractionResponse{
-		Type: discordgo.InteractionResponseChannelMessageWithSource,
-		Data: &discordgo.InteractionResponseData{
-			Content: "Pong.",
-		},
-	}
-
-	s.respondJSON(200, w, resp)
-}
+func (s *Server) handleInteractionDispatch(interaction *discordgo.Interaction, w http.ResponseWriter, req *http.Request) {
+	log := s.requestLogger(req.Context())
 
-func (s *Server) handleCommandCharacters(data discordgo.ApplicationCommandInteractionData, w http.ResponseWriter) {
-	log := s.logger()
+	locale := interactionLocale(interaction)
+	app := applicationFromContext(req.Context())
 
-	var (
-		maintenanceWorlds                  []ffxivapi.World
-		characterCreationUnavailableWorlds []ffxivapi.World
-
-		err error
-		wr  *ffxivapi.WorldsResponse
+	ctx, span := s.Tracer.Start(req.Context(), "dispatch interaction",
+		tracing.String("interaction_type", interaction.Type.String()),
+		tracing.String("command", interactionCommandName(interaction)),
 	)
+	defer span.End()
 
-	wr, err = s.API.Worlds()
-	if err != nil {
-		log.Error(err.Error())
-
-		s.respondJSON(200, w, &discordgo.InteractionResponse{
-			Type: discordgo.InteractionResponseChannelMessageWithSource,
-			Data: &discordgo.InteractionResponseData{
-				Content: "Could not check availability.",
-			},
-		})
-
-		return
-	}
-
-	for _, world := range wr.Worlds {
-		if world.IsMaintenance {
-			maintenanceWorlds = append(maintenanceWorlds, world)
-		}
-		if !world.CanCreateNewCharacters {
-			characterCreationUnavailableWorlds = append(characterCreationUnavailableWorlds, world)
-		}
+	ir := &InteractionRequest{
+		Context:     ctx,
+		Application: app,
+		Interaction: interaction,
+		Locale:      locale,
 	}
 
-	var embeds []*discordgo.MessageEmbed
+	resp, err := app.commands.Dispatch(ir)
+	if err != nil {
+		span.SetError(err)
 
-	if len(maintenanceWorlds) > 0 {
-		embed, err := Worlds(maintenanceWorlds).Embed("Maintenance", s.DiscordThumbnailURL)
-		if err != nil {
-			log.Print(err.Error())
+		if errors.Is(err, ErrUnknownCommand) {
+			log.Printf("Command not recognized: %s", err.Error())
 
-			s.respondError(w, ErrorResponse{
-				Status: 500,
-				Type:   ErrTypeInternalServerError,
-			})
+			s.respondError(w, newErrorResponse(ErrTypeUnknownCommand, err.Error()))
 
 			return
 		}
 
-		embeds = append(embeds, embed)
-	}
-
-	if len(characterCreationUnavailableWorlds) > 0 {
-		embed, err := Worlds(characterCreationUnavailableWorlds).Embed("Character creation unavailable", s.DiscordThumbnailURL)
-		if err != nil {
-			log.Print(err.Error())
+		if errors.Is(err, ErrUnsupportedInteractionType) {
+			log.Printf("Interaction not supported: %s", err.Error())
 
-			s.respondError(w, ErrorResponse{
-				Status: 500,
-				Type:   ErrTypeInternalServerError,
-			})
+			s.respondError(w, newErrorResponse(ErrTypeUnsupportedInteractionType, err.Error()))
 
 			return
 		}
 
-		embeds = append(embeds, embed)
-	}
-
-	var content string
-	if len(embeds) == 0 {
-		content = "Everything looks good."
-	}
-
-	interactionResponse := &discordgo.InteractionResponse{
-		Type: discordgo.InteractionResponseChannelMessageWithSource,
-		Data: &discordgo.InteractionResponseData{
-			Embeds:  embeds,
-			Content: content,
-		},
-	}
-
-	s.respondJSON(200, w, interactionResponse)
-}
-
-func (s *Server) handleInteractionApplicationCommand(interaction *discordgo.Interaction, w http.ResponseWriter, req *http.Request) {
-	log := s.logger()
-
-	// This might panic.
-	data := interaction.ApplicationCommandData()
-
-	log.WithFields(logger.Fields{
-		"data": data,
-	}).Print("Processing command.")
+		var interactionErr *InteractionError
+		if !errors.As(err, &interactionErr) {
+			interactionErr = &InteractionError{
+				Problem: newErrorResponse(ErrTypeInternalServerError, err.Error()),
+				Message: msgInternalError,
+				Err:     err,
+			}
+		}
 
-	switch data.Name {
-	case CmdPing:
-		s.handleCommandPing(data, w)
-	case CmdCharacters:
-		s.handleCommandCharacters(data, w)
-	default:
-		log.Print("Command not recognized: %s", data.Name)
+		s.requestLoggerWithFields(req.Context(), logger.Fields{
+			"problem_type":   interactionErr.Problem.Type,
+			"problem_detail": interactionErr.Problem.Detail,
+		}).Errorf("could not handle interaction: %s", err.Error())
 
-		s.respondError(w, ErrorResponse{
-			Status: 400,
-			Type:   ErrTypeUnknownCommand,
-		})
+		s.respondInteraction(w, errorMessageResponse(interaction.Type, localize(locale, interactionErr.Message)))
 
 		return
 	}
+
+	if resp.Data != nil && len(resp.Data.Files) > 0 {
+		resp = s.deferFiles(ir, resp)
+	}
+
+	s.respondInteraction(w, resp)
 }
 
 func (s *Server) handleInteractionRequest(w http.ResponseWriter, req *http.Request) {
-	log := s.logger()
+	log := s.requestLogger(req.Context())
 
 	var interaction *discordgo.Interaction
 
 	dec := json.NewDecoder(req.Body)
 	err := dec.Decode(&interaction)
+	if err == nil && interaction == nil {
+		err = errors.New("interaction is null")
+	}
 	if err != nil {
 		log.Errorf("could not request decode body as JSON: %s", err.Error())
 
-		http.Error(w, "", http.StatusInternalServerError)
+		s.respondError(w, newErrorResponse(ErrTypeMalformedPayload, err.Error()))
 
 		return
 	}
 
+	setCurrentInteraction(req.Context(), interaction)
+	addRequestLogFields(req.Context(), s.interactionLogFields(interaction))
+
 	switch interaction.Type {
 	case discordgo.InteractionPing:
 		s.handleInteractionPing(interaction, w, req)
-	case discordgo.InteractionApplicationCommand:
-		s.handleInteractionApplicationCommand(interaction, w, req)
 	default:
-		http.Error(w, "", http.StatusBadRequest)
+		s.handleInteractionDispatch(interaction, w, req)
 	}
 }
@@ -0,0 +1,165 @@
+package interactionsapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"runtime/debug"
+	"sync"
+
+	logger "github.com/c032/go-logger"
+)
+
+var ErrShuttingDown = errors.New("server is shutting down")
+
+// inFlightGroup counts the interactions being handled, and the responses
+// deferred by them, so they can be drained on shutdown.
+type inFlightGroup struct {
+	mu      sync.Mutex
+	closed  bool
+	count   int
+	drained chan struct{}
+}
+
+// begin starts tracking a new interaction. It returns `false`, and doesn't
+// track anything, if the group was closed.
+func (g *inFlightGroup) begin() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.closed {
+		return false
+	}
+
+	g.count++
+
+	return true
+}
+
+// beginNested starts tracking work started by an interaction that is still
+// being tracked, e.g. a deferred response. Unlike `begin`, it succeeds after
+// the group was closed.
+func (g *inFlightGroup) beginNested() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.count++
+}
+
+// end stops tracking something started with `begin` or `beginNested`.
+func (g *inFlightGroup) end() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.count--
+	if g.count == 0 && g.drained != nil {
+		close(g.drained)
+		g.drained = nil
+	}
+}
+
+// isClosed returns whether `close` was called.
+func (g *inFlightGroup) isClosed() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return g.closed
+}
+
+// close makes `begin` fail, and returns a channel that is closed once
+// everything being tracked has ended.
+func (g *inFlightGroup) close() <-chan struct{} {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.closed = true
+
+	drained := make(chan struct{})
+	if g.count == 0 {
+		close(drained)
+	} else {
+		g.drained = drained
+	}
+
+	return drained
+}
+
+// inFlightMiddleware tracks the interactions being handled, and rejects new
+// ones once the server is shutting down.
+func (s *Server) inFlightMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if !s.inFlight.begin() {
+			s.respondError(w, newErrorResponse(ErrTypeShuttingDown, ""))
+
+			return
+		}
+		defer s.inFlight.end()
+
+		next.ServeHTTP(w, req)
+	})
+}
+
+// goDeferred runs `fn` in the background, e.g. to edit the original response
+// after responding with a deferred message (see `deferFiles`). `Shutdown`
+// waits for it.
+//
+// The context passed to `fn` keeps the values of `req.Context`, but isn't
+// canceled when the request ends.
+func (s *Server) goDeferred(req *InteractionRequest, fn func(ctx context.Context)) {
+	ctx := context.WithoutCancel(req.Context)
+
+	s.inFlight.beginNested()
+
+	go func() {
+		defer s.inFlight.end()
+
+		defer func() {
+			rvr := recover()
+			if rvr == nil {
+				return
+			}
+
+			s.requestLoggerWithFields(ctx, logger.Fields{
+				"panic": fmt.Sprint(rvr),
+				"stack": string(debug.Stack()),
+			}).Errorf("Recovered from panic in deferred work: %v", rvr)
+		}()
+
+		fn(ctx)
+	}()
+}
+
+// Shutdown makes the server reject new interactions, and waits until the ones
+// being handled, and the responses deferred by them, are finished, or `ctx`
+// is done.
+//
+// From then on, `/readyz` fails and interactions are answered with 503, so
+// it must be called before the HTTP server stops listening. Load balancers
+// only notice if they poll `/readyz` before that, so callers should wait a
+// while in between.
+//
+// Discord sessions are still open afterwards, so they must be released with
+// `Cleanup`.
+func (s *Server) Shutdown(ctx context.Context) error {
+	log := s.logger()
+	log.Print("Draining in-flight interactions.")
+
+	select {
+	case <-s.inFlight.close():
+		log.Print("In-flight interactions drained.")
+
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("could not drain in-flight interactions: %w", ctx.Err())
+	}
+}
+
+// checkShutdown returns `ErrShuttingDown` once `Shutdown` was called.
+func (s *Server) checkShutdown() error {
+	if s.inFlight.isClosed() {
+		return ErrShuttingDown
+	}
+
+	return nil
+}
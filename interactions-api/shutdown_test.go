@@ -0,0 +1,108 @@
+package interactionsapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/c032/ffxiv-world-status-discord/simulator"
+)
+
+func TestServer_ShutdownWaitsForDeferredWork(t *testing.T) {
+	h := newE2EHarness(t, e2eWorlds)
+
+	release := make(chan struct{})
+	finished := make(chan struct{})
+
+	h.server.inFlight.begin()
+	h.server.goDeferred(&InteractionRequest{Context: context.Background()}, func(ctx context.Context) {
+		<-release
+		close(finished)
+	})
+	h.server.inFlight.end()
+
+	shutdownErr := make(chan error, 1)
+	go func() {
+		shutdownErr <- h.server.Shutdown(context.Background())
+	}()
+
+	select {
+	case err := <-shutdownErr:
+		t.Fatalf("Shutdown returned before deferred work finished: %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	// While draining, the server is still listening, but it's not ready
+	// and rejects new interactions.
+	if code, _ := getHealth(t, h.server, "/readyz"); code != http.StatusServiceUnavailable {
+		t.Errorf("/readyz = %d while draining; want %d", code, http.StatusServiceUnavailable)
+	}
+
+	resp, err := h.client.Send(context.Background(), simulator.DefaultContext.Ping())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("resp.StatusCode = %d while draining; want %d", resp.StatusCode, http.StatusServiceUnavailable)
+	}
+
+	close(release)
+
+	err = <-shutdownErr
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-finished:
+	default:
+		t.Fatal("deferred work did not finish")
+	}
+}
+
+func TestServer_ShutdownDeadline(t *testing.T) {
+	h := newE2EHarness(t, e2eWorlds)
+
+	release := make(chan struct{})
+	t.Cleanup(func() {
+		close(release)
+	})
+
+	h.server.inFlight.begin()
+	h.server.goDeferred(&InteractionRequest{Context: context.Background()}, func(ctx context.Context) {
+		<-release
+	})
+	h.server.inFlight.end()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	err := h.server.Shutdown(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v; want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestServer_ShutdownRejectsInteractions(t *testing.T) {
+	h := newE2EHarness(t, e2eWorlds)
+
+	err := h.server.Shutdown(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := h.client.Send(context.Background(), simulator.DefaultContext.Ping())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("resp.StatusCode = %d; want %d", resp.StatusCode, http.StatusServiceUnavailable)
+	}
+
+	if code, resp := getHealth(t, h.server, "/readyz"); code != http.StatusServiceUnavailable || resp.Checks["shutdown"].Status != healthStatusUnavailable {
+		t.Errorf("/readyz = %d %#v; want %d", code, resp, http.StatusServiceUnavailable)
+	}
+}
@@ -0,0 +1,375 @@
+package interactionsapi
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/c032/ffxiv-world-status-discord/ffxivapi"
+)
+
+// Names of the templates of responses.
+const (
+	// TemplateNameEverythingGood is the content of the response when no world
+	// is under maintenance or has character creation unavailable. Its data
+	// is a `TemplateData`.
+	TemplateNameEverythingGood = "everything_good"
+
+	// TemplateNameMaintenanceTitle is the title of the embeds with the worlds
+	// under maintenance. Its data is a `TemplateData`.
+	TemplateNameMaintenanceTitle = "maintenance_title"
+
+	// TemplateNameCharacterCreationTitle is the title of the embeds with the
+	// worlds where characters can't be created. Its data is a
+	// `TemplateData`.
+	TemplateNameCharacterCreationTitle = "character_creation_title"
+
+	// TemplateNameGroupName is the name of the field with the worlds of a
+	// group. Its data is a `TemplateGroup`.
+	TemplateNameGroupName = "group_name"
+
+	// TemplateNameWorld is the line of a world in a list. Its data is a
+	// `TemplateWorld`.
+	TemplateNameWorld = "world"
+)
+
+// defaultTemplates contains the templates used when there's no override.
+var defaultTemplates = map[string]string{
+	TemplateNameEverythingGood:         `{{localize .Locale "response.everything-looks-good"}}`,
+	TemplateNameMaintenanceTitle:       `{{localize .Locale "embed.title.maintenance"}}`,
+	TemplateNameCharacterCreationTitle: `{{localize .Locale "embed.title.character-creation-unavailable"}}`,
+	TemplateNameGroupName:              `{{.Name}}`,
+	TemplateNameWorld:                  `{{if .PlainText}}{{.Name}}{{with .Labels}} ({{join . ", "}}){{end}}{{else}}{{with .Icons}}{{.}} {{end}}{{.Name}}{{end}}`,
+}
+
+// TemplateData is the data of the templates of a whole response.
+type TemplateData struct {
+	// Locale is the locale of the response, e.g. `en-US`.
+	Locale string
+
+	// GuildID is the ID of the guild where the command was used, or empty
+	// outside of guilds.
+	GuildID string
+
+	// Time is when the response was rendered.
+	Time time.Time
+
+	// PlainText is whether the guild prefers the names of states instead
+	// of icons.
+	PlainText bool
+
+	// Worlds contains every world, sorted by group, like the status board,
+	// and name.
+	Worlds []TemplateWorld
+
+	// Maintenance contains the groups with worlds under maintenance.
+	Maintenance []TemplateGroup
+
+	// CharacterCreationUnavailable contains the groups with worlds where
+	// characters can't be created.
+	CharacterCreationUnavailable []TemplateGroup
+
+	Counts TemplateCounts
+}
+
+// TemplateCounts contains the number of worlds in each state.
+type TemplateCounts struct {
+	Worlds                       int
+	Online                       int
+	Maintenance                  int
+	Congested                    int
+	CharacterCreationUnavailable int
+}
+
+// TemplateGroup is a group (i.e. a data center) with some of its worlds.
+type TemplateGroup struct {
+	Name   string
+	Worlds []TemplateWorld
+}
+
+// TemplateWorld is a single world.
+type TemplateWorld struct {
+	Name  string
+	Group string
+
+	Online                 bool
+	Maintenance            bool
+	Congested              bool
+	Preferred              bool
+	New                    bool
+	CanCreateNewCharacters bool
+
+	// Icons contains the icons of the states of the world. It's empty if
+	// `PlainText` is set.
+	Icons string
+
+	// Labels contains the localized names of the states of the world.
+	Labels []string
+
+	// Locale and PlainText are the same as in `TemplateData`.
+	Locale    string
+	PlainText bool
+
+	world ffxivapi.World
+}
+
+var templateFuncs = template.FuncMap{
+	"localize": func(locale string, key string) string {
+		return localize(discordgo.Locale(locale), messageKey(key))
+	},
+	"join":  strings.Join,
+	"lower": strings.ToLower,
+	"upper": strings.ToUpper,
+}
+
+// TemplateOverrides replaces the default templates of responses. Keys of
+// every map are template names (e.g. `TemplateNameWorld`).
+type TemplateOverrides struct {
+	// Default contains the templates used by every guild.
+	Default map[string]string
+
+	// Guilds contains the templates used by a single guild, by guild ID.
+	// They take precedence over `Default`.
+	Guilds map[string]map[string]string
+}
+
+// ResponseTemplates renders the parts of responses that can be customized.
+type ResponseTemplates struct {
+	defaults *template.Template
+	guilds   map[string]*template.Template
+}
+
+// NewResponseTemplates parses the default templates with `overrides` applied.
+//
+// Every override is checked by rendering it with sample data, so mistakes
+// are reported now instead of when a command is used. The returned error
+// contains every problem found.
+func NewResponseTemplates(overrides TemplateOverrides) (*ResponseTemplates, error) {
+	base := template.New("").Funcs(templateFuncs)
+	for _, name := range slices.Sorted(maps.Keys(defaultTemplates)) {
+		template.Must(base.New(name).Parse(defaultTemplates[name]))
+	}
+
+	var errs []error
+
+	defaults, err := applyTemplateOverrides(base, overrides.Default)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("default templates: %w", err))
+
+		// Guild templates are still checked, to report every problem at
+		// once.
+		defaults = base
+	}
+
+	rt := &ResponseTemplates{
+		defaults: defaults,
+		guilds:   map[string]*template.Template{},
+	}
+
+	for _, guildID := range slices.Sorted(maps.Keys(overrides.Guilds)) {
+		t, err := applyTemplateOverrides(defaults, overrides.Guilds[guildID])
+		if err != nil {
+			errs = append(errs, fmt.Errorf("templates of guild %#v: %w", guildID, err))
+
+			continue
+		}
+
+		rt.guilds[guildID] = t
+	}
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	return rt, nil
+}
+
+// applyTemplateOverrides returns a copy of `base` with the templates in
+// `overrides` replaced.
+func applyTemplateOverrides(base *template.Template, overrides map[string]string) (*template.Template, error) {
+	t := template.Must(base.Clone())
+	if len(overrides) == 0 {
+		return t, nil
+	}
+
+	var errs []error
+	for _, name := range slices.Sorted(maps.Keys(overrides)) {
+		if _, ok := defaultTemplates[name]; !ok {
+			errs = append(errs, fmt.Errorf("unknown template %#v (known templates: %s)", name, strings.Join(slices.Sorted(maps.Keys(defaultTemplates)), ", ")))
+
+			continue
+		}
+
+		// `text/template` doesn't replace a template with an empty one, so
+		// the default would be kept silently.
+		if strings.TrimSpace(overrides[name]) == "" {
+			errs = append(errs, fmt.Errorf("template %#v is empty", name))
+
+			continue
+		}
+
+		_, err := t.New(name).Parse(overrides[name])
+		if err != nil {
+			errs = append(errs, fmt.Errorf("could not parse template %#v: %w", name, err))
+
+			continue
+		}
+
+		err = checkTemplate(t, name)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("could not render template %#v with sample data: %w", name, err))
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	return t, nil
+}
+
+// errEmptyTemplateOutput is returned by `checkTemplate` for templates that
+// render only whitespace, which Discord rejects as the content of a message,
+// the name of a field, or a line of a list.
+var errEmptyTemplateOutput = errors.New("output is empty")
+
+// checkTemplate renders the template `name` of `t` with sample data, and
+// checks that the output isn't empty.
+func checkTemplate(t *template.Template, name string) error {
+	sample := newTemplateData(worldListStyle{
+		Locale: defaultLocale,
+		Icons:  DefaultStatusIcons,
+	}, "", []ffxivapi.World{
+		{Group: "Aether", Name: "Gilgamesh", IsMaintenance: true},
+		{Group: "Light", Name: "Lich", IsOnline: true, IsCongested: true},
+		{Group: "Light", Name: "Alpha", IsOnline: true, IsNew: true, CanCreateNewCharacters: true},
+	})
+
+	var data any = sample
+	switch name {
+	case TemplateNameGroupName:
+		data = sample.Maintenance[0]
+	case TemplateNameWorld:
+		data = sample.Worlds[0]
+	}
+
+	var sb strings.Builder
+
+	err := t.ExecuteTemplate(&sb, name, data)
+	if err != nil {
+		return err
+	}
+
+	if strings.TrimSpace(sb.String()) == "" {
+		return errEmptyTemplateOutput
+	}
+
+	return nil
+}
+
+// execute renders the template `name` for the guild with `guildID`.
+func (rt *ResponseTemplates) execute(guildID string, name string, data any) (string, error) {
+	t, ok := rt.guilds[guildID]
+	if !ok {
+		t = rt.defaults
+	}
+
+	var sb strings.Builder
+
+	err := t.ExecuteTemplate(&sb, name, data)
+	if err != nil {
+		return "", fmt.Errorf("could not render template %#v: %w", name, err)
+	}
+
+	return sb.String(), nil
+}
+
+// newTemplateWorld returns the data of `w` for templates.
+func newTemplateWorld(style worldListStyle, w ffxivapi.World) TemplateWorld {
+	return TemplateWorld{
+		Name:  w.Name,
+		Group: w.Group,
+
+		Online:                 w.IsOnline,
+		Maintenance:            w.IsMaintenance,
+		Congested:              w.IsCongested,
+		Preferred:              w.IsPreferred,
+		New:                    w.IsNew,
+		CanCreateNewCharacters: w.CanCreateNewCharacters,
+
+		Icons:  style.icons(w),
+		Labels: style.labels(w),
+
+		Locale:    string(style.Locale),
+		PlainText: style.PlainText,
+
+		world: w,
+	}
+}
+
+// newTemplateGroups returns the worlds grouped by their group, in the same
+// order as `ffxivapi.GroupByDataCenter`, which is also used by the status
+// board.
+func newTemplateGroups(style worldListStyle, worlds []ffxivapi.World) []TemplateGroup {
+	var result []TemplateGroup
+	for _, dataCenter := range ffxivapi.GroupByDataCenter(worlds) {
+		group := TemplateGroup{
+			Name: dataCenter.Name,
+		}
+		for _, w := range dataCenter.Worlds {
+			group.Worlds = append(group.Worlds, newTemplateWorld(style, w))
+		}
+
+		result = append(result, group)
+	}
+
+	return result
+}
+
+// newTemplateData returns the data of a response about `worlds` for
+// templates.
+func newTemplateData(style worldListStyle, guildID string, worlds []ffxivapi.World) TemplateData {
+	data := TemplateData{
+		Locale:    string(style.Locale),
+		GuildID:   guildID,
+		Time:      time.Now(),
+		PlainText: style.PlainText,
+	}
+
+	var maintenanceWorlds, characterCreationUnavailableWorlds []ffxivapi.World
+	for _, w := range worlds {
+		data.Counts.Worlds++
+
+		if w.IsOnline {
+			data.Counts.Online++
+		}
+		if w.IsCongested {
+			data.Counts.Congested++
+		}
+		if w.IsMaintenance {
+			data.Counts.Maintenance++
+
+			maintenanceWorlds = append(maintenanceWorlds, w)
+		}
+		if !w.CanCreateNewCharacters {
+			data.Counts.CharacterCreationUnavailable++
+
+			characterCreationUnavailableWorlds = append(characterCreationUnavailableWorlds, w)
+		}
+	}
+
+	for _, group := range newTemplateGroups(style, worlds) {
+		data.Worlds = append(data.Worlds, group.Worlds...)
+	}
+
+	data.Maintenance = newTemplateGroups(style, maintenanceWorlds)
+	data.CharacterCreationUnavailable = newTemplateGroups(style, characterCreationUnavailableWorlds)
+
+	return data
+}
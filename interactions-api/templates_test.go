@@ -0,0 +1,124 @@
+package interactionsapi
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestResponseTemplates(t *testing.T) {
+	rt, err := NewResponseTemplates(TemplateOverrides{
+		Default: map[string]string{
+			TemplateNameWorld: `{{.Name}} [{{.Group}}]`,
+		},
+		Guilds: map[string]map[string]string{
+			"1234": {
+				TemplateNameEverythingGood: `All {{.Counts.Worlds}} worlds are fine.`,
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data := newTemplateData(worldListStyle{
+		Locale: discordgo.EnglishUS,
+		Icons:  DefaultStatusIcons,
+	}, "", e2eWorlds)
+
+	tests := []struct {
+		guildID string
+		name    string
+		data    any
+		want    string
+	}{
+		{"", TemplateNameEverythingGood, data, "Everything looks good."},
+		{"", TemplateNameWorld, data.Worlds[0], "Omega [Chaos]"},
+		{"1234", TemplateNameEverythingGood, data, "All 6 worlds are fine."},
+		{"1234", TemplateNameWorld, data.Worlds[0], "Omega [Chaos]"},
+		{"1234", TemplateNameGroupName, data.Maintenance[0], "Chaos"},
+	}
+
+	for _, tt := range tests {
+		got, err := rt.execute(tt.guildID, tt.name, tt.data)
+		if err != nil {
+			t.Errorf("execute(%#v, %#v) returned error: %s", tt.guildID, tt.name, err)
+
+			continue
+		}
+
+		if got != tt.want {
+			t.Errorf("execute(%#v, %#v) = %#v; want %#v", tt.guildID, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestResponseTemplates_Defaults(t *testing.T) {
+	rt, err := NewResponseTemplates(TemplateOverrides{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	style := worldListStyle{
+		Locale: discordgo.EnglishUS,
+		Icons:  DefaultStatusIcons,
+	}
+	data := newTemplateData(style, "", e2eWorlds)
+
+	got, err := rt.execute("", TemplateNameWorld, data.Worlds[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "🔧 Omega"; got != want {
+		t.Errorf("world = %#v; want %#v", got, want)
+	}
+
+	style.PlainText = true
+	data = newTemplateData(style, "", e2eWorlds)
+
+	got, err = rt.execute("", TemplateNameWorld, data.Worlds[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "Omega (Maintenance)"; got != want {
+		t.Errorf("world = %#v; want %#v", got, want)
+	}
+}
+
+func TestNewResponseTemplates_Invalid(t *testing.T) {
+	_, err := NewResponseTemplates(TemplateOverrides{
+		Default: map[string]string{
+			"unknown": `{{.Name}}`,
+		},
+		Guilds: map[string]map[string]string{
+			"1234": {
+				TemplateNameWorld: `{{.Name`,
+			},
+			"5678": {
+				TemplateNameGroupName: `{{.Missing}}`,
+			},
+			"9012": {
+				TemplateNameEverythingGood: ` `,
+				TemplateNameGroupName:      `{{if false}}{{.Name}}{{end}}`,
+				TemplateNameWorld:          ``,
+			},
+		},
+	})
+	if err == nil {
+		t.Fatal("NewResponseTemplates() returned no error")
+	}
+
+	for _, want := range []string{
+		`unknown template "unknown"`,
+		`templates of guild "1234": could not parse template "world"`,
+		`templates of guild "5678": could not render template "group_name" with sample data`,
+		`templates of guild "9012": template "everything_good" is empty`,
+		`could not render template "group_name" with sample data: output is empty`,
+		`template "world" is empty`,
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %#v does not contain %#v", err.Error(), want)
+		}
+	}
+}
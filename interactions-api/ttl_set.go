@@ -0,0 +1,81 @@
+package interactionsapi
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// ttlSet remembers keys for a limited time, and never more than a fixed
+// number of them.
+//
+// Keys are kept in the order they were added. Expired keys at the front are
+// forgotten on every `add`, and once the set is full the oldest key is
+// forgotten even if it hasn't expired yet, so adding is O(1) and memory is
+// bounded.
+type ttlSet struct {
+	mu       sync.Mutex
+	capacity int
+	keys     map[string]*list.Element
+	order    list.List
+}
+
+type ttlSetEntry struct {
+	key       string
+	expiresAt time.Time
+}
+
+// newTTLSet returns a `ttlSet` that remembers at most `capacity` keys.
+func newTTLSet(capacity int) *ttlSet {
+	return &ttlSet{
+		capacity: max(1, capacity),
+		keys:     map[string]*list.Element{},
+	}
+}
+
+// add remembers `key` until `now + ttl`, unless it's already remembered. It
+// returns whether `key` was added and, if it wasn't, how much time is left
+// until it expires.
+func (set *ttlSet) add(key string, now time.Time, ttl time.Duration) (bool, time.Duration) {
+	set.mu.Lock()
+	defer set.mu.Unlock()
+
+	if e, ok := set.keys[key]; ok {
+		entry := e.Value.(*ttlSetEntry)
+		if now.Before(entry.expiresAt) {
+			return false, entry.expiresAt.Sub(now)
+		}
+
+		set.remove(e)
+	}
+
+	for e := set.order.Front(); e != nil; e = set.order.Front() {
+		if now.Before(e.Value.(*ttlSetEntry).expiresAt) && set.order.Len() < set.capacity {
+			break
+		}
+
+		set.remove(e)
+	}
+
+	set.keys[key] = set.order.PushBack(&ttlSetEntry{
+		key:       key,
+		expiresAt: now.Add(ttl),
+	})
+
+	return true, 0
+}
+
+// remove forgets the key of `e`. The caller must hold `set.mu`.
+func (set *ttlSet) remove(e *list.Element) {
+	delete(set.keys, e.Value.(*ttlSetEntry).key)
+	set.order.Remove(e)
+}
+
+// len returns how many keys are remembered, including expired ones that
+// weren't forgotten yet.
+func (set *ttlSet) len() int {
+	set.mu.Lock()
+	defer set.mu.Unlock()
+
+	return set.order.Len()
+}
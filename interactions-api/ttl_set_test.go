@@ -0,0 +1,51 @@
+package interactionsapi
+
+import (
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestTTLSet(t *testing.T) {
+	set := newTTLSet(10)
+	now := time.Unix(1000, 0)
+
+	if added, _ := set.add("a", now, time.Minute); !added {
+		t.Fatal("new key was not added")
+	}
+
+	added, remaining := set.add("a", now.Add(20*time.Second), time.Minute)
+	if added || remaining != 40*time.Second {
+		t.Fatalf("add() = %t, %s; want false, 40s", added, remaining)
+	}
+
+	if added, _ := set.add("a", now.Add(time.Minute), time.Minute); !added {
+		t.Fatal("expired key was not added again")
+	}
+}
+
+func TestTTLSet_Bounded(t *testing.T) {
+	set := newTTLSet(10)
+	now := time.Unix(1000, 0)
+
+	// None of the keys expire, but the oldest ones are forgotten.
+	for i := 0; i < 25; i++ {
+		set.add(strconv.Itoa(i), now, time.Hour)
+	}
+
+	if got, want := set.len(), 10; got != want {
+		t.Fatalf("set.len() = %d; want %d", got, want)
+	}
+	if added, _ := set.add("24", now, time.Hour); added {
+		t.Fatal("newest key was forgotten")
+	}
+	if added, _ := set.add("0", now, time.Hour); !added {
+		t.Fatal("oldest key was not forgotten")
+	}
+
+	// Expired keys are forgotten as new ones are added.
+	set.add("new", now.Add(2*time.Hour), time.Hour)
+	if got, want := set.len(), 1; got != want {
+		t.Fatalf("set.len() = %d; want %d", got, want)
+	}
+}
@@ -0,0 +1,250 @@
+package interactionsapi
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/c032/ffxiv-world-status-discord/tracing"
+)
+
+const (
+	DefaultMaxRequestBodySize int64 = 1 << 20
+	DefaultMaxTimestampSkew         = 5 * time.Minute
+	DefaultReplayWindow             = 15 * time.Minute
+)
+
+// maxSeenInteractions is the maximum number of interaction IDs remembered to
+// reject replayed requests. Once reached, the oldest IDs are forgotten before
+// `ReplayWindow` passes.
+const maxSeenInteractions = 100000
+
+const (
+	headerSignature = "X-Signature-Ed25519"
+	headerTimestamp = "X-Signature-Timestamp"
+)
+
+// requestValidator is a step of the request validation pipeline.
+//
+// It returns `nil` if the request is valid.
+type requestValidator func(req *http.Request, body []byte) *ErrorResponse
+
+// validationMiddleware rejects requests that don't pass every step of the
+// validation pipeline. The next handler receives the request with its body
+// already read, so it can be read again.
+func (s *Server) validationMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		log := s.requestLogger(req.Context())
+
+		ctx, span := s.Tracer.Start(req.Context(), "validate request")
+		body, problem := s.validateRequest(w, req.WithContext(ctx))
+		if problem != nil {
+			span.SetAttributes(tracing.String("problem_type", problem.Type))
+		}
+		span.End()
+
+		if problem != nil {
+			log.Printf("Rejecting request: %s", problem.Title)
+
+			s.respondError(w, *problem)
+
+			return
+		}
+
+		req.Body = io.NopCloser(bytes.NewReader(body))
+
+		next.ServeHTTP(w, req)
+	})
+}
+
+// validateRequest runs every step of the validation pipeline, and returns the
+// body of the request if it's valid.
+func (s *Server) validateRequest(w http.ResponseWriter, req *http.Request) ([]byte, *ErrorResponse) {
+	app := applicationFromContext(req.Context())
+
+	problem := s.checkContentType(req)
+	if problem != nil {
+		return nil, problem
+	}
+
+	body, problem := s.readBody(w, req)
+	if problem != nil {
+		return nil, problem
+	}
+
+	validators := []requestValidator{
+		s.checkTimestamp,
+		s.checkSignature,
+		s.checkReplay,
+	}
+	if app.SkipRequestValidation {
+		s.requestLogger(req.Context()).Print("Skipping request signature validation.")
+
+		validators = []requestValidator{
+			s.checkReplay,
+		}
+	}
+
+	for _, validate := range validators {
+		problem = validate(req, body)
+		if problem != nil {
+			return nil, problem
+		}
+	}
+
+	return body, nil
+}
+
+func (s *Server) checkContentType(req *http.Request) *ErrorResponse {
+	mediaType, _, err := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	if err != nil || mediaType != "application/json" {
+		problem := newErrorResponse(ErrTypeUnsupportedMediaType, "")
+
+		return &problem
+	}
+
+	return nil
+}
+
+func (s *Server) readBody(w http.ResponseWriter, req *http.Request) ([]byte, *ErrorResponse) {
+	maxSize := s.MaxRequestBodySize
+	if maxSize <= 0 {
+		maxSize = DefaultMaxRequestBodySize
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, req.Body, maxSize))
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			problem := newErrorResponse(ErrTypeRequestTooLarge, fmt.Sprintf("Limit is %d bytes.", maxSize))
+
+			return nil, &problem
+		}
+
+		problem := newErrorResponse(ErrTypeMalformedPayload, err.Error())
+
+		return nil, &problem
+	}
+
+	return body, nil
+}
+
+// maxTimestampSkew returns `MaxTimestampSkew`, or its default.
+func (s *Server) maxTimestampSkew() time.Duration {
+	if s.MaxTimestampSkew <= 0 {
+		return DefaultMaxTimestampSkew
+	}
+
+	return s.MaxTimestampSkew
+}
+
+// replayWindow returns `ReplayWindow`, or its default.
+func (s *Server) replayWindow() time.Duration {
+	if s.ReplayWindow <= 0 {
+		return DefaultReplayWindow
+	}
+
+	return s.ReplayWindow
+}
+
+// checkReplayWindow returns an error if interaction IDs could be forgotten
+// while their timestamps are still accepted, which would let replays through.
+func (s *Server) checkReplayWindow() error {
+	if s.replayWindow() < s.maxTimestampSkew() {
+		return fmt.Errorf("replay window (%s) must not be shorter than the maximum timestamp skew (%s)", s.replayWindow(), s.maxTimestampSkew())
+	}
+
+	return nil
+}
+
+func (s *Server) checkTimestamp(req *http.Request, body []byte) *ErrorResponse {
+	maxSkew := s.maxTimestampSkew()
+
+	rawTimestamp := req.Header.Get(headerTimestamp)
+
+	unixTimestamp, err := strconv.ParseInt(rawTimestamp, 10, 64)
+	if err != nil {
+		problem := newErrorResponse(ErrTypeInvalidTimestamp, "Timestamp is not a Unix timestamp.")
+
+		return &problem
+	}
+
+	skew := s.now().Sub(time.Unix(unixTimestamp, 0))
+	if skew < 0 {
+		skew = -skew
+	}
+
+	if skew > maxSkew {
+		problem := newErrorResponse(ErrTypeInvalidTimestamp, fmt.Sprintf("Timestamp is %s away from the server time.", skew.Round(time.Second)))
+
+		return &problem
+	}
+
+	return nil
+}
+
+func (s *Server) checkSignature(req *http.Request, body []byte) *ErrorResponse {
+	signature, err := hex.DecodeString(req.Header.Get(headerSignature))
+	if err != nil || len(signature) != ed25519.SignatureSize {
+		problem := newErrorResponse(ErrTypeInvalidSignature, "Signature is malformed.")
+
+		return &problem
+	}
+
+	msg := append([]byte(req.Header.Get(headerTimestamp)), body...)
+
+	app := applicationFromContext(req.Context())
+
+	_, span := s.Tracer.Start(req.Context(), "verify signature")
+	valid := app.PublicKeys.Verify(msg, signature)
+	span.End()
+
+	if !valid {
+		problem := newErrorResponse(ErrTypeInvalidSignature, "")
+
+		return &problem
+	}
+
+	return nil
+}
+
+func (s *Server) checkReplay(req *http.Request, body []byte) *ErrorResponse {
+	var interaction struct {
+		ID string `json:"id"`
+	}
+
+	err := json.Unmarshal(body, &interaction)
+	if err != nil {
+		problem := newErrorResponse(ErrTypeMalformedPayload, err.Error())
+
+		return &problem
+	}
+
+	if interaction.ID == "" {
+		return nil
+	}
+
+	if added, _ := s.seenInteractions.add(interaction.ID, s.now(), s.replayWindow()); !added {
+		problem := newErrorResponse(ErrTypeReplayedInteraction, "")
+
+		return &problem
+	}
+
+	return nil
+}
+
+func (s *Server) now() time.Time {
+	if s.clock != nil {
+		return s.clock()
+	}
+
+	return time.Now()
+}
@@ -0,0 +1,253 @@
+package interactionsapi
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testNow = time.Date(2024, 12, 1, 12, 0, 0, 0, time.UTC)
+
+func newTestKey(t *testing.T) (ed25519.PublicKey, ed25519.PrivateKey) {
+	t.Helper()
+
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return publicKey, privateKey
+}
+
+func newSignedRequest(privateKey ed25519.PrivateKey, timestamp time.Time, body string) *http.Request {
+	rawTimestamp := strconv.FormatInt(timestamp.Unix(), 10)
+	signature := ed25519.Sign(privateKey, []byte(rawTimestamp+body))
+
+	req := httptest.NewRequest(http.MethodPost, "/interactions", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(headerTimestamp, rawTimestamp)
+	req.Header.Set(headerSignature, hex.EncodeToString(signature))
+
+	return req
+}
+
+func newValidationTestServer(publicKey ed25519.PublicKey) (*Server, http.Handler) {
+	s := &Server{
+		Applications: []*Application{
+			{
+				Name:       "default",
+				PublicKeys: NewPublicKeyRing(publicKey),
+			},
+		},
+		clock: func() time.Time {
+			return testNow
+		},
+		seenInteractions: newTTLSet(maxSeenInteractions),
+	}
+
+	h := s.defaultApplicationMiddleware(s.validationMiddleware(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})))
+
+	return s, h
+}
+
+func serve(h http.Handler, req *http.Request) int {
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+
+	return w.Code
+}
+
+func TestValidation_ValidRequest(t *testing.T) {
+	publicKey, privateKey := newTestKey(t)
+	_, h := newValidationTestServer(publicKey)
+
+	req := newSignedRequest(privateKey, testNow, `{"id":"1","type":1}`)
+	if got, want := serve(h, req), http.StatusOK; got != want {
+		t.Errorf("status = %d; want %d", got, want)
+	}
+}
+
+func TestValidation_InvalidSignature(t *testing.T) {
+	publicKey, _ := newTestKey(t)
+	_, otherPrivateKey := newTestKey(t)
+	_, h := newValidationTestServer(publicKey)
+
+	req := newSignedRequest(otherPrivateKey, testNow, `{"id":"1","type":1}`)
+	if got, want := serve(h, req), http.StatusUnauthorized; got != want {
+		t.Errorf("status = %d; want %d", got, want)
+	}
+
+	req = newSignedRequest(otherPrivateKey, testNow, `{"id":"1","type":1}`)
+	req.Header.Set(headerSignature, "not hex")
+	if got, want := serve(h, req), http.StatusUnauthorized; got != want {
+		t.Errorf("status with malformed signature = %d; want %d", got, want)
+	}
+}
+
+func TestValidation_MultipleKeys(t *testing.T) {
+	oldPublicKey, oldPrivateKey := newTestKey(t)
+	newPublicKey, newPrivateKey := newTestKey(t)
+	s, h := newValidationTestServer(oldPublicKey)
+
+	s.Applications[0].PublicKeys.Replace([]ed25519.PublicKey{oldPublicKey, newPublicKey})
+
+	if got, want := serve(h, newSignedRequest(oldPrivateKey, testNow, `{"id":"1","type":1}`)), http.StatusOK; got != want {
+		t.Errorf("status with old key = %d; want %d", got, want)
+	}
+	if got, want := serve(h, newSignedRequest(newPrivateKey, testNow, `{"id":"2","type":1}`)), http.StatusOK; got != want {
+		t.Errorf("status with new key = %d; want %d", got, want)
+	}
+
+	s.Applications[0].PublicKeys.Replace([]ed25519.PublicKey{newPublicKey})
+
+	if got, want := serve(h, newSignedRequest(oldPrivateKey, testNow, `{"id":"3","type":1}`)), http.StatusUnauthorized; got != want {
+		t.Errorf("status with removed key = %d; want %d", got, want)
+	}
+}
+
+func TestValidation_TamperedBody(t *testing.T) {
+	publicKey, privateKey := newTestKey(t)
+	_, h := newValidationTestServer(publicKey)
+
+	req := newSignedRequest(privateKey, testNow, `{"id":"1","type":1}`)
+	req.Body = http.NoBody
+	if got, want := serve(h, req), http.StatusUnauthorized; got != want {
+		t.Errorf("status = %d; want %d", got, want)
+	}
+}
+
+func TestValidation_TimestampSkew(t *testing.T) {
+	publicKey, privateKey := newTestKey(t)
+	s, h := newValidationTestServer(publicKey)
+	s.MaxTimestampSkew = time.Minute
+
+	tests := []struct {
+		timestamp time.Time
+		want      int
+	}{
+		{testNow.Add(-30 * time.Second), http.StatusOK},
+		{testNow.Add(30 * time.Second), http.StatusOK},
+		{testNow.Add(-2 * time.Minute), http.StatusUnauthorized},
+		{testNow.Add(2 * time.Minute), http.StatusUnauthorized},
+	}
+
+	for i, tt := range tests {
+		req := newSignedRequest(privateKey, tt.timestamp, `{"id":"`+strconv.Itoa(i)+`","type":1}`)
+		if got := serve(h, req); got != tt.want {
+			t.Errorf("status with timestamp %s = %d; want %d", tt.timestamp, got, tt.want)
+		}
+	}
+
+	req := newSignedRequest(privateKey, testNow, `{"id":"100","type":1}`)
+	req.Header.Set(headerTimestamp, "yesterday")
+	if got, want := serve(h, req), http.StatusUnauthorized; got != want {
+		t.Errorf("status with malformed timestamp = %d; want %d", got, want)
+	}
+}
+
+func TestValidation_Replay(t *testing.T) {
+	publicKey, privateKey := newTestKey(t)
+	s, h := newValidationTestServer(publicKey)
+	s.MaxTimestampSkew = 30 * time.Second
+	s.ReplayWindow = time.Minute
+
+	const body = `{"id":"1","type":1}`
+
+	if got, want := serve(h, newSignedRequest(privateKey, testNow, body)), http.StatusOK; got != want {
+		t.Errorf("status of first request = %d; want %d", got, want)
+	}
+	if got, want := serve(h, newSignedRequest(privateKey, testNow, body)), http.StatusConflict; got != want {
+		t.Errorf("status of replayed request = %d; want %d", got, want)
+	}
+
+	s.clock = func() time.Time {
+		return testNow.Add(2 * time.Minute)
+	}
+	if got, want := serve(h, newSignedRequest(privateKey, testNow.Add(2*time.Minute), body)), http.StatusOK; got != want {
+		t.Errorf("status of request after replay window = %d; want %d", got, want)
+	}
+}
+
+func TestValidation_ReplayWindowShorterThanSkew(t *testing.T) {
+	s := &Server{
+		MaxTimestampSkew: time.Minute,
+		ReplayWindow:     30 * time.Second,
+	}
+
+	err := s.checkReplayWindow()
+	if err == nil {
+		t.Fatal("replay window shorter than the timestamp skew was accepted")
+	}
+
+	// Defaults are used for unset durations.
+	s.ReplayWindow = 0
+	err = s.checkReplayWindow()
+	if err != nil {
+		t.Fatalf("s.checkReplayWindow() = %s; want nil", err)
+	}
+}
+
+func TestValidation_BodySize(t *testing.T) {
+	publicKey, privateKey := newTestKey(t)
+	s, h := newValidationTestServer(publicKey)
+	s.MaxRequestBodySize = 64
+
+	body := `{"id":"1","type":1,"padding":"` + strings.Repeat("a", 64) + `"}`
+	if got, want := serve(h, newSignedRequest(privateKey, testNow, body)), http.StatusRequestEntityTooLarge; got != want {
+		t.Errorf("status = %d; want %d", got, want)
+	}
+}
+
+func TestValidation_ContentType(t *testing.T) {
+	publicKey, privateKey := newTestKey(t)
+	_, h := newValidationTestServer(publicKey)
+
+	req := newSignedRequest(privateKey, testNow, `{"id":"1","type":1}`)
+	req.Header.Set("Content-Type", "text/plain")
+	if got, want := serve(h, req), http.StatusUnsupportedMediaType; got != want {
+		t.Errorf("status = %d; want %d", got, want)
+	}
+
+	req = newSignedRequest(privateKey, testNow, `{"id":"2","type":1}`)
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	if got, want := serve(h, req), http.StatusOK; got != want {
+		t.Errorf("status with charset = %d; want %d", got, want)
+	}
+}
+
+func TestValidation_MalformedPayload(t *testing.T) {
+	publicKey, privateKey := newTestKey(t)
+	_, h := newValidationTestServer(publicKey)
+
+	req := newSignedRequest(privateKey, testNow, `{"id":`)
+	if got, want := serve(h, req), http.StatusBadRequest; got != want {
+		t.Errorf("status = %d; want %d", got, want)
+	}
+}
+
+func TestValidation_BodyIsReadable(t *testing.T) {
+	publicKey, privateKey := newTestKey(t)
+	s, _ := newValidationTestServer(publicKey)
+
+	const body = `{"id":"1","type":1}`
+
+	var gotBody bytes.Buffer
+	h := s.defaultApplicationMiddleware(s.validationMiddleware(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		_, _ = gotBody.ReadFrom(req.Body)
+	})))
+
+	serve(h, newSignedRequest(privateKey, testNow, body))
+
+	if got, want := gotBody.String(), body; got != want {
+		t.Errorf("body = %#v; want %#v", got, want)
+	}
+}
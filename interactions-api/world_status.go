@@ -0,0 +1,221 @@
+package interactionsapi
+
+import (
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/c032/ffxiv-world-status-discord/ffxivapi"
+)
+
+// StatusIcons are the icons shown next to the name of worlds for each of
+// their states. Each icon can be a Unicode emoji, or a custom emoji in the
+// format `<:name:id>`.
+//
+// Empty icons are replaced by the ones in `DefaultStatusIcons`.
+type StatusIcons struct {
+	Online                  string
+	Maintenance             string
+	Congested               string
+	Preferred               string
+	New                     string
+	CharacterCreationLocked string
+}
+
+var DefaultStatusIcons = StatusIcons{
+	Online:                  "🟢",
+	Maintenance:             "🔧",
+	Congested:               "🟠",
+	Preferred:               "⭐",
+	New:                     "🆕",
+	CharacterCreationLocked: "🔒",
+}
+
+// withDefaults returns a copy of `icons` with the empty icons replaced by the
+// ones in `DefaultStatusIcons`.
+func (icons StatusIcons) withDefaults() StatusIcons {
+	replace := func(icon *string, defaultIcon string) {
+		if *icon == "" {
+			*icon = defaultIcon
+		}
+	}
+
+	replace(&icons.Online, DefaultStatusIcons.Online)
+	replace(&icons.Maintenance, DefaultStatusIcons.Maintenance)
+	replace(&icons.Congested, DefaultStatusIcons.Congested)
+	replace(&icons.Preferred, DefaultStatusIcons.Preferred)
+	replace(&icons.New, DefaultStatusIcons.New)
+	replace(&icons.CharacterCreationLocked, DefaultStatusIcons.CharacterCreationLocked)
+
+	return icons
+}
+
+// worldState is a state of a world that has its own icon.
+type worldState int
+
+const (
+	worldStateOnline worldState = iota
+	worldStateMaintenance
+	worldStateCongested
+	worldStatePreferred
+	worldStateNew
+	worldStateCharacterCreationLocked
+)
+
+// allWorldStates contains every `worldState`, in the order they are shown in
+// legends.
+var allWorldStates = []worldState{
+	worldStateOnline,
+	worldStateMaintenance,
+	worldStateCongested,
+	worldStatePreferred,
+	worldStateNew,
+	worldStateCharacterCreationLocked,
+}
+
+func (state worldState) icon(icons StatusIcons) string {
+	switch state {
+	case worldStateOnline:
+		return icons.Online
+	case worldStateMaintenance:
+		return icons.Maintenance
+	case worldStateCongested:
+		return icons.Congested
+	case worldStatePreferred:
+		return icons.Preferred
+	case worldStateNew:
+		return icons.New
+	case worldStateCharacterCreationLocked:
+		return icons.CharacterCreationLocked
+	}
+
+	return ""
+}
+
+func (state worldState) label(locale discordgo.Locale) string {
+	switch state {
+	case worldStateOnline:
+		return localize(locale, msgStateOnline)
+	case worldStateMaintenance:
+		return localize(locale, msgStateMaintenance)
+	case worldStateCongested:
+		return localize(locale, msgStateCongested)
+	case worldStatePreferred:
+		return localize(locale, msgStatePreferred)
+	case worldStateNew:
+		return localize(locale, msgStateNew)
+	case worldStateCharacterCreationLocked:
+		return localize(locale, msgStateCharacterCreationLocked)
+	}
+
+	return ""
+}
+
+// worldStates returns the states of `w`. The first one is `w.State()`,
+// unless it's offline, and it's followed by the states that can be combined
+// with any other.
+func worldStates(w ffxivapi.World) []worldState {
+	var states []worldState
+
+	switch w.State() {
+	case ffxivapi.StateMaintenance:
+		states = append(states, worldStateMaintenance)
+	case ffxivapi.StateCongested:
+		states = append(states, worldStateCongested)
+	case ffxivapi.StatePreferred:
+		states = append(states, worldStatePreferred)
+	case ffxivapi.StateOnline:
+		states = append(states, worldStateOnline)
+	}
+
+	if w.IsNew {
+		states = append(states, worldStateNew)
+	}
+	if !w.CanCreateNewCharacters {
+		states = append(states, worldStateCharacterCreationLocked)
+	}
+
+	return states
+}
+
+// Colours of embeds, by the most severe state of their worlds.
+const (
+	colorSeverityCritical = 0xED4245
+	colorSeverityWarning  = 0xFEE75C
+	colorSeverityOK       = 0x57F287
+)
+
+// severityColor returns the colour of an embed that lists `worlds`.
+func severityColor(worlds []ffxivapi.World) int {
+	color := colorSeverityOK
+
+	for _, w := range worlds {
+		if w.IsMaintenance {
+			return colorSeverityCritical
+		}
+		if w.IsCongested || !w.CanCreateNewCharacters {
+			color = colorSeverityWarning
+		}
+	}
+
+	return color
+}
+
+// worldListStyle decides how worlds are written in lists.
+type worldListStyle struct {
+	Locale discordgo.Locale
+	Icons  StatusIcons
+
+	// PlainText makes the default templates show the names of the states
+	// instead of icons, e.g. for screen readers.
+	PlainText bool
+}
+
+// icons returns the icons of the states of `w`, or an empty string if icons
+// are not used.
+func (style worldListStyle) icons(w ffxivapi.World) string {
+	if style.PlainText {
+		return ""
+	}
+
+	var icons strings.Builder
+	for _, state := range worldStates(w) {
+		icons.WriteString(state.icon(style.Icons))
+	}
+
+	return icons.String()
+}
+
+// labels returns the localized names of the states of `w`.
+func (style worldListStyle) labels(w ffxivapi.World) []string {
+	var labels []string
+	for _, state := range worldStates(w) {
+		labels = append(labels, state.label(style.Locale))
+	}
+
+	return labels
+}
+
+// legend returns the explanation of the icons of the states of `worlds`, or
+// an empty string if icons are not used.
+func (style worldListStyle) legend(worlds []ffxivapi.World) string {
+	if style.PlainText {
+		return ""
+	}
+
+	used := map[worldState]bool{}
+	for _, w := range worlds {
+		for _, state := range worldStates(w) {
+			used[state] = true
+		}
+	}
+
+	var entries []string
+	for _, state := range allWorldStates {
+		if used[state] {
+			entries = append(entries, state.icon(style.Icons)+" "+state.label(style.Locale))
+		}
+	}
+
+	return strings.Join(entries, " · ")
+}
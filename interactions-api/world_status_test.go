@@ -0,0 +1,58 @@
+package interactionsapi
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/c032/ffxiv-world-status-discord/ffxivapi"
+)
+
+func TestWorldListStyle(t *testing.T) {
+	worlds := []ffxivapi.World{
+		{Name: "Alpha", IsOnline: true, IsNew: true, CanCreateNewCharacters: true},
+		{Name: "Lich", IsOnline: true, IsCongested: true},
+	}
+
+	style := worldListStyle{
+		Locale: discordgo.EnglishUS,
+		Icons:  StatusIcons{New: "<:new:123>"}.withDefaults(),
+	}
+
+	if got, want := style.icons(worlds[0]), "🟢<:new:123>"; got != want {
+		t.Errorf("style.icons() = %#v; want %#v", got, want)
+	}
+	if got, want := style.legend(worlds), "🟢 Online · 🟠 Congested · <:new:123> New · 🔒 Creation locked"; got != want {
+		t.Errorf("style.legend() = %#v; want %#v", got, want)
+	}
+
+	style.PlainText = true
+
+	if got := style.icons(worlds[1]); got != "" {
+		t.Errorf("style.icons() = %#v; want no icons", got)
+	}
+	if got, want := strings.Join(style.labels(worlds[1]), ", "), "Congested, Creation locked"; got != want {
+		t.Errorf("style.labels() = %#v; want %#v", got, want)
+	}
+	if got := style.legend(worlds); got != "" {
+		t.Errorf("style.legend() = %#v; want no legend", got)
+	}
+}
+
+func TestSeverityColor(t *testing.T) {
+	tests := []struct {
+		worlds []ffxivapi.World
+		want   int
+	}{
+		{[]ffxivapi.World{{IsOnline: true, CanCreateNewCharacters: true}}, colorSeverityOK},
+		{[]ffxivapi.World{{IsOnline: true}}, colorSeverityWarning},
+		{[]ffxivapi.World{{IsOnline: true}, {IsMaintenance: true}}, colorSeverityCritical},
+	}
+
+	for _, tt := range tests {
+		if got := severityColor(tt.worlds); got != tt.want {
+			t.Errorf("severityColor(%#v) = %#x; want %#x", tt.worlds, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,345 @@
+// Package metrics implements counters, gauges and histograms that can be
+// exposed in the Prometheus text format.
+//
+// Only the subset of the format needed by this project is supported.
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ContentType is the content type of the Prometheus text format.
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// DefaultBuckets are the upper bounds, in seconds, of the buckets used for
+// latency histograms.
+var DefaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+type metricType string
+
+const (
+	typeCounter   metricType = "counter"
+	typeGauge     metricType = "gauge"
+	typeHistogram metricType = "histogram"
+)
+
+// Registry contains metric families, and writes them in the Prometheus text
+// format.
+type Registry struct {
+	mu       sync.Mutex
+	families []*family
+}
+
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+func (r *Registry) register(f *family) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, existing := range r.families {
+		if existing.name == f.name {
+			panic(fmt.Sprintf("metrics: metric %#v is already registered", f.name))
+		}
+	}
+
+	r.families = append(r.families, f)
+}
+
+// NewCounter registers a counter with the given label names.
+func (r *Registry) NewCounter(name string, help string, labelNames ...string) *CounterVec {
+	f := newFamily(name, help, typeCounter, labelNames, nil)
+	r.register(f)
+
+	return &CounterVec{f: f}
+}
+
+// NewGauge registers a gauge with the given label names.
+func (r *Registry) NewGauge(name string, help string, labelNames ...string) *GaugeVec {
+	f := newFamily(name, help, typeGauge, labelNames, nil)
+	r.register(f)
+
+	return &GaugeVec{f: f}
+}
+
+// NewHistogram registers a histogram with the given label names. If
+// `buckets` is empty, `DefaultBuckets` is used.
+func (r *Registry) NewHistogram(name string, help string, buckets []float64, labelNames ...string) *HistogramVec {
+	if len(buckets) == 0 {
+		buckets = DefaultBuckets
+	}
+
+	buckets = slices.Clone(buckets)
+	slices.Sort(buckets)
+
+	f := newFamily(name, help, typeHistogram, labelNames, buckets)
+	r.register(f)
+
+	return &HistogramVec{f: f}
+}
+
+// WriteTo writes every metric in the Prometheus text format.
+func (r *Registry) WriteTo(w io.Writer) (int64, error) {
+	r.mu.Lock()
+	families := slices.Clone(r.families)
+	r.mu.Unlock()
+
+	slices.SortFunc(families, func(a, b *family) int {
+		return strings.Compare(a.name, b.name)
+	})
+
+	var sb strings.Builder
+	for _, f := range families {
+		f.write(&sb)
+	}
+
+	n, err := io.WriteString(w, sb.String())
+
+	return int64(n), err
+}
+
+func (r *Registry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", ContentType)
+
+	_, _ = r.WriteTo(w)
+}
+
+type family struct {
+	name       string
+	help       string
+	typ        metricType
+	labelNames []string
+	buckets    []float64
+
+	mu     sync.Mutex
+	series map[string]*series
+}
+
+type series struct {
+	labelValues []string
+
+	// value is used by counters and gauges.
+	value float64
+
+	// bucketCounts, sum and count are used by histograms.
+	bucketCounts []uint64
+	sum          float64
+	count        uint64
+}
+
+func newFamily(name string, help string, typ metricType, labelNames []string, buckets []float64) *family {
+	return &family{
+		name:       name,
+		help:       help,
+		typ:        typ,
+		labelNames: slices.Clone(labelNames),
+		buckets:    buckets,
+		series:     map[string]*series{},
+	}
+}
+
+func (f *family) checkLabelValues(labelValues []string) {
+	if len(labelValues) != len(f.labelNames) {
+		panic(fmt.Sprintf("metrics: metric %#v has %d labels; got %d values", f.name, len(f.labelNames), len(labelValues)))
+	}
+}
+
+// update calls `fn` with the series identified by `labelValues`, creating it
+// if it doesn't exist.
+func (f *family) update(labelValues []string, fn func(s *series)) {
+	f.checkLabelValues(labelValues)
+
+	key := strings.Join(labelValues, "\xff")
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	s, ok := f.series[key]
+	if !ok {
+		s = &series{
+			labelValues: slices.Clone(labelValues),
+		}
+		if f.typ == typeHistogram {
+			s.bucketCounts = make([]uint64, len(f.buckets))
+		}
+
+		f.series[key] = s
+	}
+
+	fn(s)
+}
+
+// replace swaps every series with `newSeries` at once.
+func (f *family) replace(newSeries map[string]*series) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.series = newSeries
+}
+
+func (f *family) write(sb *strings.Builder) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	fmt.Fprintf(sb, "# HELP %s %s\n", f.name, escapeHelp(f.help))
+	fmt.Fprintf(sb, "# TYPE %s %s\n", f.name, f.typ)
+
+	keys := make([]string, 0, len(f.series))
+	for key := range f.series {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	for _, key := range keys {
+		s := f.series[key]
+
+		if f.typ != typeHistogram {
+			fmt.Fprintf(sb, "%s%s %s\n", f.name, formatLabels(f.labelNames, s.labelValues), formatFloat(s.value))
+
+			continue
+		}
+
+		labelNames := append(slices.Clone(f.labelNames), "le")
+
+		var cumulative uint64
+		for i, upperBound := range f.buckets {
+			cumulative += s.bucketCounts[i]
+
+			labelValues := append(slices.Clone(s.labelValues), formatFloat(upperBound))
+			fmt.Fprintf(sb, "%s_bucket%s %d\n", f.name, formatLabels(labelNames, labelValues), cumulative)
+		}
+
+		labelValues := append(slices.Clone(s.labelValues), "+Inf")
+		fmt.Fprintf(sb, "%s_bucket%s %d\n", f.name, formatLabels(labelNames, labelValues), s.count)
+
+		fmt.Fprintf(sb, "%s_sum%s %s\n", f.name, formatLabels(f.labelNames, s.labelValues), formatFloat(s.sum))
+		fmt.Fprintf(sb, "%s_count%s %d\n", f.name, formatLabels(f.labelNames, s.labelValues), s.count)
+	}
+}
+
+func formatLabels(names []string, values []string) string {
+	if len(names) == 0 {
+		return ""
+	}
+
+	pairs := make([]string, len(names))
+	for i, name := range names {
+		pairs[i] = name + `="` + escapeLabelValue(values[i]) + `"`
+	}
+
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+var (
+	helpReplacer       = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelValueReplacer = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+func escapeHelp(s string) string {
+	return helpReplacer.Replace(s)
+}
+
+func escapeLabelValue(s string) string {
+	return labelValueReplacer.Replace(s)
+}
+
+// CounterVec is a counter partitioned by labels.
+type CounterVec struct {
+	f *family
+}
+
+// Add increases the counter identified by `labelValues`. `v` must not be
+// negative.
+func (c *CounterVec) Add(v float64, labelValues ...string) {
+	if v < 0 {
+		panic(fmt.Sprintf("metrics: counter %#v can't decrease", c.f.name))
+	}
+
+	c.f.update(labelValues, func(s *series) {
+		s.value += v
+	})
+}
+
+// Inc increases the counter identified by `labelValues` by one.
+func (c *CounterVec) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+// GaugeVec is a gauge partitioned by labels.
+type GaugeVec struct {
+	f *family
+}
+
+// Set sets the gauge identified by `labelValues`.
+func (g *GaugeVec) Set(v float64, labelValues ...string) {
+	g.f.update(labelValues, func(s *series) {
+		s.value = v
+	})
+}
+
+// GaugeValue is the value of a single series of a gauge.
+type GaugeValue struct {
+	LabelValues []string
+	Value       float64
+}
+
+// Replace removes every series of the gauge and sets `values` instead, e.g.
+// with a new snapshot, so series that no longer exist are not reported.
+//
+// The series are swapped at once, so scrapes see either the previous series
+// or the new ones, and never an empty gauge.
+func (g *GaugeVec) Replace(values []GaugeValue) {
+	newSeries := make(map[string]*series, len(values))
+	for _, v := range values {
+		g.f.checkLabelValues(v.LabelValues)
+
+		newSeries[strings.Join(v.LabelValues, "\xff")] = &series{
+			labelValues: slices.Clone(v.LabelValues),
+			value:       v.Value,
+		}
+	}
+
+	g.f.replace(newSeries)
+}
+
+// HistogramVec is a histogram partitioned by labels.
+type HistogramVec struct {
+	f *family
+}
+
+// Observe adds `v` to the histogram identified by `labelValues`.
+func (h *HistogramVec) Observe(v float64, labelValues ...string) {
+	h.f.update(labelValues, func(s *series) {
+		for i, upperBound := range h.f.buckets {
+			if v <= upperBound {
+				s.bucketCounts[i]++
+
+				break
+			}
+		}
+
+		s.sum += v
+		s.count++
+	})
+}
@@ -0,0 +1,84 @@
+package metrics_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/c032/ffxiv-world-status-discord/metrics"
+)
+
+func TestRegistry_WriteTo(t *testing.T) {
+	r := metrics.NewRegistry()
+
+	requests := r.NewCounter("test_requests_total", "Requests handled.", "method", "code")
+	requests.Inc("GET", "200")
+	requests.Inc("GET", "200")
+	requests.Add(3, "POST", "500")
+
+	up := r.NewGauge("test_up", "Whether it's up.\nSecond line.")
+	up.Set(1)
+
+	worlds := r.NewGauge("test_world_online", "Whether the world is online.", "world")
+	worlds.Set(1, `Quote"d`)
+
+	latency := r.NewHistogram("test_latency_seconds", "Latency.", []float64{0.5, 0.1}, "method")
+	latency.Observe(0.05, "GET")
+	latency.Observe(0.3, "GET")
+	latency.Observe(2, "GET")
+
+	var sb strings.Builder
+	_, err := r.WriteTo(&sb)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const want = `# HELP test_latency_seconds Latency.
+# TYPE test_latency_seconds histogram
+test_latency_seconds_bucket{method="GET",le="0.1"} 1
+test_latency_seconds_bucket{method="GET",le="0.5"} 2
+test_latency_seconds_bucket{method="GET",le="+Inf"} 3
+test_latency_seconds_sum{method="GET"} 2.35
+test_latency_seconds_count{method="GET"} 3
+# HELP test_requests_total Requests handled.
+# TYPE test_requests_total counter
+test_requests_total{method="GET",code="200"} 2
+test_requests_total{method="POST",code="500"} 3
+# HELP test_up Whether it's up.\nSecond line.
+# TYPE test_up gauge
+test_up 1
+# HELP test_world_online Whether the world is online.
+# TYPE test_world_online gauge
+test_world_online{world="Quote\"d"} 1
+`
+
+	if got := sb.String(); got != want {
+		t.Errorf("r.WriteTo() wrote:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestGaugeVec_Replace(t *testing.T) {
+	r := metrics.NewRegistry()
+
+	g := r.NewGauge("test_gauge", "Test.", "name")
+	g.Set(1, "old")
+	g.Replace([]metrics.GaugeValue{
+		{LabelValues: []string{"new"}, Value: 1},
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	if got, want := w.Header().Get("Content-Type"), metrics.ContentType; got != want {
+		t.Errorf("Content-Type = %#v; want %#v", got, want)
+	}
+
+	body := w.Body.String()
+	if strings.Contains(body, `name="old"`) {
+		t.Errorf("body contains removed series:\n%s", body)
+	}
+	if !strings.Contains(body, `test_gauge{name="new"} 1`) {
+		t.Errorf("body does not contain new series:\n%s", body)
+	}
+}
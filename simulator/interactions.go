@@ -0,0 +1,194 @@
+package simulator
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// discordEpoch is the first millisecond of 2015, in Unix milliseconds.
+const discordEpoch = 1420070400000
+
+var snowflakeSequence atomic.Int64
+
+// newSnowflake returns an ID that looks like the ones created by Discord.
+// IDs are unique within the process, so replay protection doesn't reject
+// them.
+func newSnowflake(now time.Time) string {
+	ms := now.UnixMilli() - discordEpoch
+	seq := snowflakeSequence.Add(1) & 0xfff
+
+	return strconv.FormatInt(ms<<22|seq, 10)
+}
+
+func newToken() string {
+	var b [32]byte
+	_, _ = rand.Read(b[:])
+
+	return hex.EncodeToString(b[:])
+}
+
+// Context contains the fields shared by every interaction.
+type Context struct {
+	ApplicationID string
+
+	// GuildID is the guild where the interaction happens. If it's empty, the
+	// interaction happens in a DM.
+	GuildID   string
+	ChannelID string
+	UserID    string
+	Username  string
+
+	// Permissions of the member in the guild.
+	Permissions int64
+
+	Locale discordgo.Locale
+}
+
+// DefaultContext is an interaction by an administrator in a guild.
+var DefaultContext = Context{
+	ApplicationID: "100000000000000000",
+	GuildID:       "200000000000000000",
+	ChannelID:     "300000000000000000",
+	UserID:        "400000000000000000",
+	Username:      "simulator",
+	Permissions:   discordgo.PermissionAdministrator,
+	Locale:        discordgo.EnglishUS,
+}
+
+func (c Context) interaction(interactionType discordgo.InteractionType, data discordgo.InteractionData) *discordgo.Interaction {
+	user := &discordgo.User{
+		ID:       c.UserID,
+		Username: c.Username,
+	}
+
+	interaction := &discordgo.Interaction{
+		ID:        newSnowflake(time.Now()),
+		AppID:     c.ApplicationID,
+		Type:      interactionType,
+		Data:      data,
+		GuildID:   c.GuildID,
+		ChannelID: c.ChannelID,
+		Locale:    c.Locale,
+		Token:     newToken(),
+		Version:   1,
+	}
+
+	if c.GuildID != "" {
+		guildLocale := c.Locale
+
+		interaction.Member = &discordgo.Member{
+			GuildID:     c.GuildID,
+			User:        user,
+			Permissions: c.Permissions,
+		}
+		interaction.GuildLocale = &guildLocale
+	} else {
+		interaction.User = user
+	}
+
+	return interaction
+}
+
+// Ping returns the interaction that Discord sends to check that the endpoint
+// is reachable.
+func (c Context) Ping() *discordgo.Interaction {
+	return c.interaction(discordgo.InteractionPing, nil)
+}
+
+// Command returns the use of the slash command `name` with `options`.
+func (c Context) Command(name string, options ...*discordgo.ApplicationCommandInteractionDataOption) *discordgo.Interaction {
+	return c.interaction(discordgo.InteractionApplicationCommand, discordgo.ApplicationCommandInteractionData{
+		ID:          newSnowflake(time.Now()),
+		Name:        name,
+		CommandType: discordgo.ChatApplicationCommand,
+		Options:     options,
+	})
+}
+
+// Autocomplete returns the request of choices for the option of the command
+// `name` that is focused. Exactly one of `options` should be focused.
+func (c Context) Autocomplete(name string, options ...*discordgo.ApplicationCommandInteractionDataOption) *discordgo.Interaction {
+	return c.interaction(discordgo.InteractionApplicationCommandAutocomplete, discordgo.ApplicationCommandInteractionData{
+		ID:          newSnowflake(time.Now()),
+		Name:        name,
+		CommandType: discordgo.ChatApplicationCommand,
+		Options:     options,
+	})
+}
+
+// Component returns a click on the button with `customID`, or the selection
+// of `values` in the select menu with `customID`.
+func (c Context) Component(customID string, values ...string) *discordgo.Interaction {
+	componentType := discordgo.ButtonComponent
+	if len(values) > 0 {
+		componentType = discordgo.SelectMenuComponent
+	}
+
+	interaction := c.interaction(discordgo.InteractionMessageComponent, discordgo.MessageComponentInteractionData{
+		CustomID:      customID,
+		ComponentType: componentType,
+		Values:        values,
+	})
+
+	interaction.Message = &discordgo.Message{
+		ID:        newSnowflake(time.Now()),
+		ChannelID: c.ChannelID,
+		GuildID:   c.GuildID,
+	}
+
+	return interaction
+}
+
+// Option returns a command option named `name`. The type of the option is
+// taken from `value`, which can be a string, a bool, an int or a float64.
+func Option(name string, value any) *discordgo.ApplicationCommandInteractionDataOption {
+	option := &discordgo.ApplicationCommandInteractionDataOption{
+		Name:  name,
+		Value: value,
+	}
+
+	switch v := value.(type) {
+	case bool:
+		option.Type = discordgo.ApplicationCommandOptionBoolean
+	case int:
+		option.Type = discordgo.ApplicationCommandOptionInteger
+		option.Value = float64(v)
+	case float64:
+		option.Type = discordgo.ApplicationCommandOptionNumber
+	default:
+		option.Type = discordgo.ApplicationCommandOptionString
+	}
+
+	return option
+}
+
+// ParseOption is like `Option`, but the type is guessed from `rawValue`:
+// `true` and `false` are booleans, integers are integers, other numbers are
+// numbers, and everything else is a string.
+func ParseOption(name string, rawValue string) *discordgo.ApplicationCommandInteractionDataOption {
+	if rawValue == "true" || rawValue == "false" {
+		return Option(name, rawValue == "true")
+	}
+
+	if v, err := strconv.Atoi(rawValue); err == nil {
+		return Option(name, v)
+	}
+
+	if v, err := strconv.ParseFloat(rawValue, 64); err == nil {
+		return Option(name, v)
+	}
+
+	return Option(name, rawValue)
+}
+
+// Focused marks `option` as the one being autocompleted.
+func Focused(option *discordgo.ApplicationCommandInteractionDataOption) *discordgo.ApplicationCommandInteractionDataOption {
+	option.Focused = true
+
+	return option
+}
@@ -0,0 +1,82 @@
+package simulator
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+)
+
+// PublicKeyFilePath returns the path of the file with the public key of the
+// private key at `privateKeyPath`.
+func PublicKeyFilePath(privateKeyPath string) string {
+	return privateKeyPath + ".pub"
+}
+
+// ReadPrivateKeyFile reads a private key written by `WritePrivateKeyFile`.
+func ReadPrivateKeyFile(path string) (ed25519.PrivateKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read private key: %w", err)
+	}
+
+	seed, err := hex.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil {
+		return nil, fmt.Errorf("could not decode private key: %w", err)
+	}
+
+	if len(seed) != ed25519.SeedSize {
+		return nil, fmt.Errorf("private key has %d bytes; expected %d", len(seed), ed25519.SeedSize)
+	}
+
+	return ed25519.NewKeyFromSeed(seed), nil
+}
+
+// WritePrivateKeyFile writes the seed of `key`, hex-encoded, to `path`, and
+// its public key to `PublicKeyFilePath(path)`, in the format expected by
+// `interactionsapi.ParsePublicKeys`.
+func WritePrivateKeyFile(path string, key ed25519.PrivateKey) error {
+	err := os.WriteFile(path, []byte(hex.EncodeToString(key.Seed())+"\n"), 0o600)
+	if err != nil {
+		return fmt.Errorf("could not write private key: %w", err)
+	}
+
+	publicKey := key.Public().(ed25519.PublicKey)
+
+	err = os.WriteFile(PublicKeyFilePath(path), []byte(hex.EncodeToString(publicKey)+"\n"), 0o644)
+	if err != nil {
+		return fmt.Errorf("could not write public key: %w", err)
+	}
+
+	return nil
+}
+
+// LoadOrGenerateKey reads the private key at `path`. If the file doesn't
+// exist, a new key is generated and written there.
+//
+// It also returns whether the key was generated.
+func LoadOrGenerateKey(path string) (ed25519.PrivateKey, bool, error) {
+	key, err := ReadPrivateKeyFile(path)
+	if err == nil {
+		return key, false, nil
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		return nil, false, err
+	}
+
+	_, key, err = ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, false, fmt.Errorf("could not generate private key: %w", err)
+	}
+
+	err = WritePrivateKeyFile(path, key)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return key, true, nil
+}
@@ -0,0 +1,269 @@
+// Package simulator sends interactions to the interactions API, signed like
+// Discord signs them, so the whole verification path can be exercised
+// locally.
+package simulator
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	headerSignature = "X-Signature-Ed25519"
+	headerTimestamp = "X-Signature-Timestamp"
+)
+
+// Sign returns the headers that Discord would send with `body` at
+// `timestamp`, signed with `key`.
+func Sign(key ed25519.PrivateKey, timestamp time.Time, body []byte) http.Header {
+	rawTimestamp := strconv.FormatInt(timestamp.Unix(), 10)
+	signature := ed25519.Sign(key, append([]byte(rawTimestamp), body...))
+
+	h := http.Header{}
+	h.Set(headerSignature, hex.EncodeToString(signature))
+	h.Set(headerTimestamp, rawTimestamp)
+
+	return h
+}
+
+// NewRequest returns a signed request that sends `interaction` to `url`.
+func NewRequest(ctx context.Context, url string, key ed25519.PrivateKey, interaction *discordgo.Interaction) (*http.Request, error) {
+	body, err := json.Marshal(interaction)
+	if err != nil {
+		return nil, fmt.Errorf("could not encode interaction: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("could not create request: %w", err)
+	}
+
+	for k, v := range Sign(key, time.Now(), body) {
+		req.Header[k] = v
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	return req, nil
+}
+
+// Response is the response of the server to an interaction.
+type Response struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+// Pretty returns the body indented, if it's JSON, or as is otherwise. For
+// multipart bodies, it returns the JSON payload followed by the names of the
+// attached files.
+func (resp *Response) Pretty() string {
+	payload, files, err := resp.parts()
+	if err != nil {
+		return string(resp.Body)
+	}
+
+	var buf bytes.Buffer
+
+	err = json.Indent(&buf, payload, "", "  ")
+	if err != nil {
+		buf.Reset()
+		buf.Write(payload)
+	}
+
+	for _, f := range files {
+		data, _ := io.ReadAll(f.Reader)
+
+		fmt.Fprintf(&buf, "\nAttached file: %s (%s, %d bytes)", f.Name, f.ContentType, len(data))
+	}
+
+	return buf.String()
+}
+
+// InteractionResponse decodes the body as an interaction response, with the
+// files of multipart bodies in `Data.Files`.
+func (resp *Response) InteractionResponse() (*discordgo.InteractionResponse, error) {
+	payload, files, err := resp.parts()
+	if err != nil {
+		return nil, err
+	}
+
+	ir, err := DecodeInteractionResponse(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(files) > 0 {
+		if ir.Data == nil {
+			ir.Data = &discordgo.InteractionResponseData{}
+		}
+
+		ir.Data.Files = files
+	}
+
+	return ir, nil
+}
+
+// parts returns the JSON payload of the body and, if the body is multipart,
+// the files attached to it.
+func (resp *Response) parts() ([]byte, []*discordgo.File, error) {
+	mediaType, params, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/form-data" {
+		return resp.Body, nil, nil
+	}
+
+	var (
+		payload []byte
+		files   []*discordgo.File
+	)
+
+	mr := multipart.NewReader(bytes.NewReader(resp.Body), params["boundary"])
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("could not read multipart response: %w", err)
+		}
+
+		data, err := io.ReadAll(part)
+		if err != nil {
+			return nil, nil, fmt.Errorf("could not read multipart response: %w", err)
+		}
+
+		if part.FormName() == "payload_json" {
+			payload = data
+
+			continue
+		}
+
+		files = append(files, &discordgo.File{
+			Name:        part.FileName(),
+			ContentType: part.Header.Get("Content-Type"),
+			Reader:      bytes.NewReader(data),
+		})
+	}
+
+	return payload, files, nil
+}
+
+// DecodeComponents decodes message components, which `discordgo` can only
+// decode as part of a `discordgo.Message`.
+func DecodeComponents(rawComponents []json.RawMessage) ([]discordgo.MessageComponent, error) {
+	components := make([]discordgo.MessageComponent, 0, len(rawComponents))
+	for _, raw := range rawComponents {
+		component, err := discordgo.MessageComponentFromJSON(raw)
+		if err != nil {
+			return nil, fmt.Errorf("could not decode component: %w", err)
+		}
+
+		components = append(components, component)
+	}
+
+	return components, nil
+}
+
+// DecodeInteractionResponse decodes an interaction response, including its
+// components.
+func DecodeInteractionResponse(data []byte) (*discordgo.InteractionResponse, error) {
+	var raw struct {
+		Type discordgo.InteractionResponseType `json:"type"`
+		Data map[string]json.RawMessage        `json:"data"`
+	}
+
+	err := json.Unmarshal(data, &raw)
+	if err != nil {
+		return nil, fmt.Errorf("could not decode interaction response: %w", err)
+	}
+
+	ir := &discordgo.InteractionResponse{
+		Type: raw.Type,
+	}
+	if raw.Data == nil {
+		return ir, nil
+	}
+
+	var rawComponents []json.RawMessage
+	if rawValue, ok := raw.Data["components"]; ok {
+		err = json.Unmarshal(rawValue, &rawComponents)
+		if err != nil {
+			return nil, fmt.Errorf("could not decode components: %w", err)
+		}
+
+		delete(raw.Data, "components")
+	}
+
+	dataWithoutComponents, err := json.Marshal(raw.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(dataWithoutComponents, &ir.Data)
+	if err != nil {
+		return nil, fmt.Errorf("could not decode interaction response data: %w", err)
+	}
+
+	if rawComponents != nil {
+		ir.Data.Components, err = DecodeComponents(rawComponents)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return ir, nil
+}
+
+// Client sends signed interactions to a running server.
+type Client struct {
+	// URL is the interactions endpoint, e.g.
+	// `http://localhost:8000/interactions`.
+	URL string
+
+	Key ed25519.PrivateKey
+
+	// HTTPClient defaults to `http.DefaultClient`.
+	HTTPClient *http.Client
+}
+
+// Send sends `interaction` and returns the response of the server, whatever
+// its status.
+func (c *Client) Send(ctx context.Context, interaction *discordgo.Interaction) (*Response, error) {
+	req, err := NewRequest(ctx, c.URL, c.Key, interaction)
+	if err != nil {
+		return nil, err
+	}
+
+	hc := c.HTTPClient
+	if hc == nil {
+		hc = http.DefaultClient
+	}
+
+	httpResp, err := hc.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("could not send interaction: %w", err)
+	}
+	defer httpResp.Body.Close()
+
+	body, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("could not read response: %w", err)
+	}
+
+	return &Response{
+		StatusCode: httpResp.StatusCode,
+		Header:     httpResp.Header,
+		Body:       body,
+	}, nil
+}
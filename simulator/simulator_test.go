@@ -0,0 +1,161 @@
+package simulator
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+
+	iapi "github.com/c032/ffxiv-world-status-discord/interactions-api"
+)
+
+func newTestServer(t *testing.T, publicKey ed25519.PublicKey) *httptest.Server {
+	t.Helper()
+
+	s := &iapi.Server{
+		Applications: []*iapi.Application{
+			{
+				Name:       "default",
+				ID:         DefaultContext.ApplicationID,
+				Token:      "token",
+				PublicKeys: iapi.NewPublicKeyRing(publicKey),
+			},
+		},
+		SkipCommandSync: true,
+	}
+
+	err := s.Initialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		s.Cleanup()
+	})
+
+	ts := httptest.NewServer(s)
+	t.Cleanup(ts.Close)
+
+	return ts
+}
+
+func TestClient_Send(t *testing.T) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ts := newTestServer(t, publicKey)
+
+	c := &Client{
+		URL: ts.URL + "/interactions",
+		Key: privateKey,
+	}
+
+	tests := []struct {
+		name        string
+		interaction *discordgo.Interaction
+		want        discordgo.InteractionResponseType
+	}{
+		{
+			name:        "ping",
+			interaction: DefaultContext.Ping(),
+			want:        discordgo.InteractionResponsePong,
+		},
+		{
+			name:        "command",
+			interaction: DefaultContext.Command(iapi.CmdPing),
+			want:        discordgo.InteractionResponseChannelMessageWithSource,
+		},
+		{
+			name:        "command with options",
+			interaction: DefaultContext.Command(iapi.CmdSettings, ParseOption(iapi.OptEphemeral, "true")),
+			want:        discordgo.InteractionResponseChannelMessageWithSource,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := c.Send(context.Background(), tt.interaction)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("resp.StatusCode = %d; want %d; body: %s", resp.StatusCode, http.StatusOK, resp.Pretty())
+			}
+
+			ir, err := resp.InteractionResponse()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if ir.Type != tt.want {
+				t.Fatalf("ir.Type = %d; want %d", ir.Type, tt.want)
+			}
+		})
+	}
+}
+
+func TestClient_Send_WrongKey(t *testing.T) {
+	publicKey, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, otherPrivateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ts := newTestServer(t, publicKey)
+
+	c := &Client{
+		URL: ts.URL + "/interactions",
+		Key: otherPrivateKey,
+	}
+
+	resp, err := c.Send(context.Background(), DefaultContext.Ping())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("resp.StatusCode = %d; want %d", resp.StatusCode, http.StatusUnauthorized)
+	}
+}
+
+func TestLoadOrGenerateKey(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dev.key")
+
+	key, generated, err := LoadOrGenerateKey(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !generated {
+		t.Fatal("generated = false; want true")
+	}
+
+	loaded, generated, err := LoadOrGenerateKey(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if generated {
+		t.Fatal("generated = true; want false")
+	}
+	if !key.Equal(loaded) {
+		t.Fatal("loaded key differs from generated key")
+	}
+
+	publicKeys, err := iapi.ReadPublicKeysFile(PublicKeyFilePath(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(publicKeys) != 1 || !publicKeys[0].Equal(key.Public()) {
+		t.Fatalf("public key file contains %v; want the public key of the generated key", publicKeys)
+	}
+}
@@ -0,0 +1,352 @@
+// Package statusboard draws the status of worlds as an image, with a grid of
+// data centers and their worlds coloured by state.
+//
+// Only the standard library is used. Text is drawn with a small bitmap font
+// that covers ASCII letters, digits and common punctuation, so the board is
+// always in English.
+package statusboard
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"slices"
+	"sync"
+
+	"github.com/c032/ffxiv-world-status-discord/assets"
+	"github.com/c032/ffxiv-world-status-discord/ffxivapi"
+)
+
+// ContentType is the content type of the encoded boards.
+const ContentType = "image/png"
+
+// Layout of the board, in pixels.
+const (
+	margin     = 24
+	sectionGap = 24
+
+	logoSize  = 72
+	logoGap   = 20
+	titleSize = 3
+
+	maxColumns  = 4
+	columnWidth = 208
+	columnGap   = 16
+	rowGap      = 24
+
+	groupNameScale = 2
+	groupNameGap   = 10
+
+	tileHeight   = 32
+	tileGap      = 6
+	tileBarWidth = 6
+	tilePadding  = 10
+	tileDotSize  = 8
+	nameScale    = 2
+
+	legendScale      = 2
+	legendSwatchSize = 14
+	legendSwatchGap  = 8
+	legendEntryGap   = 24
+	legendLineGap    = 10
+)
+
+// Colours of the board. The colours of states are the same used for the
+// embeds of responses.
+var (
+	colorBackground = rgb(0x2B2D31)
+	colorTile       = rgb(0x383A40)
+	colorText       = rgb(0xF2F3F5)
+	colorMutedText  = rgb(0xB5BAC1)
+
+	colorOnline      = rgb(0x57F287)
+	colorPreferred   = rgb(0x5865F2)
+	colorCongested   = rgb(0xFEE75C)
+	colorMaintenance = rgb(0xED4245)
+	colorOffline     = rgb(0x80848E)
+
+	colorCreationLocked = rgb(0xF0B232)
+	colorNew            = rgb(0xEB459E)
+)
+
+func rgb(v uint32) color.RGBA {
+	return color.RGBA{
+		R: uint8(v >> 16),
+		G: uint8(v >> 8),
+		B: uint8(v),
+		A: 0xFF,
+	}
+}
+
+// legendEntries explains the colours of the board, in the order they are
+// drawn.
+var legendEntries = []struct {
+	Label string
+	Color color.RGBA
+}{
+	{"Online", colorOnline},
+	{"Preferred", colorPreferred},
+	{"Congested", colorCongested},
+	{"Maintenance", colorMaintenance},
+	{"Offline", colorOffline},
+	{"Creation locked", colorCreationLocked},
+	{"New", colorNew},
+}
+
+// Options changes how the board is drawn.
+type Options struct {
+	// Title is drawn next to the logo.
+	Title string
+}
+
+// stateColors are the colours of the states of worlds.
+var stateColors = map[ffxivapi.State]color.RGBA{
+	ffxivapi.StateOffline:     colorOffline,
+	ffxivapi.StateOnline:      colorOnline,
+	ffxivapi.StatePreferred:   colorPreferred,
+	ffxivapi.StateCongested:   colorCongested,
+	ffxivapi.StateMaintenance: colorMaintenance,
+}
+
+// legendItem is an entry of the legend placed in the board.
+type legendItem struct {
+	At    image.Point
+	Label string
+	Color color.RGBA
+}
+
+// layoutLegend places the entries of the legend in lines of at most `width`
+// pixels, starting at the origin. It also returns the height of the legend.
+func layoutLegend(width int) ([]legendItem, int) {
+	lineHeight := max(legendSwatchSize, textHeight(legendScale))
+
+	var (
+		items []legendItem
+		x, y  int
+	)
+	for _, entry := range legendEntries {
+		entryWidth := legendSwatchSize + legendSwatchGap + textWidth(entry.Label, legendScale)
+		if x > 0 && x+entryWidth > width {
+			x = 0
+			y += lineHeight + legendLineGap
+		}
+
+		items = append(items, legendItem{
+			At:    image.Pt(x, y),
+			Label: entry.Label,
+			Color: entry.Color,
+		})
+
+		x += entryWidth + legendEntryGap
+	}
+
+	return items, y + lineHeight
+}
+
+// gridHeight returns the height of the rows of data centers in `groups`.
+func gridHeight(groups []ffxivapi.DataCenter) int {
+	var height int
+	for row := range slices.Chunk(groups, maxColumns) {
+		if height > 0 {
+			height += rowGap
+		}
+
+		var worlds int
+		for _, g := range row {
+			worlds = max(worlds, len(g.Worlds))
+		}
+
+		height += textHeight(groupNameScale) + groupNameGap + worlds*(tileHeight+tileGap) - tileGap
+	}
+
+	return height
+}
+
+// Render draws the board with the status of `worlds`.
+func Render(worlds []ffxivapi.World, opts Options) (*image.RGBA, error) {
+	logo, err := logo()
+	if err != nil {
+		return nil, err
+	}
+
+	groups := ffxivapi.GroupByDataCenter(worlds)
+
+	columns := max(1, min(len(groups), maxColumns))
+	contentWidth := max(
+		columns*columnWidth+(columns-1)*columnGap,
+		logoSize+logoGap+textWidth(opts.Title, titleSize),
+	)
+
+	legend, legendHeight := layoutLegend(contentWidth)
+
+	height := margin + logoSize + sectionGap
+	if len(groups) > 0 {
+		height += gridHeight(groups) + sectionGap
+	}
+	height += legendHeight + margin
+
+	dst := image.NewRGBA(image.Rect(0, 0, contentWidth+2*margin, height))
+	fillRect(dst, dst.Bounds(), colorBackground)
+
+	// Header.
+	draw.Draw(dst, image.Rect(margin, margin, margin+logoSize, margin+logoSize), logo, image.Point{}, draw.Over)
+	drawText(dst, image.Pt(margin+logoSize+logoGap, margin+(logoSize-textHeight(titleSize))/2), opts.Title, colorText, titleSize)
+
+	// Data centers.
+	y := margin + logoSize + sectionGap
+	for row := range slices.Chunk(groups, maxColumns) {
+		for i, g := range row {
+			drawGroup(dst, image.Pt(margin+i*(columnWidth+columnGap), y), g)
+		}
+
+		y += gridHeight(row) + rowGap
+	}
+	if len(groups) > 0 {
+		y += sectionGap - rowGap
+	}
+
+	// Legend.
+	lineHeight := max(legendSwatchSize, textHeight(legendScale))
+	for _, item := range legend {
+		at := item.At.Add(image.Pt(margin, y))
+
+		swatchY := at.Y + (lineHeight-legendSwatchSize)/2
+		fillRect(dst, image.Rect(at.X, swatchY, at.X+legendSwatchSize, swatchY+legendSwatchSize), item.Color)
+
+		textY := at.Y + (lineHeight-textHeight(legendScale))/2
+		drawText(dst, image.Pt(at.X+legendSwatchSize+legendSwatchGap, textY), item.Label, colorMutedText, legendScale)
+	}
+
+	return dst, nil
+}
+
+// drawGroup draws the name of `g` and a tile for each of its worlds, in a
+// column with its top left corner at `pt`.
+func drawGroup(dst *image.RGBA, pt image.Point, g ffxivapi.DataCenter) {
+	drawText(dst, pt, fitText(g.Name, columnWidth, groupNameScale), colorMutedText, groupNameScale)
+
+	y := pt.Y + textHeight(groupNameScale) + groupNameGap
+	for _, w := range g.Worlds {
+		tile := image.Rect(pt.X, y, pt.X+columnWidth, y+tileHeight)
+		fillRect(dst, tile, colorTile)
+
+		// The bar on the left shows the state of the world, and the one on
+		// the right whether characters can't be created.
+		fillRect(dst, image.Rect(tile.Min.X, tile.Min.Y, tile.Min.X+tileBarWidth, tile.Max.Y), stateColors[w.State()])
+		if !w.CanCreateNewCharacters {
+			fillRect(dst, image.Rect(tile.Max.X-tileBarWidth, tile.Min.Y, tile.Max.X, tile.Max.Y), colorCreationLocked)
+		}
+
+		nameX := tile.Min.X + tileBarWidth + tilePadding
+		nameWidth := tile.Max.X - tileBarWidth - tilePadding - nameX
+		if w.IsNew {
+			dotX := tile.Max.X - tileBarWidth - tilePadding - tileDotSize
+			dotY := tile.Min.Y + (tileHeight-tileDotSize)/2
+			fillRect(dst, image.Rect(dotX, dotY, dotX+tileDotSize, dotY+tileDotSize), colorNew)
+
+			nameWidth -= tileDotSize + tilePadding
+		}
+
+		nameY := tile.Min.Y + (tileHeight-textHeight(nameScale))/2
+		drawText(dst, image.Pt(nameX, nameY), fitText(w.Name, nameWidth, nameScale), colorText, nameScale)
+
+		y += tileHeight + tileGap
+	}
+}
+
+// EncodePNG draws the board with the status of `worlds` and writes it to `w`
+// as a PNG.
+func EncodePNG(w io.Writer, worlds []ffxivapi.World, opts Options) error {
+	img, err := Render(worlds, opts)
+	if err != nil {
+		return err
+	}
+
+	err = png.Encode(w, img)
+	if err != nil {
+		return fmt.Errorf("could not encode board: %w", err)
+	}
+
+	return nil
+}
+
+// fillRect fills `r` with `c`.
+func fillRect(dst *image.RGBA, r image.Rectangle, c color.RGBA) {
+	draw.Draw(dst, r, image.NewUniform(c), image.Point{}, draw.Src)
+}
+
+var (
+	logoOnce  sync.Once
+	logoImage *image.RGBA
+	logoErr   error
+)
+
+// logo returns the portrait of the fan kit scaled to `logoSize` and cropped
+// to a circle. It's only decoded once.
+func logo() (*image.RGBA, error) {
+	logoOnce.Do(func() {
+		src, err := png.Decode(bytes.NewReader(assets.FankitPortrait))
+		if err != nil {
+			logoErr = fmt.Errorf("could not decode logo: %w", err)
+
+			return
+		}
+
+		logoImage = scaleCircle(src, logoSize)
+	})
+
+	return logoImage, logoErr
+}
+
+// scaleCircle returns `src` scaled to a `size`×`size` square, averaging the
+// pixels that fall in each pixel of the result, and with the pixels outside of
+// the inscribed circle left transparent.
+func scaleCircle(src image.Image, size int) *image.RGBA {
+	b := src.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, size, size))
+
+	for y := 0; y < size; y++ {
+		y0 := b.Min.Y + y*b.Dy()/size
+		y1 := b.Min.Y + (y+1)*b.Dy()/size
+
+		for x := 0; x < size; x++ {
+			dx := 2*x + 1 - size
+			dy := 2*y + 1 - size
+			if dx*dx+dy*dy > size*size {
+				continue
+			}
+
+			x0 := b.Min.X + x*b.Dx()/size
+			x1 := b.Min.X + (x+1)*b.Dx()/size
+
+			var r, g, bl, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					cr, cg, cb, ca := src.At(sx, sy).RGBA()
+
+					r += uint64(cr)
+					g += uint64(cg)
+					bl += uint64(cb)
+					a += uint64(ca)
+					n++
+				}
+			}
+			if n == 0 {
+				continue
+			}
+
+			dst.SetRGBA(x, y, color.RGBA{
+				R: uint8(r / n >> 8),
+				G: uint8(g / n >> 8),
+				B: uint8(bl / n >> 8),
+				A: uint8(a / n >> 8),
+			})
+		}
+	}
+
+	return dst
+}
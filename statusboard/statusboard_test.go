@@ -0,0 +1,123 @@
+package statusboard_test
+
+import (
+	"bytes"
+	"flag"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/c032/ffxiv-world-status-discord/ffxivapi"
+	"github.com/c032/ffxiv-world-status-discord/statusboard"
+)
+
+var updateGolden = flag.Bool("update", false, "update the golden images")
+
+var testWorlds = []ffxivapi.World{
+	{Group: "Light", Name: "Lich", IsOnline: true, IsCongested: true},
+	{Group: "Light", Name: "Odin", IsOnline: true, IsCongested: true},
+	{Group: "Light", Name: "Alpha", IsOnline: true, CanCreateNewCharacters: true, IsNew: true},
+	{Group: "Chaos", Name: "Omega", IsMaintenance: true, CanCreateNewCharacters: true},
+	{Group: "Aether", Name: "Gilgamesh", IsMaintenance: true},
+	{Group: "Aether", Name: "Adamantoise", IsOnline: true, IsPreferred: true, CanCreateNewCharacters: true},
+	{Group: "Elemental", Name: "Tonberry", IsOnline: true, CanCreateNewCharacters: true},
+	{Group: "Materia", Name: "Bismarck", CanCreateNewCharacters: true},
+	{Group: "Unknown", Name: "A world with a very long name", IsOnline: true, CanCreateNewCharacters: true},
+}
+
+// assertGoldenImage compares `img` with the PNG `name` in `testdata/golden`,
+// pixel by pixel. With `-update`, the file is replaced instead.
+func assertGoldenImage(t *testing.T, name string, img image.Image) {
+	t.Helper()
+
+	path := filepath.Join("testdata", "golden", name+".png")
+
+	if *updateGolden {
+		var buf bytes.Buffer
+
+		err := png.Encode(&buf, img)
+		if err == nil {
+			err = os.MkdirAll(filepath.Dir(path), 0o755)
+		}
+		if err == nil {
+			err = os.WriteFile(path, buf.Bytes(), 0o644)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("could not open golden image (run with -update to create it): %s", err)
+	}
+	defer f.Close()
+
+	want, err := png.Decode(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if img.Bounds() != want.Bounds() {
+		t.Fatalf("bounds = %v; want %v (run with -update to replace the golden image)", img.Bounds(), want.Bounds())
+	}
+
+	b := img.Bounds()
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			got := color.NRGBAModel.Convert(img.At(x, y))
+			if wantColor := color.NRGBAModel.Convert(want.At(x, y)); got != wantColor {
+				t.Fatalf("pixel (%d, %d) = %v; want %v (run with -update to replace the golden image)", x, y, got, wantColor)
+			}
+		}
+	}
+}
+
+func TestRender(t *testing.T) {
+	img, err := statusboard.Render(testWorlds, statusboard.Options{
+		Title: "FFXIV world status",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertGoldenImage(t, "board", img)
+}
+
+func TestRender_SingleDataCenter(t *testing.T) {
+	var worlds []ffxivapi.World
+	for _, w := range testWorlds {
+		if w.Group == "Light" {
+			worlds = append(worlds, w)
+		}
+	}
+
+	img, err := statusboard.Render(worlds, statusboard.Options{
+		Title: "Light",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertGoldenImage(t, "single_data_center", img)
+}
+
+func TestEncodePNG_Deterministic(t *testing.T) {
+	var first, second bytes.Buffer
+
+	for _, buf := range []*bytes.Buffer{&first, &second} {
+		err := statusboard.EncodePNG(buf, testWorlds, statusboard.Options{})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if !bytes.Equal(first.Bytes(), second.Bytes()) {
+		t.Error("encoding the same board twice returned different images")
+	}
+}
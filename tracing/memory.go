@@ -0,0 +1,31 @@
+package tracing
+
+import (
+	"context"
+	"sync"
+)
+
+var _ Exporter = (*InMemoryExporter)(nil)
+
+// InMemoryExporter keeps exported spans in memory. It's meant for tests.
+type InMemoryExporter struct {
+	mu    sync.Mutex
+	spans []SpanData
+}
+
+func (e *InMemoryExporter) ExportSpans(ctx context.Context, spans []SpanData) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.spans = append(e.spans, spans...)
+
+	return nil
+}
+
+// Spans returns a copy of every exported span, in the order they ended.
+func (e *InMemoryExporter) Spans() []SpanData {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return append([]SpanData(nil), e.spans...)
+}
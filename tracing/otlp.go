@@ -0,0 +1,239 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var _ Exporter = (*OTLPExporter)(nil)
+
+// DefaultOTLPTimeout limits how long sending a batch of spans can take when
+// `OTLPExporter.Client` is not set, so a stalled collector doesn't block
+// flushes.
+const DefaultOTLPTimeout = 10 * time.Second
+
+var defaultOTLPClient = &http.Client{
+	Timeout: DefaultOTLPTimeout,
+}
+
+// OTLPExporter sends spans to an OpenTelemetry collector, using OTLP over
+// HTTP with JSON encoding.
+type OTLPExporter struct {
+	// Endpoint is the base URL of the collector, e.g.
+	// `http://localhost:4318`. Spans are sent to `/v1/traces`.
+	Endpoint string
+
+	// ServiceName is reported as the `service.name` resource attribute.
+	ServiceName string
+
+	// Headers are added to every request, e.g. for authentication.
+	Headers map[string]string
+
+	// Client defaults to a client with a timeout of
+	// `DefaultOTLPTimeout`.
+	Client *http.Client
+}
+
+type otlpRequest struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpResource struct {
+	Attributes []otlpAttribute `json:"attributes"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope  `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpSpan struct {
+	TraceID           string          `json:"traceId"`
+	SpanID            string          `json:"spanId"`
+	ParentSpanID      string          `json:"parentSpanId,omitempty"`
+	Name              string          `json:"name"`
+	Kind              int             `json:"kind"`
+	StartTimeUnixNano string          `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string          `json:"endTimeUnixNano"`
+	Attributes        []otlpAttribute `json:"attributes,omitempty"`
+	Status            otlpStatus      `json:"status"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code"`
+	Message string `json:"message,omitempty"`
+}
+
+type otlpAttribute struct {
+	Key   string    `json:"key"`
+	Value otlpValue `json:"value"`
+}
+
+type otlpValue struct {
+	StringValue *string  `json:"stringValue,omitempty"`
+	IntValue    *string  `json:"intValue,omitempty"`
+	DoubleValue *float64 `json:"doubleValue,omitempty"`
+	BoolValue   *bool    `json:"boolValue,omitempty"`
+}
+
+// OTLP span kinds and status codes.
+const (
+	otlpSpanKindInternal = 1
+	otlpSpanKindServer   = 2
+	otlpSpanKindClient   = 3
+
+	otlpStatusCodeUnset = 0
+	otlpStatusCodeError = 2
+)
+
+func toOTLPValue(v any) otlpValue {
+	switch v := v.(type) {
+	case string:
+		return otlpValue{StringValue: &v}
+	case int64:
+		s := strconv.FormatInt(v, 10)
+
+		return otlpValue{IntValue: &s}
+	case int:
+		s := strconv.Itoa(v)
+
+		return otlpValue{IntValue: &s}
+	case float64:
+		return otlpValue{DoubleValue: &v}
+	case bool:
+		return otlpValue{BoolValue: &v}
+	}
+
+	s := fmt.Sprint(v)
+
+	return otlpValue{StringValue: &s}
+}
+
+func toOTLPAttributes(attributes []Attribute) []otlpAttribute {
+	var result []otlpAttribute
+	for _, attr := range attributes {
+		result = append(result, otlpAttribute{
+			Key:   attr.Key,
+			Value: toOTLPValue(attr.Value),
+		})
+	}
+
+	return result
+}
+
+func toOTLPSpan(data SpanData) otlpSpan {
+	span := otlpSpan{
+		TraceID:           data.TraceID.String(),
+		SpanID:            data.SpanID.String(),
+		Name:              data.Name,
+		Kind:              otlpSpanKindInternal,
+		StartTimeUnixNano: strconv.FormatInt(data.Start.UnixNano(), 10),
+		EndTimeUnixNano:   strconv.FormatInt(data.End.UnixNano(), 10),
+		Attributes:        toOTLPAttributes(data.Attributes),
+
+		// Successful spans are left unset, as recommended for
+		// instrumentation; only errors are reported.
+		Status: otlpStatus{
+			Code: otlpStatusCodeUnset,
+		},
+	}
+
+	if data.ParentSpanID.IsValid() {
+		span.ParentSpanID = data.ParentSpanID.String()
+	}
+
+	switch data.Kind {
+	case SpanKindServer:
+		span.Kind = otlpSpanKindServer
+	case SpanKindClient:
+		span.Kind = otlpSpanKindClient
+	}
+
+	if data.Err != "" {
+		span.Status = otlpStatus{
+			Code:    otlpStatusCodeError,
+			Message: data.Err,
+		}
+	}
+
+	return span
+}
+
+func (e *OTLPExporter) ExportSpans(ctx context.Context, spans []SpanData) error {
+	otlpSpans := make([]otlpSpan, 0, len(spans))
+	for _, span := range spans {
+		otlpSpans = append(otlpSpans, toOTLPSpan(span))
+	}
+
+	payload := otlpRequest{
+		ResourceSpans: []otlpResourceSpans{
+			{
+				Resource: otlpResource{
+					Attributes: toOTLPAttributes([]Attribute{
+						String("service.name", e.ServiceName),
+					}),
+				},
+				ScopeSpans: []otlpScopeSpans{
+					{
+						Scope: otlpScope{
+							Name: "github.com/c032/ffxiv-world-status-discord/tracing",
+						},
+						Spans: otlpSpans,
+					},
+				},
+			},
+		},
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("could not encode spans: %w", err)
+	}
+
+	urlStr := strings.TrimSuffix(e.Endpoint, "/") + "/v1/traces"
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, urlStr, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("could not create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range e.Headers {
+		req.Header.Set(key, value)
+	}
+
+	c := e.Client
+	if c == nil {
+		c = defaultOTLPClient
+	}
+
+	resp, err := c.Do(req)
+	if err != nil {
+		return fmt.Errorf("could not send spans: %w", err)
+	}
+	defer resp.Body.Close()
+
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("collector responded with status %d", resp.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,331 @@
+// Package tracing implements a minimal tracer, compatible with the
+// OpenTelemetry data model, that records spans and exports them in batches.
+//
+// A `nil` `*Tracer` is valid, and creates spans that record nothing, so
+// tracing can be disabled by not configuring a tracer.
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+
+	logger "github.com/c032/go-logger"
+)
+
+const (
+	DefaultMaxQueueSize  = 2048
+	DefaultFlushInterval = 5 * time.Second
+)
+
+type TraceID [16]byte
+
+func (id TraceID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+func (id TraceID) IsValid() bool {
+	return id != TraceID{}
+}
+
+type SpanID [8]byte
+
+func (id SpanID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+func (id SpanID) IsValid() bool {
+	return id != SpanID{}
+}
+
+type SpanKind int
+
+const (
+	SpanKindInternal SpanKind = iota
+	SpanKindServer
+	SpanKindClient
+)
+
+// Attribute is a key-value pair attached to a span. Values can be strings,
+// integers, floats or booleans.
+type Attribute struct {
+	Key   string
+	Value any
+}
+
+func String(key string, value string) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+func Int(key string, value int) Attribute {
+	return Attribute{Key: key, Value: int64(value)}
+}
+
+func Bool(key string, value bool) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// SpanData is a finished span.
+type SpanData struct {
+	Name string
+	Kind SpanKind
+
+	TraceID      TraceID
+	SpanID       SpanID
+	ParentSpanID SpanID
+
+	Start time.Time
+	End   time.Time
+
+	Attributes []Attribute
+
+	// Err is the error message of failed spans.
+	Err string
+}
+
+// Exporter sends finished spans somewhere, e.g. to a collector.
+type Exporter interface {
+	ExportSpans(ctx context.Context, spans []SpanData) error
+}
+
+type TracerOptions struct {
+	// ServiceName identifies the process in the exported spans.
+	ServiceName string
+
+	// MaxQueueSize is the maximum number of finished spans waiting to be
+	// exported. When it's reached, new spans are dropped. Defaults to
+	// `DefaultMaxQueueSize`.
+	MaxQueueSize int
+
+	// FlushInterval is how often `Run` exports the finished spans. Defaults
+	// to `DefaultFlushInterval`.
+	FlushInterval time.Duration
+
+	Logger logger.Logger
+}
+
+// Tracer creates spans, and exports them with an `Exporter` once they end.
+type Tracer struct {
+	exporter Exporter
+	options  TracerOptions
+
+	mu      sync.Mutex
+	queue   []SpanData
+	dropped int
+
+	// exportMutex ensures spans are exported by one caller at a time.
+	exportMutex sync.Mutex
+}
+
+func NewTracer(exporter Exporter, options TracerOptions) *Tracer {
+	if options.MaxQueueSize <= 0 {
+		options.MaxQueueSize = DefaultMaxQueueSize
+	}
+
+	if options.FlushInterval <= 0 {
+		options.FlushInterval = DefaultFlushInterval
+	}
+
+	return &Tracer{
+		exporter: exporter,
+		options:  options,
+	}
+}
+
+func (t *Tracer) logger() logger.Logger {
+	if t.options.Logger == nil {
+		return logger.Discard
+	}
+
+	return t.options.Logger
+}
+
+type spanContextKey struct{}
+
+// SpanFromContext returns the span of `ctx`, or `nil` if there is none.
+func SpanFromContext(ctx context.Context) *Span {
+	span, _ := ctx.Value(spanContextKey{}).(*Span)
+
+	return span
+}
+
+// Start creates a span, as a child of the span of `ctx` if there is one. The
+// returned context contains the new span.
+//
+// The span must be ended with `End`.
+func (t *Tracer) Start(ctx context.Context, name string, attributes ...Attribute) (context.Context, *Span) {
+	if t == nil {
+		return ctx, nil
+	}
+
+	span := &Span{
+		tracer: t,
+		data: SpanData{
+			Name:       name,
+			Start:      time.Now(),
+			Attributes: append([]Attribute(nil), attributes...),
+		},
+	}
+
+	_, _ = rand.Read(span.data.SpanID[:])
+
+	if parent := SpanFromContext(ctx); parent != nil {
+		span.data.TraceID = parent.data.TraceID
+		span.data.ParentSpanID = parent.data.SpanID
+	} else {
+		_, _ = rand.Read(span.data.TraceID[:])
+	}
+
+	return context.WithValue(ctx, spanContextKey{}, span), span
+}
+
+func (t *Tracer) enqueue(data SpanData) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if len(t.queue) >= t.options.MaxQueueSize {
+		t.dropped++
+
+		return
+	}
+
+	t.queue = append(t.queue, data)
+}
+
+// Flush exports every finished span.
+func (t *Tracer) Flush(ctx context.Context) error {
+	if t == nil {
+		return nil
+	}
+
+	t.exportMutex.Lock()
+	defer t.exportMutex.Unlock()
+
+	t.mu.Lock()
+	spans := t.queue
+	dropped := t.dropped
+	t.queue = nil
+	t.dropped = 0
+	t.mu.Unlock()
+
+	if dropped > 0 {
+		t.logger().Errorf("Dropped %d spans because the queue was full.", dropped)
+	}
+
+	if len(spans) == 0 {
+		return nil
+	}
+
+	err := t.exporter.ExportSpans(ctx, spans)
+	if err != nil {
+		return fmt.Errorf("could not export %d spans: %w", len(spans), err)
+	}
+
+	return nil
+}
+
+// Run exports the finished spans every `FlushInterval`, until `ctx` is done.
+//
+// Spans that end after `ctx` is done must be exported by calling `Flush`.
+func (t *Tracer) Run(ctx context.Context) {
+	if t == nil {
+		return
+	}
+
+	log := t.logger()
+
+	ticker := time.NewTicker(t.options.FlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		err := t.Flush(ctx)
+		if err != nil {
+			log.Errorf("Could not export spans: %s", err.Error())
+		}
+	}
+}
+
+// Span is an operation being traced.
+//
+// Methods of a `nil` `*Span` do nothing.
+type Span struct {
+	tracer *Tracer
+
+	mu    sync.Mutex
+	data  SpanData
+	ended bool
+}
+
+// TraceID returns the ID of the trace of the span.
+func (span *Span) TraceID() TraceID {
+	if span == nil {
+		return TraceID{}
+	}
+
+	return span.data.TraceID
+}
+
+func (span *Span) SetKind(kind SpanKind) {
+	if span == nil {
+		return
+	}
+
+	span.mu.Lock()
+	defer span.mu.Unlock()
+
+	span.data.Kind = kind
+}
+
+func (span *Span) SetAttributes(attributes ...Attribute) {
+	if span == nil {
+		return
+	}
+
+	span.mu.Lock()
+	defer span.mu.Unlock()
+
+	span.data.Attributes = append(span.data.Attributes, attributes...)
+}
+
+// SetError marks the span as failed, if `err` is not `nil`.
+func (span *Span) SetError(err error) {
+	if span == nil || err == nil {
+		return
+	}
+
+	span.mu.Lock()
+	defer span.mu.Unlock()
+
+	span.data.Err = err.Error()
+}
+
+// End finishes the span, and queues it to be exported. Calls after the first
+// one do nothing.
+func (span *Span) End() {
+	if span == nil {
+		return
+	}
+
+	span.mu.Lock()
+	if span.ended {
+		span.mu.Unlock()
+
+		return
+	}
+
+	span.ended = true
+	span.data.End = time.Now()
+	data := span.data
+	span.mu.Unlock()
+
+	span.tracer.enqueue(data)
+}
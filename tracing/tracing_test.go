@@ -0,0 +1,116 @@
+package tracing_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/c032/ffxiv-world-status-discord/tracing"
+)
+
+func TestTracer_ParentChild(t *testing.T) {
+	exporter := &tracing.InMemoryExporter{}
+	tracer := tracing.NewTracer(exporter, tracing.TracerOptions{})
+
+	ctx, parent := tracer.Start(context.Background(), "parent")
+	_, child := tracer.Start(ctx, "child", tracing.String("key", "value"))
+	child.SetError(errors.New("test"))
+	child.End()
+	parent.End()
+	parent.End()
+
+	err := tracer.Flush(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	spans := exporter.Spans()
+	if got, want := len(spans), 2; got != want {
+		t.Fatalf("len(spans) = %d; want %d", got, want)
+	}
+
+	childData, parentData := spans[0], spans[1]
+	if got, want := childData.TraceID, parentData.TraceID; got != want {
+		t.Errorf("child trace ID = %s; want %s", got, want)
+	}
+	if got, want := childData.ParentSpanID, parentData.SpanID; got != want {
+		t.Errorf("child parent span ID = %s; want %s", got, want)
+	}
+	if parentData.ParentSpanID.IsValid() {
+		t.Errorf("parent has parent span ID %s; want none", parentData.ParentSpanID)
+	}
+	if got, want := childData.Err, "test"; got != want {
+		t.Errorf("child error = %#v; want %#v", got, want)
+	}
+}
+
+func TestTracer_Nil(t *testing.T) {
+	var tracer *tracing.Tracer
+
+	ctx, span := tracer.Start(context.Background(), "test")
+	span.SetAttributes(tracing.Int("key", 1))
+	span.End()
+
+	if tracing.SpanFromContext(ctx) != nil {
+		t.Errorf("nil tracer stored a span in the context")
+	}
+}
+
+func TestOTLPExporter(t *testing.T) {
+	var body map[string]any
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if got, want := req.URL.Path, "/v1/traces"; got != want {
+			t.Errorf("path = %#v; want %#v", got, want)
+		}
+
+		raw, _ := io.ReadAll(req.Body)
+		_ = json.Unmarshal(raw, &body)
+	}))
+	defer ts.Close()
+
+	tracer := tracing.NewTracer(&tracing.OTLPExporter{
+		Endpoint:    ts.URL,
+		ServiceName: "test",
+	}, tracing.TracerOptions{})
+
+	_, span := tracer.Start(context.Background(), "test", tracing.Int("attempt", 2))
+	span.End()
+
+	_, failed := tracer.Start(context.Background(), "failed")
+	failed.SetError(errors.New("test"))
+	failed.End()
+
+	err := tracer.Flush(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resourceSpans := body["resourceSpans"].([]any)[0].(map[string]any)
+	scopeSpans := resourceSpans["scopeSpans"].([]any)[0].(map[string]any)
+	spans := scopeSpans["spans"].([]any)
+	if got, want := len(spans), 2; got != want {
+		t.Fatalf("len(spans) = %d; want %d", got, want)
+	}
+
+	s := spans[0].(map[string]any)
+	if got, want := s["name"], "test"; got != want {
+		t.Errorf("name = %#v; want %#v", got, want)
+	}
+	if got, want := len(s["traceId"].(string)), 32; got != want {
+		t.Errorf("len(traceId) = %d; want %d", got, want)
+	}
+
+	// Successful spans have an unset status, and failed spans an error.
+	if got, want := s["status"].(map[string]any)["code"], float64(0); got != want {
+		t.Errorf("status code of successful span = %#v; want %#v", got, want)
+	}
+
+	failedSpan := spans[1].(map[string]any)
+	if got, want := failedSpan["status"].(map[string]any)["code"], float64(2); got != want {
+		t.Errorf("status code of failed span = %#v; want %#v", got, want)
+	}
+}